@@ -0,0 +1,119 @@
+package actions
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/metal-toolbox/ironlib/model"
+	"github.com/metal-toolbox/ironlib/utils"
+)
+
+var ErrBIOSConfigurationEmpty = errors.New("expected one or more BIOS settings to apply")
+
+// SetBIOSConfiguration applies the given vendor native BIOS settings through the setter,
+// settings that already have the requested value are skipped.
+//
+// Once applied the BIOS configuration is read back to identify the settings that took effect,
+// the settings not reflected in the read back are returned as pending.
+func SetBIOSConfiguration(ctx context.Context, setter BIOSConfigurationSetter, deviceModel string, config map[string]string) (*model.BIOSConfigurationChanges, error) {
+	if len(config) == 0 {
+		return nil, ErrBIOSConfigurationEmpty
+	}
+
+	current, err := setter.GetBIOSConfiguration(ctx, deviceModel)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading BIOS configuration before applying changes")
+	}
+
+	changes := model.NewBIOSConfigurationChanges()
+	write := map[string]string{}
+
+	for k, v := range config {
+		if biosSettingMatches(current, k, v) {
+			changes.Unchanged[k] = v
+			continue
+		}
+
+		write[k] = v
+	}
+
+	if len(write) == 0 {
+		return changes, nil
+	}
+
+	if err := setter.SetBIOSConfiguration(ctx, deviceModel, write); err != nil {
+		return nil, err
+	}
+
+	readBack, err := setter.GetBIOSConfiguration(ctx, deviceModel)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading back BIOS configuration after applying changes")
+	}
+
+	for k, v := range write {
+		if biosSettingMatches(readBack, k, v) {
+			changes.Applied[k] = v
+			continue
+		}
+
+		changes.Pending[k] = v
+	}
+
+	return changes, nil
+}
+
+// BIOSConfigDiff compares the desired BIOS configuration with the current configuration returned by the device manager.
+//
+// The desired settings may be normalized keys (smt, secure_boot..), "raw:" prefixed or vendor native settings,
+// allowing one desired configuration to be compared across vendors.
+func BIOSConfigDiff(ctx context.Context, dm DeviceManager, desired map[string]string) (*model.BIOSConfigurationDiff, error) {
+	current, err := dm.GetBIOSConfiguration(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading BIOS configuration")
+	}
+
+	report := model.NewBIOSConfigurationDiff()
+
+	for k, v := range desired {
+		nKey, nValue := utils.NormalizeBIOSSetting(k, v)
+
+		currentValue, exists := current[nKey]
+		switch {
+		case !exists && utils.IsNormalizedBIOSKey(nKey):
+			report.Missing[k] = v
+		case !exists:
+			report.Unknown[k] = v
+		case biosValueEqual(currentValue, nValue):
+			report.Matching[k] = v
+		default:
+			report.Differing[k] = model.BIOSSettingDiff{Desired: v, Current: currentValue}
+		}
+	}
+
+	return report, nil
+}
+
+// biosSettingMatches returns true when the given vendor native setting has the same value
+// in the normalized BIOS configuration.
+func biosSettingMatches(normalizedCfg map[string]string, key, value string) bool {
+	// normalize the setting the same way the BIOS configuration collectors do
+	nKey, nValue := utils.NormalizeBIOSSetting(key, value)
+
+	// settings dropped by the normalizer (passwords) can't be compared
+	if nKey == "" {
+		return false
+	}
+
+	current, exists := normalizedCfg[nKey]
+	if !exists {
+		return false
+	}
+
+	return biosValueEqual(current, nValue)
+}
+
+func biosValueEqual(a, b string) bool {
+	return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+}
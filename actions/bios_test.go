@@ -0,0 +1,124 @@
+package actions
+
+import (
+	"context"
+	"maps"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/metal-toolbox/ironlib/model"
+	"github.com/metal-toolbox/ironlib/utils"
+)
+
+// fakeBIOSConfigurationSetter holds vendor BIOS settings, settings listed in deferred are
+// accepted but not reflected in the configuration until a reboot.
+type fakeBIOSConfigurationSetter struct {
+	cfg      map[string]string
+	deferred map[string]bool
+	written  map[string]string
+}
+
+func (f *fakeBIOSConfigurationSetter) GetBIOSConfiguration(context.Context, string) (map[string]string, error) {
+	return utils.NormalizeBIOSConfiguration(f.cfg), nil
+}
+
+func (f *fakeBIOSConfigurationSetter) SetBIOSConfiguration(_ context.Context, _ string, cfg map[string]string) error {
+	f.written = maps.Clone(cfg)
+
+	for k, v := range cfg {
+		if f.deferred[k] {
+			continue
+		}
+
+		f.cfg[k] = v
+	}
+
+	return nil
+}
+
+func Test_SetBIOSConfiguration(t *testing.T) {
+	setter := &fakeBIOSConfigurationSetter{
+		cfg: map[string]string{
+			"LogicalProc":       "Enabled",
+			"SriovGlobalEnable": "Disabled",
+			"BootMode":          "Uefi",
+			"MemTest":           "Disabled",
+		},
+		deferred: map[string]bool{"BootMode": true},
+	}
+
+	changes, err := SetBIOSConfiguration(context.TODO(), setter, "", map[string]string{
+		"LogicalProc":       "Enabled",
+		"SriovGlobalEnable": "Enabled",
+		"BootMode":          "Bios",
+		"MemTest":           "Enabled",
+	})
+	assert.NoError(t, err)
+
+	expected := &model.BIOSConfigurationChanges{
+		Applied:   map[string]string{"SriovGlobalEnable": "Enabled", "MemTest": "Enabled"},
+		Pending:   map[string]string{"BootMode": "Bios"},
+		Unchanged: map[string]string{"LogicalProc": "Enabled"},
+	}
+
+	assert.Equal(t, expected, changes)
+	// settings with the requested value are not written
+	assert.Equal(t, map[string]string{"SriovGlobalEnable": "Enabled", "BootMode": "Bios", "MemTest": "Enabled"}, setter.written)
+
+	_, err = SetBIOSConfiguration(context.TODO(), setter, "", nil)
+	assert.ErrorIs(t, err, ErrBIOSConfigurationEmpty)
+}
+
+// fakeDeviceManager returns the BIOS configuration as a provider would
+type fakeDeviceManager struct {
+	DeviceManager
+	cfg map[string]string
+}
+
+func (f *fakeDeviceManager) GetBIOSConfiguration(context.Context) (map[string]string, error) {
+	return f.cfg, nil
+}
+
+func Test_BIOSConfigDiff(t *testing.T) {
+	desired := map[string]string{
+		"smt":               "Disabled",
+		"boot_mode":         "Bios",
+		"tpm":               "On",
+		"intel_sgx":         "Enabled",
+		"raw:MemTest":       "Disabled",
+		"SriovGlobalEnable": "enable",
+		"raw:Foo":           "Bar",
+	}
+
+	// a Dell BIOS configuration as returned by GetBIOSConfiguration
+	dm := &fakeDeviceManager{
+		cfg: utils.NormalizeBIOSConfiguration(map[string]string{
+			"LogicalProc":       "Enabled",
+			"BootMode":          "Bios",
+			"TpmSecurity":       "On",
+			"MemTest":           "Disabled",
+			"SriovGlobalEnable": "Enabled",
+		}),
+	}
+
+	got, err := BIOSConfigDiff(context.TODO(), dm, desired)
+	assert.NoError(t, err)
+
+	expected := &model.BIOSConfigurationDiff{
+		Matching: map[string]string{
+			"boot_mode":         "Bios",
+			"tpm":               "On",
+			"raw:MemTest":       "Disabled",
+			"SriovGlobalEnable": "enable",
+		},
+		Differing: map[string]model.BIOSSettingDiff{
+			"smt": {Desired: "Disabled", Current: "Enabled"},
+		},
+		Missing: map[string]string{"intel_sgx": "Enabled"},
+		Unknown: map[string]string{"raw:Foo": "Bar"},
+	}
+
+	assert.Equal(t, expected, got)
+	assert.False(t, got.InSync())
+}
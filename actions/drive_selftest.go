@@ -0,0 +1,261 @@
+package actions
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	common "github.com/metal-toolbox/bmc-common"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/metal-toolbox/ironlib/model"
+	"github.com/metal-toolbox/ironlib/utils"
+)
+
+var (
+	ErrDriveSelfTestsFailed     = errors.New("drive self-tests failed")
+	ErrDriveSelfTestInProgress  = errors.New("a self-test is already in progress on the drive")
+	ErrDriveSelfTestNotStarted  = errors.New("drive self-test was not started")
+	ErrDriveSelfTestNoLogEntry  = errors.New("drive self-test log has no entry for the self-test")
+	ErrDriveSelfTestUnsupported = errors.New("drive self-test type not supported")
+)
+
+const (
+	defaultSelfTestPollInterval = 30 * time.Second
+	// selfTestStartPolls is the number of polls a self-test has to show up as in progress or completed in the drive self-test log.
+	selfTestStartPolls = 3
+	// selfTestAbortTimeout is the time allowed to abort a self-test once the context is canceled.
+	selfTestAbortTimeout = 30 * time.Second
+)
+
+// DriveSelfTestResult is the result of a drive self-test in a batch
+type DriveSelfTestResult struct {
+	Drive   *common.Drive
+	Type    model.DriveSelfTestType
+	Started time.Time
+	Ended   time.Time
+	// Passed is set when the self-test completed without error.
+	Passed bool
+	// Status is the self-test result as described by the drive.
+	Status string
+	// FailingLBA is the first logical block address the self-test failed on, this is nil when not reported.
+	FailingLBA *uint64
+	// Error is set when the self-test could not be run or its result not determined.
+	Error error
+}
+
+// SelfTestDrives runs a self-test on the drives concurrently, the results are returned in the same order as the drives.
+//
+// The self-test is run with the utility returned by GetSelfTestUtility and its progress polled until it completes,
+// when the context is canceled the running self-tests are aborted. ErrDriveSelfTestsFailed is returned
+// along with the results once all drives have been tested if any drive did not pass its self-test.
+func (s *StorageControllerAction) SelfTestDrives(ctx context.Context, drives []*common.Drive, options *model.DriveSelfTestOptions) ([]*DriveSelfTestResult, error) {
+	return selfTestDrives(ctx, s.Logger, drives, options, s.GetSelfTestUtility)
+}
+
+// GetSelfTestUtility returns the self-test utility for the drive based on its protocol,
+// NVMe drives are tested with nvme-cli and other drives with smartctl.
+func (s *StorageControllerAction) GetSelfTestUtility(drive *common.Drive) DriveSelfTester {
+	if strings.EqualFold(drive.Protocol, "nvme") || strings.HasPrefix(drive.LogicalName, "/dev/nvme") {
+		return utils.NewNvmeCmd(s.trace)
+	}
+
+	return utils.NewSmartctlCmd(s.trace)
+}
+
+func selfTestDrives(ctx context.Context, logger *logrus.Logger, drives []*common.Drive, options *model.DriveSelfTestOptions, testerFor func(*common.Drive) DriveSelfTester) ([]*DriveSelfTestResult, error) {
+	if options == nil {
+		options = &model.DriveSelfTestOptions{}
+	}
+
+	testType := options.Type
+	if testType == "" {
+		testType = model.DriveSelfTestShort
+	}
+
+	pollInterval := options.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = defaultSelfTestPollInterval
+	}
+
+	concurrency := options.Concurrency
+	if concurrency <= 0 || concurrency > len(drives) {
+		concurrency = len(drives)
+	}
+
+	sem := make(chan struct{}, max(concurrency, 1))
+	results := make([]*DriveSelfTestResult, len(drives))
+
+	var wg sync.WaitGroup
+	for idx, drive := range drives {
+		result := &DriveSelfTestResult{Drive: drive, Type: testType}
+		results[idx] = result
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if !acquire(ctx, sem) {
+				result.Error = ctx.Err()
+				return
+			}
+			defer func() { <-sem }()
+
+			selfTestBatchDrive(ctx, logger, result, pollInterval, testerFor(drive))
+		}()
+	}
+
+	wg.Wait()
+
+	failed := []string{}
+	for _, result := range results {
+		switch {
+		case result.Error != nil:
+			failed = append(failed, result.Drive.LogicalName+": "+result.Error.Error())
+		case !result.Passed:
+			failed = append(failed, result.Drive.LogicalName+": "+result.Status)
+		}
+	}
+
+	if len(failed) > 0 {
+		return results, errors.Wrap(ErrDriveSelfTestsFailed, strings.Join(failed, "; "))
+	}
+
+	return results, nil
+}
+
+// selfTestBatchDrive runs the self-test on the result drive and sets the result fields
+func selfTestBatchDrive(ctx context.Context, logger *logrus.Logger, result *DriveSelfTestResult, pollInterval time.Duration, tester DriveSelfTester) {
+	l := logger.WithField("drive", result.Drive.LogicalName).WithField("type", result.Type)
+
+	l.Info("running drive self-test")
+
+	result.Started = time.Now().UTC()
+	entry, err := selfTestDrive(ctx, l, tester, result.Drive.LogicalName, result.Type, pollInterval)
+	result.Ended = time.Now().UTC()
+
+	if err != nil {
+		result.Error = err
+		l.WithError(err).Warn("drive self-test error")
+
+		return
+	}
+
+	result.Passed = entry.Passed
+	result.Status = entry.Status
+	result.FailingLBA = entry.FailingLBA
+
+	l = l.WithField("status", entry.Status).WithField("duration", result.Ended.Sub(result.Started).String())
+	if !entry.Passed {
+		if entry.FailingLBA != nil {
+			l = l.WithField("failing-lba", *entry.FailingLBA)
+		}
+
+		l.Warn("drive self-test failed")
+
+		return
+	}
+
+	l.Info("drive self-test passed")
+}
+
+// selfTestDrive starts a self-test on the drive and polls the drive self-test status until the self-test completes,
+// the self-test log entry of the completed self-test is returned.
+//
+// The self-test is identified as completed once it is no longer in progress and a self-test log entry was added
+// since the self-test was started, see newSelfTestLogEntry.
+func selfTestDrive(ctx context.Context, l *logrus.Entry, tester DriveSelfTester, logicalName string, testType model.DriveSelfTestType, pollInterval time.Duration) (*model.DriveSelfTestLogEntry, error) {
+	if testType != model.DriveSelfTestShort && testType != model.DriveSelfTestExtended {
+		return nil, errors.Wrap(ErrDriveSelfTestUnsupported, string(testType))
+	}
+
+	before, err := tester.SelfTestStatus(ctx, logicalName)
+	if err != nil {
+		return nil, err
+	}
+
+	if before.InProgress {
+		return nil, ErrDriveSelfTestInProgress
+	}
+
+	if err := tester.StartSelfTest(ctx, logicalName, testType); err != nil {
+		return nil, err
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	var started bool
+
+	for polls := 1; ; polls++ {
+		select {
+		case <-ctx.Done():
+			abortSelfTest(ctx, l, tester, logicalName)
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+
+		status, err := tester.SelfTestStatus(ctx, logicalName)
+		if err != nil {
+			if ctx.Err() != nil {
+				abortSelfTest(ctx, l, tester, logicalName)
+			}
+
+			return nil, err
+		}
+
+		if status.InProgress {
+			started = true
+
+			l.WithField("remaining-percent", status.RemainingPercent).Debug("drive self-test in progress")
+
+			continue
+		}
+
+		if newSelfTestLogEntry(before, status) {
+			return status.Latest, nil
+		}
+
+		// the self-test completed and was not recorded in the self-test log
+		if started {
+			return nil, ErrDriveSelfTestNoLogEntry
+		}
+
+		if polls >= selfTestStartPolls {
+			return nil, ErrDriveSelfTestNotStarted
+		}
+	}
+}
+
+// newSelfTestLogEntry returns true when an entry was added to the self-test log since the before status.
+//
+// The entry is identified by the self-test log entry count increasing, the latest entry is compared only
+// when the count is not reported or the log is full, since the drive may log an identical entry for a repeated self-test.
+func newSelfTestLogEntry(before, status *model.DriveSelfTestStatus) bool {
+	if status.Latest == nil {
+		return false
+	}
+
+	if status.LogEntries > before.LogEntries {
+		return true
+	}
+
+	return !reflect.DeepEqual(before.Latest, status.Latest)
+}
+
+// abortSelfTest aborts the self-test running on the drive, the abort is attempted even though the context is canceled.
+func abortSelfTest(ctx context.Context, l *logrus.Entry, tester DriveSelfTester, logicalName string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), selfTestAbortTimeout)
+	defer cancel()
+
+	if err := tester.AbortSelfTest(ctx, logicalName); err != nil {
+		l.WithError(err).Warn("failed to abort drive self-test")
+		return
+	}
+
+	l.Info("drive self-test aborted")
+}
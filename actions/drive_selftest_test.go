@@ -0,0 +1,177 @@
+package actions
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	common "github.com/metal-toolbox/bmc-common"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metal-toolbox/ironlib/model"
+)
+
+// fakeSelfTester returns the configured statuses in order, the last status is repeated once all have been returned
+type fakeSelfTester struct {
+	mu       sync.Mutex
+	statuses []*model.DriveSelfTestStatus
+	started  model.DriveSelfTestType
+	aborted  bool
+	startErr error
+}
+
+func (f *fakeSelfTester) StartSelfTest(_ context.Context, _ string, testType model.DriveSelfTestType) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.started = testType
+
+	return f.startErr
+}
+
+func (f *fakeSelfTester) AbortSelfTest(ctx context.Context, _ string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// the abort is expected to be called with a context that is not canceled
+	f.aborted = ctx.Err() == nil
+
+	return nil
+}
+
+func (f *fakeSelfTester) SelfTestStatus(context.Context, string) (*model.DriveSelfTestStatus, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	status := f.statuses[0]
+	if len(f.statuses) > 1 {
+		f.statuses = f.statuses[1:]
+	}
+
+	return status, nil
+}
+
+func Test_SelfTestDrives(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+
+	previous := &model.DriveSelfTestLogEntry{Type: "Short offline", Status: "Completed without error", Passed: true, PowerOnHours: 10}
+	passed := &model.DriveSelfTestLogEntry{Type: "Extended offline", Status: "Completed without error", Passed: true, PowerOnHours: 12}
+
+	lba := uint64(4096)
+	failed := &model.DriveSelfTestLogEntry{Type: "Extended offline", Status: "Completed: read failure", PowerOnHours: 12, FailingLBA: &lba}
+
+	testers := map[string]*fakeSelfTester{
+		"/dev/sda": {
+			statuses: []*model.DriveSelfTestStatus{
+				{Latest: previous},
+				{InProgress: true, RemainingPercent: 90, Latest: previous},
+				{InProgress: true, RemainingPercent: 10, Latest: previous},
+				{Latest: passed},
+			},
+		},
+		"/dev/sdb": {
+			statuses: []*model.DriveSelfTestStatus{
+				{},
+				{InProgress: true, RemainingPercent: 50},
+				{Latest: failed},
+			},
+		},
+		// the self-test never shows up in the drive self-test log
+		"/dev/sdc": {
+			statuses: []*model.DriveSelfTestStatus{{Latest: previous}},
+		},
+		"/dev/sdd": {
+			statuses: []*model.DriveSelfTestStatus{{InProgress: true}},
+		},
+		// the repeated self-test log entry is identical to the previous entry
+		"/dev/sde": {
+			statuses: []*model.DriveSelfTestStatus{
+				{Latest: previous, LogEntries: 1},
+				{InProgress: true, RemainingPercent: 50, Latest: previous, LogEntries: 1},
+				{Latest: previous, LogEntries: 2},
+			},
+		},
+	}
+
+	drives := []*common.Drive{}
+	for _, name := range []string{"/dev/sda", "/dev/sdb", "/dev/sdc", "/dev/sdd", "/dev/sde"} {
+		drives = append(drives, &common.Drive{Common: common.Common{LogicalName: name}})
+	}
+
+	testerFor := func(drive *common.Drive) DriveSelfTester { return testers[drive.LogicalName] }
+	options := &model.DriveSelfTestOptions{Type: model.DriveSelfTestExtended, PollInterval: time.Millisecond}
+
+	results, err := selfTestDrives(context.Background(), logger, drives, options, testerFor)
+	assert.ErrorIs(t, err, ErrDriveSelfTestsFailed)
+	require.Len(t, results, len(drives))
+
+	assert.Equal(t, drives[0], results[0].Drive)
+	assert.True(t, results[0].Passed)
+	assert.Equal(t, "Completed without error", results[0].Status)
+	assert.Nil(t, results[0].Error)
+	assert.Equal(t, model.DriveSelfTestExtended, testers["/dev/sda"].started)
+
+	assert.False(t, results[1].Passed)
+	assert.Equal(t, "Completed: read failure", results[1].Status)
+	assert.Equal(t, &lba, results[1].FailingLBA)
+	assert.Nil(t, results[1].Error)
+
+	assert.ErrorIs(t, results[2].Error, ErrDriveSelfTestNotStarted)
+	assert.ErrorIs(t, results[3].Error, ErrDriveSelfTestInProgress)
+	assert.Empty(t, testers["/dev/sdd"].started)
+
+	assert.True(t, results[4].Passed)
+	assert.Nil(t, results[4].Error)
+}
+
+func Test_NewSelfTestLogEntry(t *testing.T) {
+	entry := &model.DriveSelfTestLogEntry{Type: "Short offline", Status: "Completed without error", Passed: true, PowerOnHours: 10}
+	next := &model.DriveSelfTestLogEntry{Type: "Short offline", Status: "Completed without error", Passed: true, PowerOnHours: 11}
+
+	testcases := []struct {
+		name   string
+		before *model.DriveSelfTestStatus
+		status *model.DriveSelfTestStatus
+		want   bool
+	}{
+		{"empty log", &model.DriveSelfTestStatus{}, &model.DriveSelfTestStatus{}, false},
+		{"first entry", &model.DriveSelfTestStatus{}, &model.DriveSelfTestStatus{Latest: entry, LogEntries: 1}, true},
+		{"identical entry added", &model.DriveSelfTestStatus{Latest: entry, LogEntries: 1}, &model.DriveSelfTestStatus{Latest: entry, LogEntries: 2}, true},
+		{"no entry added", &model.DriveSelfTestStatus{Latest: entry, LogEntries: 2}, &model.DriveSelfTestStatus{Latest: entry, LogEntries: 2}, false},
+		{"full log", &model.DriveSelfTestStatus{Latest: entry, LogEntries: 21}, &model.DriveSelfTestStatus{Latest: next, LogEntries: 21}, true},
+		{"count not reported", &model.DriveSelfTestStatus{Latest: entry}, &model.DriveSelfTestStatus{Latest: next}, true},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, newSelfTestLogEntry(tc.before, tc.status))
+		})
+	}
+}
+
+func Test_SelfTestDrivesCanceled(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+
+	tester := &fakeSelfTester{
+		statuses: []*model.DriveSelfTestStatus{
+			{},
+			{InProgress: true, RemainingPercent: 90},
+		},
+	}
+
+	drives := []*common.Drive{{Common: common.Common{LogicalName: "/dev/sda"}}}
+	testerFor := func(*common.Drive) DriveSelfTester { return tester }
+	options := &model.DriveSelfTestOptions{PollInterval: time.Millisecond}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	results, err := selfTestDrives(ctx, logger, drives, options, testerFor)
+	assert.ErrorIs(t, err, ErrDriveSelfTestsFailed)
+	assert.ErrorIs(t, results[0].Error, context.DeadlineExceeded)
+	assert.Equal(t, model.DriveSelfTestShort, tester.started)
+	assert.True(t, tester.aborted)
+}
@@ -0,0 +1,229 @@
+package actions
+
+import (
+	"context"
+	"crypto/ed25519"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	common "github.com/metal-toolbox/bmc-common"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/metal-toolbox/ironlib/model"
+	"github.com/metal-toolbox/ironlib/utils"
+)
+
+var (
+	ErrWipeUtilityNotIdentified = errors.New("no permitted wipe utility identified for drive")
+	ErrWipeMethodsFailed        = errors.New("all wipe methods failed")
+)
+
+// WipeMethod identifies a drive wipe method
+type WipeMethod string
+
+const (
+	// WipeMethodNvme wipes through nvme sanitize or nvme format
+	WipeMethodNvme WipeMethod = "nvme"
+	// WipeMethodHdparm wipes through ATA sanitize or ATA security erase
+	WipeMethodHdparm WipeMethod = "hdparm"
+	// WipeMethodSg3Utils wipes through SCSI sanitize or SCSI format unit
+	WipeMethodSg3Utils WipeMethod = "sg3_utils"
+	// WipeMethodBlkdiscard wipes by discarding (TRIM) all the drive blocks,
+	// this depends on the drive returning zeros for discarded blocks and is considered weaker than the other methods.
+	WipeMethodBlkdiscard WipeMethod = "blkdiscard"
+	// WipeMethodFillZero wipes by writing zeros across the drive
+	WipeMethodFillZero WipeMethod = "fill-zero"
+)
+
+// driveWipeMethods returns the wipe methods applicable to the drive in the order of preference,
+// the methods are identified based on the drive protocol and capabilities.
+func driveWipeMethods(drive *common.Drive) []WipeMethod {
+	methods := []WipeMethod{}
+
+	switch strings.ToLower(drive.Protocol) {
+	case "nvme":
+		methods = append(methods, WipeMethodNvme)
+	case "sata":
+		// Lets figure out the drive capabilities in an easier format
+		var sanitize, esee, trim bool
+		for _, cap := range drive.Capabilities {
+			switch {
+			case cap.Description == "encryption supports enhanced erase":
+				esee = cap.Enabled
+			case cap.Description == "SANITIZE feature":
+				sanitize = cap.Enabled
+			case strings.HasPrefix(cap.Description, "Data Set Management TRIM supported"):
+				trim = cap.Enabled
+			}
+		}
+
+		// Drive supports Sanitize or Enhanced Erase, so we use hdparm
+		if sanitize || esee {
+			methods = append(methods, WipeMethodHdparm)
+		}
+
+		// Drive supports TRIM, so we use blkdiscard
+		if trim {
+			methods = append(methods, WipeMethodBlkdiscard)
+		}
+	case "sas":
+		// Drive supports SCSI sanitize or format unit, so we use sg3_utils
+		for _, cap := range drive.Capabilities {
+			if slices.Contains([]string{"cer", "ber", "owr", "fmtu"}, cap.Name) && cap.Enabled {
+				methods = append(methods, WipeMethodSg3Utils)
+				break
+			}
+		}
+	}
+
+	// filling the drive with zeros is always available as the last resort
+	return append(methods, WipeMethodFillZero)
+}
+
+// wipeMethod is a wipe method and its wipe utility
+type wipeMethod struct {
+	method WipeMethod
+	wiper  DriveWiper
+}
+
+// wipeChain implements the DriveWiper interface, trying each of the wipe methods
+// in order until one of them succeeds.
+type wipeChain struct {
+	methods []wipeMethod
+}
+
+// Methods returns the wipe methods in the order they are attempted
+func (w *wipeChain) Methods() []WipeMethod {
+	methods := make([]WipeMethod, 0, len(w.methods))
+	for _, m := range w.methods {
+		methods = append(methods, m.method)
+	}
+
+	return methods
+}
+
+// WipeDrive implements the DriveWiper interface
+func (w *wipeChain) WipeDrive(ctx context.Context, logger *logrus.Logger, drive *common.Drive) error {
+	failed := []string{}
+
+	for _, m := range w.methods {
+		l := logger.WithField("drive", drive.LogicalName).WithField("wipe-method", m.method)
+
+		err := m.wiper.WipeDrive(ctx, logger, drive)
+		if err == nil {
+			return nil
+		}
+
+		// no point in trying the next method when the context is done
+		if ctx.Err() != nil {
+			return err
+		}
+
+		l.WithError(err).Warn("wipe method failed, trying next method")
+		failed = append(failed, string(m.method)+": "+err.Error())
+	}
+
+	return errors.Wrap(ErrWipeMethodsFailed, strings.Join(failed, "; "))
+}
+
+// wipeChainForDrive returns the wipe chain for the drive excluding the forbidden wipe methods
+func (s *StorageControllerAction) wipeChainForDrive(drive *common.Drive) (*wipeChain, error) {
+	chain := &wipeChain{}
+
+	for _, method := range driveWipeMethods(drive) {
+		if slices.Contains(s.forbiddenWipeMethods, method) {
+			s.Logger.WithField("drive", drive.LogicalName).WithField("wipe-method", method).Trace("wipe method forbidden by policy")
+			continue
+		}
+
+		chain.methods = append(chain.methods, wipeMethod{method: method, wiper: s.wipeUtility(method, drive)})
+	}
+
+	if len(chain.methods) == 0 {
+		return nil, errors.Wrap(ErrWipeUtilityNotIdentified, drive.LogicalName)
+	}
+
+	return chain, nil
+}
+
+// wipeJournalFileChars matches the characters in the drive serial not permitted in the journal file name
+var wipeJournalFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// wipeJournalPath returns the path of the file the drive zero fill wipe progress is journaled to,
+// the file is named by the drive serial so drives wiped concurrently do not share a journal.
+func (s *StorageControllerAction) wipeJournalPath(drive *common.Drive) string {
+	return filepath.Join(s.wipeJournalDir, "wipe-"+wipeJournalFileChars.ReplaceAllString(drive.Serial, "_")+".json")
+}
+
+// wipeUtility returns the wipe utility for the wipe method
+func (s *StorageControllerAction) wipeUtility(method WipeMethod, drive *common.Drive) DriveWiper {
+	switch method {
+	case WipeMethodNvme:
+		return utils.NewNvmeCmd(s.trace)
+	case WipeMethodHdparm:
+		return utils.NewHdparmCmd(s.trace)
+	case WipeMethodSg3Utils:
+		return utils.NewSg3UtilsCmd(s.trace)
+	case WipeMethodBlkdiscard:
+		return utils.NewBlkdiscardCmd(s.trace)
+	default:
+		zero := utils.NewFillZeroCmd(s.trace)
+		if s.wipeJournalDir == "" {
+			return zero
+		}
+
+		// the journal is named by and resumed for the drive serial
+		if drive.Serial == "" {
+			s.Logger.WithField("drive", drive.LogicalName).Warn("drive serial not identified, zero fill wipe progress not journaled")
+			return zero
+		}
+
+		zero.SetJournalPath(s.wipeJournalPath(drive))
+
+		return zero
+	}
+}
+
+// WipeDriveWithCertificate wipes the drive with the given wiper and returns a certificate of the successful wipe,
+// the certificate is signed when a signing key is given.
+//
+// The certificate includes the wipe methods attempted and the verification results recorded by the wiper.
+func WipeDriveWithCertificate(ctx context.Context, logger *logrus.Logger, wiper DriveWiper, drive *common.Drive, key ed25519.PrivateKey) (*model.WipeCertificate, error) {
+	ctx, record := utils.NewWipeRecordContext(ctx)
+
+	started := time.Now().UTC()
+	if err := wiper.WipeDrive(ctx, logger, drive); err != nil {
+		return nil, err
+	}
+
+	cert := &model.WipeCertificate{
+		Drive: model.WipeCertificateDrive{
+			LogicalName:   drive.LogicalName,
+			Vendor:        drive.Vendor,
+			Model:         drive.Model,
+			Serial:        drive.Serial,
+			Protocol:      drive.Protocol,
+			CapacityBytes: drive.CapacityBytes,
+		},
+		Started:       started,
+		Ended:         time.Now().UTC(),
+		Methods:       record.Methods(),
+		Verifications: record.Verifications(),
+	}
+
+	if drive.Firmware != nil {
+		cert.Drive.Firmware = drive.Firmware.Installed
+	}
+
+	if key != nil {
+		if err := cert.Sign(key); err != nil {
+			return nil, errors.Wrap(err, "error signing wipe certificate")
+		}
+	}
+
+	return cert, nil
+}
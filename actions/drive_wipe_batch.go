@@ -0,0 +1,192 @@
+package actions
+
+import (
+	"context"
+	"crypto/ed25519"
+	"strings"
+	"sync"
+	"time"
+
+	common "github.com/metal-toolbox/bmc-common"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/metal-toolbox/ironlib/model"
+)
+
+var ErrWipeDrivesFailed = errors.New("failed to wipe drives")
+
+const (
+	defaultWipeMinTimeout     = 10 * time.Minute
+	defaultWipeMaxTimeout     = 48 * time.Hour
+	defaultWipeBytesPerSecond = 100 * 1000 * 1000
+)
+
+// WipeDriveResult is the result of wiping a drive in a batch
+type WipeDriveResult struct {
+	Drive   *common.Drive
+	Timeout time.Duration
+	Started time.Time
+	Ended   time.Time
+	// Certificate is set when the drive was wiped successfully.
+	Certificate *model.WipeCertificate
+	// Error is set when the drive wipe failed.
+	Error error
+}
+
+// WipeDrives wipes the drives concurrently, the results are returned in the same order as the drives.
+//
+// Each drive is wiped with the wipe utility returned by GetWipeUtility within a timeout derived from its capacity,
+// a failed drive does not stop the rest of the batch, instead ErrWipeDrivesFailed is returned
+// along with the results once all drives have been attempted.
+func (s *StorageControllerAction) WipeDrives(ctx context.Context, drives []*common.Drive, options *model.WipeDrivesOptions) ([]*WipeDriveResult, error) {
+	return wipeDrives(ctx, s.Logger, drives, options, s.GetWipeUtility)
+}
+
+func wipeDrives(ctx context.Context, logger *logrus.Logger, drives []*common.Drive, options *model.WipeDrivesOptions, wiperFor func(*common.Drive) (DriveWiper, error)) ([]*WipeDriveResult, error) {
+	if options == nil {
+		options = &model.WipeDrivesOptions{}
+	}
+
+	concurrency := options.Concurrency
+	if concurrency <= 0 || concurrency > len(drives) {
+		concurrency = len(drives)
+	}
+
+	sem := make(chan struct{}, max(concurrency, 1))
+
+	// semaphores per storage controller
+	controllerSems := map[string]chan struct{}{}
+	if options.ControllerConcurrency > 0 {
+		for _, drive := range drives {
+			key := driveControllerKey(drive)
+			if _, exists := controllerSems[key]; !exists {
+				controllerSems[key] = make(chan struct{}, options.ControllerConcurrency)
+			}
+		}
+	}
+
+	results := make([]*WipeDriveResult, len(drives))
+
+	var wg sync.WaitGroup
+	for idx, drive := range drives {
+		result := &WipeDriveResult{Drive: drive, Timeout: wipeTimeout(drive, options)}
+		results[idx] = result
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			// the controller slot is acquired before the batch slot,
+			// so drives waiting on a busy controller don't hold up drives on other controllers.
+			if csem, exists := controllerSems[driveControllerKey(drive)]; exists {
+				if !acquire(ctx, csem) {
+					result.Error = ctx.Err()
+					return
+				}
+				defer func() { <-csem }()
+			}
+
+			if !acquire(ctx, sem) {
+				result.Error = ctx.Err()
+				return
+			}
+			defer func() { <-sem }()
+
+			wipeBatchDrive(ctx, logger, result, options.CertificateKey, wiperFor)
+		}()
+	}
+
+	wg.Wait()
+
+	failed := []string{}
+	for _, result := range results {
+		if result.Error != nil {
+			failed = append(failed, result.Drive.LogicalName+": "+result.Error.Error())
+		}
+	}
+
+	if len(failed) > 0 {
+		return results, errors.Wrap(ErrWipeDrivesFailed, strings.Join(failed, "; "))
+	}
+
+	return results, nil
+}
+
+// wipeBatchDrive wipes the result drive within the result timeout and sets the result fields
+func wipeBatchDrive(ctx context.Context, logger *logrus.Logger, result *WipeDriveResult, key ed25519.PrivateKey, wiperFor func(*common.Drive) (DriveWiper, error)) {
+	l := logger.WithField("drive", result.Drive.LogicalName).WithField("timeout", result.Timeout.String())
+
+	wiper, err := wiperFor(result.Drive)
+	if err != nil {
+		result.Error = err
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, result.Timeout)
+	defer cancel()
+
+	l.Info("wiping drive")
+
+	result.Started = time.Now().UTC()
+	result.Certificate, result.Error = WipeDriveWithCertificate(ctx, logger, wiper, result.Drive, key)
+	result.Ended = time.Now().UTC()
+
+	if result.Error != nil {
+		l.WithError(result.Error).Warn("failed to wipe drive")
+		return
+	}
+
+	l.WithField("duration", result.Ended.Sub(result.Started).String()).Info("drive wiped")
+}
+
+// acquire acquires a slot on the semaphore, returning false if the context is done first
+func acquire(ctx context.Context, sem chan struct{}) bool {
+	select {
+	case sem <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// driveControllerKey returns the key identifying the storage controller the drive is attached to,
+// NVMe drives are their own controller.
+func driveControllerKey(drive *common.Drive) string {
+	if drive.StorageController != "" {
+		return drive.StorageController
+	}
+
+	if strings.EqualFold(drive.Protocol, "nvme") {
+		return drive.LogicalName
+	}
+
+	return ""
+}
+
+// wipeTimeout returns the time allowed to wipe the drive based on its capacity
+func wipeTimeout(drive *common.Drive, options *model.WipeDrivesOptions) time.Duration {
+	minTimeout := options.MinTimeout
+	if minTimeout <= 0 {
+		minTimeout = defaultWipeMinTimeout
+	}
+
+	maxTimeout := options.MaxTimeout
+	if maxTimeout <= 0 {
+		maxTimeout = defaultWipeMaxTimeout
+	}
+
+	bytesPerSecond := options.BytesPerSecond
+	if bytesPerSecond <= 0 {
+		bytesPerSecond = defaultWipeBytesPerSecond
+	}
+
+	if drive.CapacityBytes <= 0 {
+		return maxTimeout
+	}
+
+	timeout := minTimeout + time.Duration(drive.CapacityBytes/bytesPerSecond)*time.Second
+
+	return min(timeout, maxTimeout)
+}
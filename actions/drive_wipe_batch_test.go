@@ -0,0 +1,156 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	common "github.com/metal-toolbox/bmc-common"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/metal-toolbox/ironlib/model"
+)
+
+// concurrencyWiper tracks the number of concurrent wipes overall and per storage controller
+type concurrencyWiper struct {
+	mu            sync.Mutex
+	active        map[string]int
+	maxController int
+	total         int
+	maxTotal      int
+	fail          map[string]error
+}
+
+func (c *concurrencyWiper) WipeDrive(ctx context.Context, _ *logrus.Logger, drive *common.Drive) error {
+	key := driveControllerKey(drive)
+
+	c.mu.Lock()
+	c.active[key]++
+	c.maxController = max(c.maxController, c.active[key])
+	c.total++
+	c.maxTotal = max(c.maxTotal, c.total)
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		c.active[key]--
+		c.total--
+		c.mu.Unlock()
+	}()
+
+	select {
+	case <-time.After(10 * time.Millisecond):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return c.fail[drive.LogicalName]
+}
+
+func Test_WipeDrives(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+
+	drives := []*common.Drive{}
+	for _, name := range []string{"/dev/sda", "/dev/sdb", "/dev/sdc", "/dev/sdd"} {
+		drives = append(drives, &common.Drive{Common: common.Common{LogicalName: name}, StorageController: "hba0"})
+	}
+
+	for _, name := range []string{"/dev/nvme0n1", "/dev/nvme1n1"} {
+		drives = append(drives, &common.Drive{Common: common.Common{LogicalName: name}, Protocol: "nvme"})
+	}
+
+	errWipe := errors.New("wipe failed")
+	wiper := &concurrencyWiper{active: map[string]int{}, fail: map[string]error{"/dev/sdb": errWipe}}
+	wiperFor := func(*common.Drive) (DriveWiper, error) { return wiper, nil }
+
+	options := &model.WipeDrivesOptions{Concurrency: 3, ControllerConcurrency: 2}
+
+	results, err := wipeDrives(context.Background(), logger, drives, options, wiperFor)
+	assert.ErrorIs(t, err, ErrWipeDrivesFailed)
+	assert.Contains(t, err.Error(), "/dev/sdb: wipe failed")
+
+	assert.Len(t, results, len(drives))
+	for idx, result := range results {
+		assert.Equal(t, drives[idx], result.Drive)
+
+		if result.Drive.LogicalName == "/dev/sdb" {
+			assert.ErrorIs(t, result.Error, errWipe)
+			assert.Nil(t, result.Certificate)
+
+			continue
+		}
+
+		assert.NoError(t, result.Error)
+		assert.NotNil(t, result.Certificate)
+	}
+
+	assert.LessOrEqual(t, wiper.maxTotal, 3)
+	assert.LessOrEqual(t, wiper.maxController, 2)
+}
+
+func Test_WipeDrivesContextCanceled(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+
+	drives := []*common.Drive{
+		{Common: common.Common{LogicalName: "/dev/sda"}},
+		{Common: common.Common{LogicalName: "/dev/sdb"}},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	wiper := &concurrencyWiper{active: map[string]int{}}
+	wiperFor := func(*common.Drive) (DriveWiper, error) { return wiper, nil }
+
+	results, err := wipeDrives(ctx, logger, drives, &model.WipeDrivesOptions{Concurrency: 1}, wiperFor)
+	assert.ErrorIs(t, err, ErrWipeDrivesFailed)
+
+	for _, result := range results {
+		assert.ErrorIs(t, result.Error, context.Canceled)
+	}
+}
+
+func Test_wipeTimeout(t *testing.T) {
+	testcases := []struct {
+		name     string
+		capacity int64
+		options  *model.WipeDrivesOptions
+		expected time.Duration
+	}{
+		{
+			"unknown capacity",
+			0,
+			&model.WipeDrivesOptions{},
+			defaultWipeMaxTimeout,
+		},
+		{
+			"defaults",
+			1000 * 1000 * 1000 * 1000,
+			&model.WipeDrivesOptions{},
+			defaultWipeMinTimeout + 10000*time.Second,
+		},
+		{
+			"options",
+			1000 * 1000 * 1000,
+			&model.WipeDrivesOptions{MinTimeout: time.Minute, BytesPerSecond: 1000 * 1000},
+			time.Minute + 1000*time.Second,
+		},
+		{
+			"capped to max timeout",
+			1000 * 1000 * 1000 * 1000,
+			&model.WipeDrivesOptions{MaxTimeout: time.Hour},
+			time.Hour,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			drive := &common.Drive{CapacityBytes: tc.capacity}
+			assert.Equal(t, tc.expected, wipeTimeout(drive, tc.options))
+		})
+	}
+}
@@ -0,0 +1,295 @@
+package actions
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	common "github.com/metal-toolbox/bmc-common"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metal-toolbox/ironlib/model"
+	"github.com/metal-toolbox/ironlib/utils"
+)
+
+func Test_GetWipeUtility(t *testing.T) {
+	sataCaps := func(sanitize, esee, trim bool) []*common.Capability {
+		return []*common.Capability{
+			{Description: "SANITIZE feature", Enabled: sanitize},
+			{Description: "encryption supports enhanced erase", Enabled: esee},
+			{Description: "Data Set Management TRIM supported (limit 8 blocks)", Enabled: trim},
+		}
+	}
+
+	testcases := []struct {
+		name        string
+		drive       *common.Drive
+		forbidden   []WipeMethod
+		expected    []WipeMethod
+		expectedErr error
+	}{
+		{
+			"nvme drive",
+			&common.Drive{Protocol: "nvme"},
+			nil,
+			[]WipeMethod{WipeMethodNvme, WipeMethodFillZero},
+			nil,
+		},
+		{
+			"sata drive with sanitize and trim",
+			&common.Drive{Protocol: "sata", Common: common.Common{Capabilities: sataCaps(true, false, true)}},
+			nil,
+			[]WipeMethod{WipeMethodHdparm, WipeMethodBlkdiscard, WipeMethodFillZero},
+			nil,
+		},
+		{
+			"sata drive with enhanced erase",
+			&common.Drive{Protocol: "SATA", Common: common.Common{Capabilities: sataCaps(false, true, false)}},
+			nil,
+			[]WipeMethod{WipeMethodHdparm, WipeMethodFillZero},
+			nil,
+		},
+		{
+			"sata drive with trim, trim forbidden",
+			&common.Drive{Protocol: "sata", Common: common.Common{Capabilities: sataCaps(false, false, true)}},
+			[]WipeMethod{WipeMethodBlkdiscard},
+			[]WipeMethod{WipeMethodFillZero},
+			nil,
+		},
+		{
+			"sas drive",
+			&common.Drive{Protocol: "sas"},
+			nil,
+			[]WipeMethod{WipeMethodFillZero},
+			nil,
+		},
+		{
+			"sas drive with sanitize",
+			&common.Drive{Protocol: "sas", Common: common.Common{Capabilities: []*common.Capability{{Name: "ber", Enabled: true}}}},
+			nil,
+			[]WipeMethod{WipeMethodSg3Utils, WipeMethodFillZero},
+			nil,
+		},
+		{
+			"all methods forbidden",
+			&common.Drive{Protocol: "sas"},
+			[]WipeMethod{WipeMethodFillZero},
+			nil,
+			ErrWipeUtilityNotIdentified,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			logger, _ := test.NewNullLogger()
+			s := NewStorageControllerAction(logger, WithForbiddenWipeMethods(tc.forbidden...))
+
+			got, err := s.GetWipeUtility(tc.drive)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+
+			chain, ok := got.(*wipeChain)
+			assert.True(t, ok)
+			assert.Equal(t, tc.expected, chain.Methods())
+		})
+	}
+}
+
+// fakeWiper records the wipe attempt and returns the configured error
+type fakeWiper struct {
+	err    error
+	called bool
+}
+
+func (f *fakeWiper) WipeDrive(context.Context, *logrus.Logger, *common.Drive) error {
+	f.called = true
+	return f.err
+}
+
+func Test_wipeChainFallback(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	drive := &common.Drive{Common: common.Common{LogicalName: "/dev/sda"}}
+
+	errSanitize := errors.New("sanitize failed")
+
+	first := &fakeWiper{err: errSanitize}
+	second := &fakeWiper{}
+	third := &fakeWiper{}
+
+	chain := &wipeChain{methods: []wipeMethod{
+		{method: WipeMethodHdparm, wiper: first},
+		{method: WipeMethodBlkdiscard, wiper: second},
+		{method: WipeMethodFillZero, wiper: third},
+	}}
+
+	assert.NoError(t, chain.WipeDrive(context.Background(), logger, drive))
+	assert.True(t, first.called)
+	assert.True(t, second.called)
+	assert.False(t, third.called)
+
+	// all methods failed
+	second.err = errSanitize
+	third.err = errSanitize
+
+	err := chain.WipeDrive(context.Background(), logger, drive)
+	assert.ErrorIs(t, err, ErrWipeMethodsFailed)
+	assert.Contains(t, err.Error(), "fill-zero: sanitize failed")
+}
+
+func Test_WipeDriveWithCertificate(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	drive := &common.Drive{
+		Common: common.Common{
+			LogicalName: "/dev/sda",
+			Serial:      "S1234",
+			Model:       "Micron_5200",
+			Firmware:    &common.Firmware{Installed: "D1MU020"},
+		},
+		CapacityBytes: 1024,
+		Protocol:      "sata",
+	}
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	cert, err := WipeDriveWithCertificate(context.Background(), logger, &fakeWiper{}, drive, key)
+	assert.NoError(t, err)
+	assert.Equal(t, model.WipeCertificateDrive{
+		LogicalName:   "/dev/sda",
+		Model:         "Micron_5200",
+		Serial:        "S1234",
+		Firmware:      "D1MU020",
+		Protocol:      "sata",
+		CapacityBytes: 1024,
+	}, cert.Drive)
+	assert.False(t, cert.Ended.Before(cert.Started))
+	assert.NoError(t, cert.Verify())
+
+	// tampering with the certificate invalidates the signature
+	cert.Drive.Serial = "S5678"
+	assert.ErrorIs(t, cert.Verify(), model.ErrWipeCertificateSignature)
+
+	// unsigned
+	cert, err = WipeDriveWithCertificate(context.Background(), logger, &fakeWiper{}, drive, nil)
+	assert.NoError(t, err)
+	assert.ErrorIs(t, cert.Verify(), model.ErrWipeCertificateUnsigned)
+
+	// no certificate for a failed wipe
+	errWipe := errors.New("wipe failed")
+	cert, err = WipeDriveWithCertificate(context.Background(), logger, &fakeWiper{err: errWipe}, drive, key)
+	assert.ErrorIs(t, err, errWipe)
+	assert.Nil(t, cert)
+}
+
+func Test_WipeJournalPerDrive(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	s := NewStorageControllerAction(logger, WithWipeJournalDir("/var/lib/ironlib"))
+
+	journalPath := func(drive *common.Drive) string {
+		chain, err := s.wipeChainForDrive(drive)
+		assert.NoError(t, err)
+
+		zero, ok := chain.methods[len(chain.methods)-1].wiper.(*utils.FillZero)
+		assert.True(t, ok)
+
+		return zero.JournalPath
+	}
+
+	first := &common.Drive{Common: common.Common{LogicalName: "/dev/sda", Serial: "S3Z1NB0K"}}
+	second := &common.Drive{Common: common.Common{LogicalName: "/dev/sdb", Serial: "WD-WX/21"}}
+
+	// the drives wiped concurrently are journaled to their own files
+	assert.Equal(t, "/var/lib/ironlib/wipe-S3Z1NB0K.json", journalPath(first))
+	assert.Equal(t, "/var/lib/ironlib/wipe-WD-WX_21.json", journalPath(second))
+
+	// no journal is set when the journal directory is not
+	s = NewStorageControllerAction(logger)
+	assert.Empty(t, journalPath(first))
+}
+
+func Test_WipeDriveResumed(t *testing.T) {
+	const size = 1024 * 1024
+
+	logger, hook := test.NewNullLogger()
+	journalDir := t.TempDir()
+	s := NewStorageControllerAction(logger, WithWipeJournalDir(journalDir))
+
+	// newDrive returns a drive filled with random data, the drive is wiped by filling it with zeros
+	newDrive := func(t *testing.T, serial string) *common.Drive {
+		t.Helper()
+
+		data := make([]byte, size)
+		_, err := rand.Read(data)
+		require.NoError(t, err)
+
+		name := filepath.Join(t.TempDir(), "sda")
+		require.NoError(t, os.WriteFile(name, data, 0o600))
+
+		return &common.Drive{Common: common.Common{LogicalName: name, Serial: serial}}
+	}
+
+	t.Run("interrupted wipe resumed", func(t *testing.T) {
+		drive := newDrive(t, "S1")
+		journalPath := s.wipeJournalPath(drive)
+
+		// a canceled context stops the wipe once the watermarks are applied and journaled
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		require.ErrorIs(t, s.WipeDrive(ctx, logger, drive), context.Canceled)
+
+		// the wipe is interrupted half way through
+		journal := map[string]any{}
+		b, err := os.ReadFile(journalPath)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(b, &journal))
+
+		journal["offset"] = size / 2
+		b, err = json.Marshal(journal)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(journalPath, b, 0o600))
+
+		f, err := os.OpenFile(drive.LogicalName, os.O_WRONLY, 0)
+		require.NoError(t, err)
+		_, err = f.Write(make([]byte, size/2))
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		// the resumed wipe writes from the journaled offset and verifies the journaled watermarks
+		require.NoError(t, s.WipeDrive(context.Background(), logger, drive))
+
+		b, err = os.ReadFile(drive.LogicalName)
+		require.NoError(t, err)
+		assert.Equal(t, make([]byte, size), b)
+
+		_, err = os.Stat(journalPath)
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("drive without serial not journaled", func(t *testing.T) {
+		hook.Reset()
+
+		drive := newDrive(t, "")
+
+		require.NoError(t, s.WipeDrive(context.Background(), logger, drive))
+
+		entries, err := os.ReadDir(journalDir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+
+		require.NotNil(t, hook.LastEntry())
+		assert.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+	})
+}
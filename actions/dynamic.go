@@ -1,6 +1,8 @@
 package actions
 
 import (
+	"reflect"
+	"slices"
 	"strings"
 
 	common "github.com/metal-toolbox/bmc-common"
@@ -13,6 +15,10 @@ func StorageControllerCollectorByVendor(vendor string, trace bool) StorageContro
 		return utils.NewMvcliCmd(trace)
 	}
 
+	if isStoreCLIVendor(vendor) {
+		return utils.NewStoreCLICmd(trace)
+	}
+
 	return nil
 }
 
@@ -21,9 +27,47 @@ func DriveCollectorByStorageControllerVendor(vendor string, trace bool) DriveCol
 		return utils.NewMvcliCmd(trace)
 	}
 
+	if isStoreCLIVendor(vendor) {
+		return utils.NewStoreCLICmd(trace)
+	}
+
 	return nil
 }
 
+// isStoreCLIVendor returns true when the storage controller vendor is managed through storecli - Broadcom/LSI controllers
+func isStoreCLIVendor(vendor string) bool {
+	switch common.FormatVendorName(vendor) {
+	case common.VendorBroadcom, common.VendorLSI:
+		return true
+	}
+
+	// LSI Logic / Symbios Logic
+	return strings.HasPrefix(strings.ToLower(vendor), "lsi ")
+}
+
+// hasCollectorType returns true when a collector of the same type as c is included in collectors
+func hasCollectorType[T any](collectors []T, c T) bool {
+	return slices.ContainsFunc(collectors, func(e T) bool {
+		return reflect.TypeOf(e) == reflect.TypeOf(c)
+	})
+}
+
+// driveCapabilityCollectorForDrive returns the drive capability collector based on the drive protocol,
+// SAS drives are collected through sg3_utils, other drives are identified by their logical name.
+func driveCapabilityCollectorForDrive(drive *common.Drive, trace bool, collectors []DriveCapabilityCollector) DriveCapabilityCollector {
+	if !strings.EqualFold(drive.Protocol, "sas") {
+		return driveCapabilityCollectorByLogicalName(drive.LogicalName, trace, collectors)
+	}
+
+	for _, collector := range collectors {
+		if sg3utils, isSg3UtilsCollector := collector.(*utils.Sg3Utils); isSg3UtilsCollector {
+			return sg3utils
+		}
+	}
+
+	return utils.NewSg3UtilsCmd(trace)
+}
+
 func driveCapabilityCollectorByLogicalName(logicalName string, trace bool, collectors []DriveCapabilityCollector) DriveCapabilityCollector {
 	// when collectors are is passed in, return the collector based on the logical name
 	for _, collector := range collectors {
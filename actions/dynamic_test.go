@@ -3,6 +3,7 @@ package actions
 import (
 	"testing"
 
+	common "github.com/metal-toolbox/bmc-common"
 	"github.com/metal-toolbox/ironlib/utils"
 	"github.com/stretchr/testify/assert"
 )
@@ -47,3 +48,41 @@ func Test_driveCapabilityCollectorByLogicalName(t *testing.T) {
 		})
 	}
 }
+
+func Test_driveCapabilityCollectorForDrive(t *testing.T) {
+	sg3utils := utils.NewSg3UtilsCmd(false)
+	collectors := []DriveCapabilityCollector{utils.NewHdparmCmd(false), utils.NewNvmeCmd(false), sg3utils}
+
+	sas := &common.Drive{Common: common.Common{LogicalName: "/dev/sdb"}, Protocol: "sas"}
+	assert.Same(t, sg3utils, driveCapabilityCollectorForDrive(sas, false, collectors))
+	assert.EqualValues(t, utils.NewSg3UtilsCmd(false), driveCapabilityCollectorForDrive(sas, false, nil))
+
+	sata := &common.Drive{Common: common.Common{LogicalName: "/dev/sda"}, Protocol: "sata"}
+	assert.Same(t, collectors[0], driveCapabilityCollectorForDrive(sata, false, collectors))
+}
+
+func Test_StorageControllerCollectorByVendor(t *testing.T) {
+	testcases := []struct {
+		vendor   string
+		expected StorageControllerCollector
+	}{
+		{"marvell", utils.NewMvcliCmd(false)},
+		{"LSI", utils.NewStoreCLICmd(false)},
+		{"Broadcom / LSI", utils.NewStoreCLICmd(false)},
+		{"LSI Logic / Symbios Logic", utils.NewStoreCLICmd(false)},
+		{"intel", nil},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.vendor, func(t *testing.T) {
+			assert.EqualValues(t, tc.expected, StorageControllerCollectorByVendor(tc.vendor, false))
+		})
+	}
+}
+
+func Test_hasCollectorType(t *testing.T) {
+	collectors := []DriveCollector{utils.NewStoreCLICmd(false)}
+
+	assert.True(t, hasCollectorType(collectors, DriveCollector(utils.NewStoreCLICmd(false))))
+	assert.False(t, hasCollectorType(collectors, DriveCollector(utils.NewMvcliCmd(false))))
+}
@@ -25,7 +25,11 @@ type RaidController interface {
 
 // Setter interface declares methods to set attributes on a system.
 type Setter interface {
-	SetBIOSConfiguration(ctx context.Context, config map[string]string) error
+	// Apply the given BIOS settings, the settings may be normalized keys (smt, secure_boot..),
+	// "raw:" prefixed or vendor native settings.
+	// The returned changes are keyed by the vendor native setting and indicate the settings
+	// that took effect and the ones pending a reboot.
+	SetBIOSConfiguration(ctx context.Context, config map[string]string) (*model.BIOSConfigurationChanges, error)
 }
 
 // Getter interface declares methods implemented by providers to return various attributes.
@@ -38,7 +42,8 @@ type Getter interface {
 	RebootRequired() bool
 	// Check if any updates were applied
 	UpdatesApplied() bool
-	// Retrieve inventory for the device
+	// Retrieve inventory for the device,
+	// a report of the collectors executed is returned when the WithCollectionReport option is passed in.
 	GetInventory(ctx context.Context, options ...Option) (*common.Device, error)
 	// Retrieve inventory using the OEM tooling for the device,
 	GetInventoryOEM(ctx context.Context, device *common.Device, options *model.UpdateOptions) error
@@ -64,6 +69,14 @@ type BIOSConfiguror interface {
 	GetBIOSConfiguration(ctx context.Context, deviceModel string) (map[string]string, error)
 }
 
+// BIOSConfigurationSetter defines an interface to apply BIOS configuration
+type BIOSConfigurationSetter interface {
+	BIOSConfiguror
+	// SetBIOSConfiguration writes the given vendor native BIOS settings,
+	// deviceModel is an optional parameter depending on the hardware variants
+	SetBIOSConfiguration(ctx context.Context, deviceModel string, config map[string]string) error
+}
+
 // UtilAttributeGetter defines methods to retrieve utility attributes.
 type UtilAttributeGetter interface {
 	Attributes() (utilName model.CollectorUtility, absolutePath string, err error)
@@ -129,6 +142,13 @@ type StorageControllerCollector interface {
 	StorageControllers(ctx context.Context) ([]*common.StorageController, error)
 }
 
+// VirtualDiskCollector defines an interface to return virtual disk inventory,
+// the virtual disks collected are recorded as JSON in the device Metadata under model.DeviceMetadataVirtualDisks.
+type VirtualDiskCollector interface {
+	UtilAttributeGetter
+	ListVirtualDisks(ctx context.Context) ([]*common.VirtualDisk, error)
+}
+
 // TPMCollector defines an interface to collect TPM device inventory
 type TPMCollector interface {
 	UtilAttributeGetter
@@ -180,7 +200,7 @@ type BMCUpdater interface {
 // CPLDUpdater defines an interface to update CPLD firmware
 type CPLDUpdater interface {
 	UtilAttributeGetter
-	UpdateCPLD() error
+	UpdateCPLD(ctx context.Context, updateFile, modelNumber string) error
 }
 
 // BIOSUpdater defines an interface to update BIOS firmware
@@ -192,7 +212,7 @@ type BIOSUpdater interface {
 // StorageControllerUpdater defines an interface to update storage controller firmware
 type StorageControllerUpdater interface {
 	UtilAttributeGetter
-	UpdateStorageController() error
+	UpdateStorageController(ctx context.Context, updateFile, modelNumber string) error
 }
 
 // VirtualDiskCreator defines an interface to create virtual disks, generally via a StorageController
@@ -205,10 +225,11 @@ type VirtualDiskDestroyer interface {
 	DestroyVirtualDisk(ctx context.Context, virtualDiskID int) error
 }
 
+// VirtualDiskManager defines an interface to create, destroy and list virtual disks, generally via a StorageController
 type VirtualDiskManager interface {
 	VirtualDiskCreator
 	VirtualDiskDestroyer
-	VirtualDisks(ctx context.Context) ([]*utils.MvcliDevice, error)
+	ListVirtualDisks(ctx context.Context) ([]*common.VirtualDisk, error)
 }
 
 // DriveWiper defines an interface to override disk data
@@ -216,3 +237,12 @@ type DriveWiper interface {
 	// WipeDrive wipes away all data from the drive, wipe is always verified to have succeeded
 	WipeDrive(context.Context, *logrus.Logger, *common.Drive) error
 }
+
+// DriveSelfTester defines an interface to run drive self-tests
+//
+// The self-test runs in the background on the drive, its progress and result is read with SelfTestStatus.
+type DriveSelfTester interface {
+	StartSelfTest(ctx context.Context, logicalName string, testType model.DriveSelfTestType) error
+	AbortSelfTest(ctx context.Context, logicalName string) error
+	SelfTestStatus(ctx context.Context, logicalName string) (*model.DriveSelfTestStatus, error)
+}
@@ -4,9 +4,9 @@ package actions
 import (
 	"context"
 	"encoding/json"
-	"runtime/debug"
 	"slices"
 	"strings"
+	"time"
 
 	common "github.com/metal-toolbox/bmc-common"
 	"github.com/pkg/errors"
@@ -49,6 +49,15 @@ type InventoryCollectorAction struct {
 	// to be disabled, this is the name of collector utility
 	// which is returned by its Attributes() method.
 	disabledCollectorUtilities []model.CollectorUtility
+
+	// collectorConcurrency is the number of collectors executed in parallel.
+	collectorConcurrency int
+
+	// collectorTimeout is the maximum duration a collector may run for, no timeout is applied when unset.
+	collectorTimeout time.Duration
+
+	// report when set records the collectors executed.
+	report *model.CollectionReport
 }
 
 // Collectors is a struct acting as a registry of various inventory collectors
@@ -64,6 +73,7 @@ type Collectors struct {
 	StorageControllerCollectors []StorageControllerCollector
 	DriveCollectors             []DriveCollector
 	DriveCapabilitiesCollectors []DriveCapabilityCollector
+	VirtualDiskCollectors       []VirtualDiskCollector
 }
 
 // Empty returns a bool value
@@ -77,6 +87,7 @@ func (c *Collectors) Empty() bool {
 		len(c.StorageControllerCollectors) == 0 &&
 		len(c.DriveCollectors) == 0 &&
 		len(c.DriveCapabilitiesCollectors) == 0 &&
+		len(c.VirtualDiskCollectors) == 0 &&
 		c.UEFIVarsCollector == nil &&
 		c.FirmwareChecksumCollector == nil {
 		return true
@@ -151,6 +162,10 @@ func NewInventoryCollectorAction(ll *logrus.Logger, options ...Option) *Inventor
 			DriveCapabilitiesCollectors: []DriveCapabilityCollector{
 				utils.NewHdparmCmd(a.trace),
 				utils.NewNvmeCmd(a.trace),
+				utils.NewSg3UtilsCmd(a.trace),
+			},
+			VirtualDiskCollectors: []VirtualDiskCollector{
+				utils.NewMdadmCmd(a.trace),
 			},
 			FirmwareChecksumCollector: firmware.NewChecksumCollector(
 				firmware.MakeOutputPath(),
@@ -160,11 +175,18 @@ func NewInventoryCollectorAction(ll *logrus.Logger, options ...Option) *Inventor
 		}
 	}
 
-	// the lshw collector cannot be disabled, since its the primary inventory collector.
+	// the primary inventory collector cannot be left unset,
+	// when lshw is disabled the sysfs collector takes its place.
 	if a.collectors.InventoryCollector == nil {
 		a.collectors.InventoryCollector = utils.NewLshwCmd(a.trace)
 	}
 
+	if slices.Contains(a.disabledCollectorUtilities, model.CollectorUtility("lshw")) {
+		if _, ok := a.collectors.InventoryCollector.(*utils.Lshw); ok {
+			a.collectors.InventoryCollector = utils.NewSysfsCollector()
+		}
+	}
+
 	return a
 }
 
@@ -174,7 +196,11 @@ func NewInventoryCollectorAction(ll *logrus.Logger, options ...Option) *Inventor
 // Inventory expects a device object and optionally a collectors object,
 // when the collectors object provided is nil, the default collectors are added - lshw, smartctl
 //
-// The lshw collector always executes first and is included by default.
+// The primary inventory collector always executes first, this is lshw by default
+// or the sysfs collector when lshw is disabled.
+//
+// The remaining collectors are executed concurrently in stages - collectors depending on data from a previous stage
+// are executed in a later stage, the collected data is merged into the device in a fixed order to keep the results deterministic.
 // nolint:gocyclo //since we're collecting inventory for each type, this is cyclomatic
 func (a *InventoryCollectorAction) Collect(ctx context.Context, device *common.Device) error {
 	// initialize a new device object - when a device isn't already provided
@@ -185,116 +211,73 @@ func (a *InventoryCollectorAction) Collect(ctx context.Context, device *common.D
 
 	a.device = device
 
+	if a.report != nil {
+		a.report.Started = time.Now().UTC()
+		a.report.Collectors = nil
+
+		defer func() { a.report.Ended = time.Now().UTC() }()
+	}
+
 	// register a TPM inventory collector
 	if a.collectors.TPMCollector == nil && !slices.Contains(a.disabledCollectorUtilities, model.CollectorUtility("dmidecode")) {
-		var err error
+		dmidecode, err := utils.NewDmidecode()
+		if err != nil {
+			a.reportCollector(dmidecode, 0, 0, err)
 
-		a.collectors.TPMCollector, err = utils.NewDmidecode()
-		if err != nil && a.failOnError {
-			return errors.Wrap(err, "error in dmidecode inventory collector")
+			if a.failOnError {
+				return errors.Wrap(err, "error in dmidecode inventory collector")
+			}
+		} else {
+			a.collectors.TPMCollector = dmidecode
 		}
 	}
 
-	// TODO (joel)
-	//
-	// move Drives(), NICs() and other methods under the Collectors struct
-	// so that parameters can be set as fields on the Collectors struct and they don't have to be passed
-	// as function parameters. This will also allow data collection to proceed even if
-	// one drive/nic/storagecontroller/psu component returns an error.
-
 	// Collect initial device inventory
 	a.log.Debug("collect initial inventory")
-	err := a.collectors.InventoryCollector.Collect(ctx, a.device)
+	err := a.collect(ctx, a.inventoryTask())
 	a.log.WithError(err).Debug("collect initial done")
 	if err != nil && a.failOnError {
-		return errors.Wrap(err, "error retrieving device inventory")
-	}
-
-	// Collect drive smart data
-	a.log.Debug("collect drives")
-	err = a.CollectDrives(ctx)
-	a.log.WithError(err).Debug("collect drives done")
-	if err != nil && a.failOnError {
-		return errors.Wrap(err, "error retrieving drive inventory")
-	}
-
-	// Collect NIC info
-	a.log.Debug("collect nics")
-	err = a.CollectNICs(ctx)
-	a.log.WithError(err).Debug("collect nics done")
-	if err != nil && a.failOnError {
-		return errors.Wrap(err, "error retrieving NIC inventory")
-	}
-
-	// Collect BIOS info
-	a.log.Debug("collect bios")
-	err = a.CollectBIOS(ctx)
-	a.log.WithError(err).Debug("collect bios done")
-	if err != nil && a.failOnError {
-		return errors.Wrap(err, "error retrieving BIOS inventory")
-	}
-
-	// Collect CPLD info
-	a.log.Debug("collect cpld")
-	err = a.CollectCPLDs(ctx)
-	a.log.WithError(err).Debug("collect cpld done")
-	if err != nil && a.failOnError {
-		return errors.Wrap(err, "error retrieving CPLD inventory")
-	}
-
-	// Collect BMC info
-	a.log.Debug("collect bmc")
-	err = a.CollectBMC(ctx)
-	a.log.WithError(err).Debug("collect bmc done")
-	if err != nil && a.failOnError {
-		return errors.Wrap(err, "error retrieving BMC inventory")
-	}
-
-	// Collect TPM info
-	a.log.Debug("collect tpm")
-	err = a.CollectTPMs(ctx)
-	a.log.WithError(err).Debug("collect tpm done")
-	if err != nil && a.failOnError {
-		return errors.Wrap(err, "error retrieving TPM inventory")
-	}
-
-	// Collect Firmware checksums
-	a.log.Debug("collect firmware checksum")
-	err = a.CollectFirmwareChecksums(ctx)
-	a.log.WithError(err).Debug("collect firmware checksum done")
-	if err != nil && a.failOnError {
-		return errors.Wrap(err, "error retrieving Firmware checksums")
-	}
-
-	// Collect UEFI variables
-	a.log.Debug("collect uefi variables")
-	err = a.CollectUEFIVariables(ctx)
-	a.log.WithError(err).Debug("collect uefi variables done")
-	if err != nil && a.failOnError {
-		return errors.Wrap(err, "error retrieving UEFI variables")
+		return err
 	}
 
 	// Update StorageControllerCollectors based on controller vendor attributes
 	if a.dynamicCollection {
 		for _, sc := range a.device.StorageControllers {
-			if c := StorageControllerCollectorByVendor(sc.Vendor, a.trace); c != nil {
+			c := StorageControllerCollectorByVendor(sc.Vendor, a.trace)
+			if c != nil && !hasCollectorType(a.collectors.StorageControllerCollectors, c) {
 				a.collectors.StorageControllerCollectors = append(a.collectors.StorageControllerCollectors, c)
 			}
 		}
 	}
 
-	// Collect StorageController info
-	a.log.Debug("collect storage controller")
-	err = a.CollectStorageControllers(ctx)
-	a.log.WithError(err).Debug("collect storage controller done")
+	// Collect drive, NIC, BIOS, CPLD, BMC, TPM, firmware checksum, UEFI variables, StorageController and VirtualDisk info
+	a.log.Debug("collect components")
+	tasks := a.driveTasks()
+	tasks = append(tasks,
+		a.nicTask(),
+		a.biosTask(),
+		a.cpldTask(),
+		a.bmcTask(),
+		a.tpmTask(),
+		a.firmwareChecksumTask(),
+		a.uefiVariablesTask(),
+	)
+	tasks = append(tasks, a.storageControllerTasks()...)
+	tasks = append(tasks, a.virtualDiskTasks()...)
+
+	a.resetVirtualDisks()
+
+	err = a.collect(ctx, tasks...)
+	a.log.WithError(err).Debug("collect components done")
 	if err != nil && a.failOnError {
-		return errors.Wrap(err, "error retrieving StorageController inventory")
+		return err
 	}
 
 	// Update DriveCollectors based on drive vendor attributes
 	if a.dynamicCollection {
 		for _, sc := range a.device.StorageControllers {
-			if c := DriveCollectorByStorageControllerVendor(sc.Vendor, a.trace); c != nil {
+			c := DriveCollectorByStorageControllerVendor(sc.Vendor, a.trace)
+			if c != nil && !hasCollectorType(a.collectors.DriveCollectors, c) {
 				a.collectors.DriveCollectors = append(a.collectors.DriveCollectors, c)
 			}
 		}
@@ -305,7 +288,7 @@ func (a *InventoryCollectorAction) Collect(ctx context.Context, device *common.D
 			a.log.WithError(err).Debug("dynamic collect drive done")
 
 			if err != nil && a.failOnError {
-				return errors.Wrap(err, "error retrieving drive inventory")
+				return err
 			}
 		}
 	}
@@ -314,7 +297,7 @@ func (a *InventoryCollectorAction) Collect(ctx context.Context, device *common.D
 	a.log.Debug("collect drive capabilities")
 	err = a.CollectDriveCapabilities(ctx)
 	if err != nil && a.failOnError {
-		return errors.Wrap(err, "error retrieving DriveCapabilities")
+		return err
 	}
 	a.log.WithError(err).Debug("collect drive capabilities done")
 
@@ -341,78 +324,104 @@ func (a *InventoryCollectorAction) setDefaultAttributes() {
 	}
 }
 
-// CollectDrives executes drive collectors and merges the data into device.[]*Drive
-// nolint:gocyclo // TODO(joel) if theres more conditionals to be added in here, the method is to be split up.
-func (a *InventoryCollectorAction) CollectDrives(ctx context.Context) (err error) {
-	defer func() {
-		if r := recover(); r != nil && a.failOnError {
-			err = errors.Wrap(ErrPanic, string(debug.Stack()))
-		}
-	}()
+// collectorDisabled returns true if the collector utility has been disabled
+func (a *InventoryCollectorAction) collectorDisabled(collector UtilAttributeGetter) bool {
+	collectorKind, _, _ := collector.Attributes()
 
-	if a.collectors.DriveCollectors == nil {
-		return nil
+	return slices.Contains(a.disabledCollectorUtilities, collectorKind)
+}
+
+// inventoryTask returns the task to collect the initial device inventory
+func (a *InventoryCollectorAction) inventoryTask() *collectorTask {
+	return &collectorTask{
+		errMsg:    "error retrieving device inventory",
+		collector: a.collectors.InventoryCollector,
+		fetch: func(ctx context.Context) (mergeFunc, error) {
+			// the inventory collector writes to the device directly
+			// and is the only collector executed in its stage.
+			if err := a.collectors.InventoryCollector.Collect(ctx, a.device); err != nil {
+				return nil, err
+			}
+
+			return func() (int, error) { return deviceComponents(a.device), nil }, nil
+		},
 	}
+}
+
+// CollectDrives executes drive collectors and merges the data into device.[]*Drive
+func (a *InventoryCollectorAction) CollectDrives(ctx context.Context) error {
+	return a.collect(ctx, a.driveTasks()...)
+}
+
+// driveTasks returns a task for each of the enabled drive collectors
+func (a *InventoryCollectorAction) driveTasks() []*collectorTask {
+	tasks := []*collectorTask{}
 
 	for _, collector := range a.collectors.DriveCollectors {
 		// skip collector if its been disabled
-		collectorKind, _, _ := collector.Attributes()
-		if slices.Contains(a.disabledCollectorUtilities, collectorKind) {
+		if a.collectorDisabled(collector) {
 			continue
 		}
 
-		ndrives, err := collector.Drives(ctx)
-		if err != nil {
-			return err
-		}
-
-		if len(ndrives) == 0 {
-			return nil
-		}
-
-		for _, existing := range a.device.Drives {
-			// match existing drives by serial, and patch with changes
-			found := a.findDriveBySerial(existing.Serial, ndrives)
-			if found != nil {
-				// diff existing drive fields with the one found by the collector
-				changelog, err := diff.Diff(existing, found)
-				if err != nil {
-					return err
+		tasks = append(tasks, &collectorTask{
+			errMsg:    "error retrieving drive inventory",
+			collector: collector,
+			fetch: func(ctx context.Context) (mergeFunc, error) {
+				ndrives, err := collector.Drives(ctx)
+				if err != nil || len(ndrives) == 0 {
+					return nil, err
 				}
 
-				changelog = a.vetChanges(changelog)
-				diff.Patch(changelog, existing)
+				return func() (int, error) { return a.mergeDrives(ndrives) }, nil
+			},
+		})
+	}
 
-				continue
-			}
+	return tasks
+}
 
-			// as a fallback for the ndrives data that might not include a serial number,
-			// match existing drives by logical name and patch with changes
+// mergeDrives merges the drives identified by a drive collector into device.[]*Drive,
+// the number of drives updated and added is returned.
+func (a *InventoryCollectorAction) mergeDrives(ndrives []*common.Drive) (int, error) {
+	var touched int
+
+	for _, existing := range a.device.Drives {
+		// match existing drives by serial, and patch with changes,
+		// as a fallback for the ndrives data that might not include a serial number,
+		// match existing drives by logical name and patch with changes
+		found := a.findDriveBySerial(existing.Serial, ndrives)
+		if found == nil {
 			found = a.findDriveByLogicalName(existing.LogicalName, ndrives)
-			if found != nil {
-				// diff existing drive fields with the one found by the collector
-				changelog, err := diff.Diff(existing, found)
-				if err != nil {
-					return err
-				}
+		}
 
-				changelog = a.vetChanges(changelog)
-				diff.Patch(changelog, existing)
-			}
+		if found == nil {
+			continue
 		}
 
-		// add drive if it isn't part of the drives slice based on its serial
-		for _, new := range ndrives {
-			found := a.findDriveBySerial(new.Serial, a.device.Drives)
-			if found != nil && found.Serial != "" {
-				continue
-			}
+		// diff existing drive fields with the one found by the collector
+		changelog, err := diff.Diff(existing, found)
+		if err != nil {
+			return touched, err
+		}
 
-			a.device.Drives = append(a.device.Drives, new)
+		changelog = a.vetChanges(changelog)
+		diff.Patch(changelog, existing)
+
+		touched += changedComponent(changelog)
+	}
+
+	// add drive if it isn't part of the drives slice based on its serial
+	for _, new := range ndrives {
+		found := a.findDriveBySerial(new.Serial, a.device.Drives)
+		if found != nil && found.Serial != "" {
+			continue
 		}
+
+		a.device.Drives = append(a.device.Drives, new)
+		touched++
 	}
 
-	return nil
+	return touched, nil
 }
 
 func (a *InventoryCollectorAction) findDriveBySerial(serial string, drives []*common.Drive) *common.Drive {
@@ -438,18 +447,19 @@ func (a *InventoryCollectorAction) findDriveByLogicalName(logicalName string, dr
 // CollectDriveCapabilities executes drive capability collectors
 //
 // The capability collector is identified based on the drive logical name.
-func (a *InventoryCollectorAction) CollectDriveCapabilities(ctx context.Context) (err error) {
-	defer func() {
-		if r := recover(); r != nil && a.failOnError {
-			err = errors.Wrap(ErrPanic, string(debug.Stack()))
-		}
-	}()
+func (a *InventoryCollectorAction) CollectDriveCapabilities(ctx context.Context) error {
+	return a.collect(ctx, a.driveCapabilityTasks()...)
+}
+
+// driveCapabilityTasks returns a task for each drive the capabilities are to be collected for
+func (a *InventoryCollectorAction) driveCapabilityTasks() []*collectorTask {
+	tasks := []*collectorTask{}
 
 	for _, drive := range a.device.Drives {
-		// check capabilities on drives that are either SATA or NVME,
+		// check capabilities on drives that are either SATA, SAS or NVME,
 		//
-		// if theres others to be supported, the driveCapabilityCollectorByLogicalName() method
-		// is to be updated to include the required support for SAS/USB/SCSI or other kinds of transports.
+		// if theres others to be supported, the driveCapabilityCollectorForDrive() method
+		// is to be updated to include the required support for USB/SCSI or other kinds of transports.
 		if !slices.Contains([]string{"sata", "nvme", "sas"}, drive.Protocol) {
 			continue
 		}
@@ -459,369 +469,453 @@ func (a *InventoryCollectorAction) CollectDriveCapabilities(ctx context.Context)
 			continue
 		}
 
-		collector := driveCapabilityCollectorByLogicalName(drive.LogicalName, false, a.collectors.DriveCapabilitiesCollectors)
+		collector := driveCapabilityCollectorForDrive(drive, false, a.collectors.DriveCapabilitiesCollectors)
 
 		// skip collector if its been disabled
-		collectorKind, _, _ := collector.Attributes()
-		if slices.Contains(a.disabledCollectorUtilities, collectorKind) {
+		if a.collectorDisabled(collector) {
 			continue
 		}
 
-		capabilities, err := collector.DriveCapabilities(ctx, drive.LogicalName)
-		if err != nil {
-			return err
-		}
+		tasks = append(tasks, &collectorTask{
+			errMsg:    "error retrieving DriveCapabilities",
+			collector: collector,
+			fetch: func(ctx context.Context) (mergeFunc, error) {
+				capabilities, err := collector.DriveCapabilities(ctx, drive.LogicalName)
+				if err != nil {
+					return nil, err
+				}
 
-		drive.Capabilities = capabilities
+				return func() (int, error) {
+					drive.Capabilities = capabilities
+					return 1, nil
+				}, nil
+			},
+		})
 	}
 
-	return nil
+	return tasks
 }
 
 // CollectNICs executes nic collectors and merges the nic data into device.[]*NIC
-func (a *InventoryCollectorAction) CollectNICs(ctx context.Context) (err error) {
-	defer func() {
-		if r := recover(); r != nil && a.failOnError {
-			err = errors.Wrap(ErrPanic, string(debug.Stack()))
-		}
-	}()
-
-	if a.collectors.NICCollector == nil {
-		return nil
-	}
+func (a *InventoryCollectorAction) CollectNICs(ctx context.Context) error {
+	return a.collect(ctx, a.nicTask())
+}
 
-	// skip collector if its been disabled
-	collectorKind, _, _ := a.collectors.NICCollector.Attributes()
-	if slices.Contains(a.disabledCollectorUtilities, collectorKind) {
+// nicTask returns the task to collect the NIC information, nil when the collector is not set or disabled
+func (a *InventoryCollectorAction) nicTask() *collectorTask {
+	if a.collectors.NICCollector == nil || a.collectorDisabled(a.collectors.NICCollector) {
 		return nil
 	}
 
-	found, err := a.collectors.NICs(ctx)
-	if err != nil {
-		return err
-	}
-
-	if len(found) == 0 {
-		return nil
-	}
+	return &collectorTask{
+		errMsg:    "error retrieving NIC inventory",
+		collector: a.collectors.NICCollector,
+		fetch: func(ctx context.Context) (mergeFunc, error) {
+			found, err := a.collectors.NICs(ctx)
+			if err != nil || len(found) == 0 {
+				return nil, err
+			}
 
-	// TODO: handle case where the object may not already be present in device.NICs and needs to be added
-	for _, e := range a.device.NICs {
-		for _, n := range found {
-			// object is matched by serial identifier and patched
-			if strings.EqualFold(e.Serial, n.Serial) {
-				changelog, err := diff.Diff(e, n)
-				if err != nil {
-					return err
+			return func() (int, error) {
+				var touched int
+
+				// TODO: handle case where the object may not already be present in device.NICs and needs to be added
+				for _, e := range a.device.NICs {
+					for _, n := range found {
+						// object is matched by serial identifier and patched
+						if strings.EqualFold(e.Serial, n.Serial) {
+							changelog, err := diff.Diff(e, n)
+							if err != nil {
+								return touched, err
+							}
+
+							changelog = a.vetChanges(changelog)
+							diff.Patch(changelog, e)
+
+							touched += changedComponent(changelog)
+						}
+					}
 				}
 
-				changelog = a.vetChanges(changelog)
-				diff.Patch(changelog, e)
-			}
-		}
+				return touched, nil
+			}, nil
+		},
 	}
-
-	return nil
 }
 
 // CollectBMC executes the bmc collector and updates device bmc information
-func (a *InventoryCollectorAction) CollectBMC(ctx context.Context) (err error) {
-	defer func() {
-		if r := recover(); r != nil && a.failOnError {
-			err = errors.Wrap(ErrPanic, string(debug.Stack()))
-		}
-	}()
-
-	if a.collectors.BMCCollector == nil {
-		return nil
-	}
+func (a *InventoryCollectorAction) CollectBMC(ctx context.Context) error {
+	return a.collect(ctx, a.bmcTask())
+}
 
-	// skip collector if its been disabled
-	collectorKind, _, _ := a.collectors.BMCCollector.Attributes()
-	if slices.Contains(a.disabledCollectorUtilities, collectorKind) {
+// bmcTask returns the task to collect the BMC information, nil when the collector is not set or disabled
+func (a *InventoryCollectorAction) bmcTask() *collectorTask {
+	if a.collectors.BMCCollector == nil || a.collectorDisabled(a.collectors.BMCCollector) {
 		return nil
 	}
 
-	found, err := a.collectors.BMC(ctx)
-	if err != nil {
-		return err
-	}
+	return &collectorTask{
+		errMsg:    "error retrieving BMC inventory",
+		collector: a.collectors.BMCCollector,
+		fetch: func(ctx context.Context) (mergeFunc, error) {
+			found, err := a.collectors.BMC(ctx)
+			if err != nil {
+				return nil, err
+			}
 
-	changelog, err := diff.Diff(a.device.BMC, found)
-	if err != nil {
-		return err
-	}
+			return func() (int, error) {
+				changelog, err := diff.Diff(a.device.BMC, found)
+				if err != nil {
+					return 0, err
+				}
 
-	changelog = a.vetChanges(changelog)
-	diff.Patch(changelog, a.device.BMC)
+				changelog = a.vetChanges(changelog)
+				diff.Patch(changelog, a.device.BMC)
 
-	return nil
+				return changedComponent(changelog), nil
+			}, nil
+		},
+	}
 }
 
 // CollectCPLDs executes the bmc collector and updates device cpld information
-func (a *InventoryCollectorAction) CollectCPLDs(ctx context.Context) (err error) {
-	defer func() {
-		if r := recover(); r != nil && a.failOnError {
-			err = errors.Wrap(ErrPanic, string(debug.Stack()))
-		}
-	}()
+func (a *InventoryCollectorAction) CollectCPLDs(ctx context.Context) error {
+	return a.collect(ctx, a.cpldTask())
+}
 
-	if a.collectors.CPLDCollector == nil {
+// cpldTask returns the task to collect the CPLD information, nil when the collector is not set or disabled
+func (a *InventoryCollectorAction) cpldTask() *collectorTask {
+	if a.collectors.CPLDCollector == nil || a.collectorDisabled(a.collectors.CPLDCollector) {
 		return nil
 	}
 
-	// skip collector if its been disabled
-	collectorKind, _, _ := a.collectors.CPLDCollector.Attributes()
-	if slices.Contains(a.disabledCollectorUtilities, collectorKind) {
-		return nil
-	}
+	return &collectorTask{
+		errMsg:    "error retrieving CPLD inventory",
+		collector: a.collectors.CPLDCollector,
+		fetch: func(ctx context.Context) (mergeFunc, error) {
+			found, err := a.collectors.CPLDs(ctx)
+			// no new cplds identified
+			if err != nil || len(found) == 0 {
+				return nil, err
+			}
 
-	found, err := a.collectors.CPLDs(ctx)
-	if err != nil {
-		return err
-	}
+			return func() (int, error) {
+				if len(a.device.CPLDs) == 0 {
+					a.device.CPLDs = append(a.device.CPLDs, found...)
+					return len(found), nil
+				}
 
-	// no new cplds identified
-	if len(found) == 0 {
-		return nil
-	}
+				changelog, err := diff.Diff(a.device.CPLDs, found)
+				if err != nil {
+					return 0, err
+				}
 
-	if len(a.device.CPLDs) > 0 {
-		changelog, err := diff.Diff(a.device.CPLDs, found)
-		if err != nil {
-			return err
-		}
+				changelog = a.vetChanges(changelog)
+				diff.Patch(changelog, a.device.CPLDs)
 
-		changelog = a.vetChanges(changelog)
-		diff.Patch(changelog, a.device.CPLDs)
-	} else {
-		a.device.CPLDs = append(a.device.CPLDs, found...)
+				return changedComponents(changelog), nil
+			}, nil
+		},
 	}
-
-	return nil
 }
 
 // CollectBIOS executes the bios collector and updates device bios information
-func (a *InventoryCollectorAction) CollectBIOS(ctx context.Context) (err error) {
-	defer func() {
-		if r := recover(); r != nil && a.failOnError {
-			err = errors.Wrap(ErrPanic, string(debug.Stack()))
-		}
-	}()
+func (a *InventoryCollectorAction) CollectBIOS(ctx context.Context) error {
+	return a.collect(ctx, a.biosTask())
+}
 
-	if a.collectors.BIOSCollector == nil {
+// biosTask returns the task to collect the BIOS information, nil when the collector is not set or disabled
+func (a *InventoryCollectorAction) biosTask() *collectorTask {
+	if a.collectors.BIOSCollector == nil || a.collectorDisabled(a.collectors.BIOSCollector) {
 		return nil
 	}
 
-	// skip collector if its been disabled
-	collectorKind, _, _ := a.collectors.BIOSCollector.Attributes()
-	if slices.Contains(a.disabledCollectorUtilities, collectorKind) {
-		return nil
-	}
+	return &collectorTask{
+		errMsg:    "error retrieving BIOS inventory",
+		collector: a.collectors.BIOSCollector,
+		fetch: func(ctx context.Context) (mergeFunc, error) {
+			found, err := a.collectors.BIOS(ctx)
+			if err != nil || found == nil {
+				return nil, err
+			}
 
-	found, err := a.collectors.BIOS(ctx)
-	if err != nil {
-		return err
-	}
+			return func() (int, error) {
+				changelog, err := diff.Diff(a.device.BIOS, found)
+				if err != nil {
+					return 0, err
+				}
 
-	if found == nil {
-		return nil
-	}
+				changelog = a.vetChanges(changelog)
+				diff.Patch(changelog, a.device.BIOS)
 
-	changelog, err := diff.Diff(a.device.BIOS, found)
-	if err != nil {
-		return err
+				return changedComponent(changelog), nil
+			}, nil
+		},
 	}
-
-	changelog = a.vetChanges(changelog)
-	diff.Patch(changelog, a.device.BIOS)
-
-	return nil
 }
 
 // CollectTPMs executes the TPM collector and updates device TPM information
-func (a *InventoryCollectorAction) CollectTPMs(ctx context.Context) (err error) {
-	defer func() {
-		if r := recover(); r != nil && a.failOnError {
-			err = errors.Wrap(ErrPanic, string(debug.Stack()))
-		}
-	}()
+func (a *InventoryCollectorAction) CollectTPMs(ctx context.Context) error {
+	return a.collect(ctx, a.tpmTask())
+}
 
-	if a.collectors.TPMCollector == nil {
+// tpmTask returns the task to collect the TPM information, nil when the collector is not set or disabled
+func (a *InventoryCollectorAction) tpmTask() *collectorTask {
+	if a.collectors.TPMCollector == nil || a.collectorDisabled(a.collectors.TPMCollector) {
 		return nil
 	}
 
-	// skip collector if its been disabled
-	collectorKind, _, _ := a.collectors.TPMCollector.Attributes()
-	if slices.Contains(a.disabledCollectorUtilities, collectorKind) {
-		return nil
-	}
+	return &collectorTask{
+		errMsg:    "error retrieving TPM inventory",
+		collector: a.collectors.TPMCollector,
+		fetch: func(ctx context.Context) (mergeFunc, error) {
+			found, err := a.collectors.TPMs(ctx)
+			if err != nil || found == nil {
+				return nil, err
+			}
 
-	found, err := a.collectors.TPMs(ctx)
-	if err != nil {
-		return err
-	}
+			return func() (int, error) {
+				if len(a.device.TPMs) == 0 {
+					a.device.TPMs = append(a.device.TPMs, found...)
+					return len(found), nil
+				}
 
-	if found == nil {
-		return nil
-	}
+				changelog, err := diff.Diff(a.device.TPMs, found)
+				if err != nil {
+					return 0, err
+				}
 
-	if len(a.device.TPMs) > 0 {
-		changelog, err := diff.Diff(a.device.TPMs, found)
-		if err != nil {
-			return err
-		}
+				changelog = a.vetChanges(changelog)
+				diff.Patch(changelog, a.device.TPMs)
 
-		changelog = a.vetChanges(changelog)
-		diff.Patch(changelog, a.device.TPMs)
-	} else {
-		a.device.TPMs = append(a.device.TPMs, found...)
+				return changedComponents(changelog), nil
+			}, nil
+		},
 	}
-
-	return nil
 }
 
 // CollectFirmwareChecksums executes the Firmware checksum collector and updates the component metadata.
-func (a *InventoryCollectorAction) CollectFirmwareChecksums(ctx context.Context) (err error) {
-	defer func() {
-		if r := recover(); r != nil && a.failOnError {
-			err = errors.Wrap(ErrPanic, string(debug.Stack()))
-		}
-	}()
+func (a *InventoryCollectorAction) CollectFirmwareChecksums(ctx context.Context) error {
+	return a.collect(ctx, a.firmwareChecksumTask())
+}
 
+// firmwareChecksumTask returns the task to collect the firmware checksums, nil when the collector is not set or disabled
+func (a *InventoryCollectorAction) firmwareChecksumTask() *collectorTask {
 	if a.collectors.FirmwareChecksumCollector == nil {
 		a.log.Debug("nil firmware checksum collector")
 		return nil
 	}
 
 	// skip collector if we explicitly disable anything related to firmware checksumming.
-	collectorKind, _, _ := a.collectors.FirmwareChecksumCollector.Attributes()
-	if slices.Contains(a.disabledCollectorUtilities, collectorKind) ||
+	if a.collectorDisabled(a.collectors.FirmwareChecksumCollector) ||
 		slices.Contains(a.disabledCollectorUtilities, firmware.FirmwareDumpUtility) ||
 		slices.Contains(a.disabledCollectorUtilities, firmware.UEFIParserUtility) {
 		a.log.Debug("firmware checksum disabled")
 		return nil
 	}
 
-	sumStr, err := a.collectors.FirmwareChecksumCollector.BIOSLogoChecksum(ctx)
-	if err != nil {
-		return err
-	}
+	return &collectorTask{
+		errMsg:    "error retrieving Firmware checksums",
+		collector: a.collectors.FirmwareChecksumCollector,
+		fetch: func(ctx context.Context) (mergeFunc, error) {
+			sumStr, err := a.collectors.FirmwareChecksumCollector.BIOSLogoChecksum(ctx)
+			if err != nil {
+				return nil, err
+			}
 
-	if a.device.BIOS == nil {
-		// XXX: how did we get here?
-		a.log.Error("nil device bios data")
-		return nil
-	}
+			return func() (int, error) {
+				if a.device.BIOS == nil {
+					// XXX: how did we get here?
+					a.log.Error("nil device bios data")
+					return 0, nil
+				}
 
-	if a.device.BIOS.Metadata == nil {
-		a.device.BIOS.Metadata = map[string]string{}
-	}
+				if a.device.BIOS.Metadata == nil {
+					a.device.BIOS.Metadata = map[string]string{}
+				}
 
-	a.device.BIOS.Metadata["bios-logo-checksum"] = sumStr
+				a.device.BIOS.Metadata["bios-logo-checksum"] = sumStr
 
-	return nil
+				return 1, nil
+			}, nil
+		},
+	}
 }
 
 // CollectUEFIVariables executes the UEFI variable collector and stores them on the device object
-func (a *InventoryCollectorAction) CollectUEFIVariables(ctx context.Context) (err error) {
-	defer func() {
-		if r := recover(); r != nil && a.failOnError {
-			err = errors.Wrap(ErrPanic, string(debug.Stack()))
-		}
-	}()
+func (a *InventoryCollectorAction) CollectUEFIVariables(ctx context.Context) error {
+	return a.collect(ctx, a.uefiVariablesTask())
+}
 
-	if a.collectors.UEFIVarsCollector == nil {
+// uefiVariablesTask returns the task to collect the UEFI variables, nil when the collector is not set or disabled
+func (a *InventoryCollectorAction) uefiVariablesTask() *collectorTask {
+	if a.collectors.UEFIVarsCollector == nil || a.collectorDisabled(a.collectors.UEFIVarsCollector) {
 		return nil
 	}
 
-	// skip collector if its been disabled
-	collectorKind, _, _ := a.collectors.UEFIVarsCollector.Attributes()
-	if slices.Contains(a.disabledCollectorUtilities, collectorKind) {
-		return nil
-	}
+	return &collectorTask{
+		errMsg:    "error retrieving UEFI variables",
+		collector: a.collectors.UEFIVarsCollector,
+		fetch: func(ctx context.Context) (mergeFunc, error) {
+			keyValues, err := a.collectors.UEFIVarsCollector.GetUEFIVars(ctx)
+			// no variables returned seems unlikely
+			if err != nil || len(keyValues) == 0 {
+				return nil, err
+			}
 
-	keyValues, err := a.collectors.UEFIVarsCollector.GetUEFIVars(ctx)
-	if err != nil {
-		return err
-	}
+			jsonBytes, err := json.Marshal(keyValues)
+			if err != nil {
+				return nil, errors.Wrap(err, "marshaling uefi variables")
+			}
 
-	if len(keyValues) == 0 {
-		// seems unlikely
-		return nil
-	}
+			return func() (int, error) {
+				if a.device.Metadata == nil {
+					a.device.Metadata = map[string]string{}
+				}
 
-	if a.device.Metadata == nil {
-		a.device.Metadata = map[string]string{}
-	}
+				a.device.Metadata["uefi-variables"] = string(jsonBytes)
 
-	jsonBytes, err := json.Marshal(keyValues)
-	if err != nil {
-		return errors.Wrap(err, "marshaling uefi variables")
+				return 1, nil
+			}, nil
+		},
 	}
-
-	a.device.Metadata["uefi-variables"] = string(jsonBytes)
-
-	return nil
 }
 
 // CollectStorageControllers executes the StorageControllers collectors and updates device storage controller data
-func (a *InventoryCollectorAction) CollectStorageControllers(ctx context.Context) (err error) {
-	defer func() {
-		if r := recover(); r != nil && a.failOnError {
-			err = errors.Wrap(ErrPanic, string(debug.Stack()))
-		}
-	}()
+func (a *InventoryCollectorAction) CollectStorageControllers(ctx context.Context) error {
+	return a.collect(ctx, a.storageControllerTasks()...)
+}
 
-	if len(a.collectors.StorageControllerCollectors) == 0 {
-		return nil
-	}
+// storageControllerTasks returns a task for each of the enabled storage controller collectors
+func (a *InventoryCollectorAction) storageControllerTasks() []*collectorTask {
+	tasks := []*collectorTask{}
 
 	for _, collector := range a.collectors.StorageControllerCollectors {
 		// skip collector if its been disabled
-		collectorKind, _, _ := collector.Attributes()
-		if slices.Contains(a.disabledCollectorUtilities, collectorKind) {
+		if a.collectorDisabled(collector) {
 			continue
 		}
 
-		found, err := collector.StorageControllers(ctx)
-		if err != nil {
-			return err
+		tasks = append(tasks, &collectorTask{
+			errMsg:    "error retrieving StorageController inventory",
+			collector: collector,
+			fetch: func(ctx context.Context) (mergeFunc, error) {
+				found, err := collector.StorageControllers(ctx)
+				if err != nil || len(found) == 0 {
+					return nil, err
+				}
+
+				return func() (int, error) { return a.mergeStorageControllers(found) }, nil
+			},
+		})
+	}
+
+	return tasks
+}
+
+// CollectVirtualDisks executes the VirtualDisk collectors and stores the virtual disks in the device metadata
+func (a *InventoryCollectorAction) CollectVirtualDisks(ctx context.Context) error {
+	a.resetVirtualDisks()
+
+	return a.collect(ctx, a.virtualDiskTasks()...)
+}
+
+// resetVirtualDisks clears the virtual disks recorded by a previous collection,
+// the virtual disks are not merged by any attribute and would be otherwise listed again.
+func (a *InventoryCollectorAction) resetVirtualDisks() {
+	if a.device.Metadata != nil {
+		delete(a.device.Metadata, model.DeviceMetadataVirtualDisks)
+	}
+}
+
+// virtualDiskTasks returns a task for each of the enabled virtual disk collectors
+func (a *InventoryCollectorAction) virtualDiskTasks() []*collectorTask {
+	tasks := []*collectorTask{}
+
+	for _, collector := range a.collectors.VirtualDiskCollectors {
+		// skip collector if its been disabled
+		if a.collectorDisabled(collector) {
+			continue
 		}
 
-		if len(found) == 0 {
-			return nil
+		tasks = append(tasks, &collectorTask{
+			errMsg:    "error retrieving VirtualDisk inventory",
+			collector: collector,
+			fetch: func(ctx context.Context) (mergeFunc, error) {
+				found, err := collector.ListVirtualDisks(ctx)
+				if err != nil || len(found) == 0 {
+					return nil, err
+				}
+
+				return func() (int, error) { return a.mergeVirtualDisks(found) }, nil
+			},
+		})
+	}
+
+	return tasks
+}
+
+// mergeVirtualDisks adds the virtual disks identified by a collector to the device model.DeviceMetadataVirtualDisks metadata,
+// the common.Device does not include virtual disks and so these are recorded as a JSON list.
+func (a *InventoryCollectorAction) mergeVirtualDisks(found []*common.VirtualDisk) (int, error) {
+	if a.device.Metadata == nil {
+		a.device.Metadata = map[string]string{}
+	}
+
+	virtualDisks := []*common.VirtualDisk{}
+
+	if existing := a.device.Metadata[model.DeviceMetadataVirtualDisks]; existing != "" {
+		if err := json.Unmarshal([]byte(existing), &virtualDisks); err != nil {
+			return 0, errors.Wrap(err, "unmarshaling virtual disks")
 		}
+	}
 
-		for _, existing := range a.device.StorageControllers {
-			a.findStorageControllerBySerial(existing.Serial, found)
+	jsonBytes, err := json.Marshal(append(virtualDisks, found...))
+	if err != nil {
+		return 0, errors.Wrap(err, "marshaling virtual disks")
+	}
 
-			if found != nil {
-				// diff existing fields with the one found
-				changelog, err := diff.Diff(existing, found)
-				if err != nil {
-					return err
-				}
+	a.device.Metadata[model.DeviceMetadataVirtualDisks] = string(jsonBytes)
 
-				changelog = a.vetChanges(changelog)
-				diff.Patch(changelog, existing)
+	return len(found), nil
+}
 
-				continue
+// mergeStorageControllers merges the storage controllers identified by a collector into device.[]*StorageController,
+// the number of storage controllers updated and added is returned.
+func (a *InventoryCollectorAction) mergeStorageControllers(found []*common.StorageController) (int, error) {
+	var touched int
+
+	for _, existing := range a.device.StorageControllers {
+		a.findStorageControllerBySerial(existing.Serial, found)
+
+		if found != nil {
+			// diff existing fields with the one found
+			changelog, err := diff.Diff(existing, found)
+			if err != nil {
+				return touched, err
 			}
 
-			// add storage controller if it isn't part of existing controllers on the device
-			for _, new := range found {
-				found := a.findStorageControllerBySerial(new.Serial, a.device.StorageControllers)
-				if found != nil && found.Serial != "" {
-					continue
-				}
+			changelog = a.vetChanges(changelog)
+			diff.Patch(changelog, existing)
 
-				a.device.StorageControllers = append(a.device.StorageControllers, new)
+			touched += changedComponent(changelog)
+
+			continue
+		}
+
+		// add storage controller if it isn't part of existing controllers on the device
+		for _, new := range found {
+			found := a.findStorageControllerBySerial(new.Serial, a.device.StorageControllers)
+			if found != nil && found.Serial != "" {
+				continue
 			}
+
+			a.device.StorageControllers = append(a.device.StorageControllers, new)
+			touched++
 		}
 	}
 
-	return nil
+	return touched, nil
 }
 
 func (a *InventoryCollectorAction) findStorageControllerBySerial(serial string, controllers []*common.StorageController) *common.StorageController {
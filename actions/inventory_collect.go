@@ -0,0 +1,199 @@
+package actions
+
+import (
+	"context"
+	"reflect"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// defaultCollectorConcurrency is the default number of collectors executed in parallel.
+	defaultCollectorConcurrency = 4
+)
+
+// collectorTask is a unit of inventory collection,
+//
+// the fetch method executes the collector and returns a merge method which records
+// the collected data in the device object, tasks are fetched concurrently and merged in order.
+type collectorTask struct {
+	// errMsg is the message the task error is wrapped with.
+	errMsg string
+
+	// collector is the utility executed by the task,
+	// tasks sharing a collector are not executed concurrently.
+	collector UtilAttributeGetter
+
+	// fetch executes the collector, the merge method returned is nil when there are no changes to merge.
+	fetch func(ctx context.Context) (merge mergeFunc, err error)
+}
+
+// mergeFunc records collected data in the device object and returns the number of components added or updated.
+type mergeFunc func() (touched int, err error)
+
+// collectorTaskResult is the result of a fetched collectorTask.
+type collectorTaskResult struct {
+	merge    mergeFunc
+	err      error
+	duration time.Duration
+}
+
+// WithCollectorConcurrency sets the number of collectors executed in parallel,
+// collectors are executed sequentially when set to 1.
+func WithCollectorConcurrency(n int) Option {
+	return func(a *InventoryCollectorAction) {
+		a.collectorConcurrency = n
+	}
+}
+
+// WithCollectorTimeout sets the maximum duration a collector may run for,
+// a collector exceeding the timeout has its context canceled.
+func WithCollectorTimeout(timeout time.Duration) Option {
+	return func(a *InventoryCollectorAction) {
+		a.collectorTimeout = timeout
+	}
+}
+
+// collect executes the collector tasks concurrently and merges the collected data into the device,
+// the data is merged in the order of the tasks given regardless of the order the collectors complete in.
+//
+// When failOnError is set, the first task error stops any further data being merged,
+// otherwise the task error is logged and the remaining tasks are merged.
+// Each of the tasks is recorded in the collection report and the first task error is returned.
+func (a *InventoryCollectorAction) collect(ctx context.Context, tasks ...*collectorTask) error {
+	tasks = nonNilTasks(tasks)
+	results := a.fetch(ctx, tasks)
+
+	var firstErr error
+
+	for idx, task := range tasks {
+		var touched int
+
+		err := results[idx].err
+		if err == nil && results[idx].merge != nil && (firstErr == nil || !a.failOnError) {
+			touched, err = mergeTask(results[idx].merge)
+		}
+
+		a.reportCollector(task.collector, results[idx].duration, touched, err)
+
+		if err == nil {
+			continue
+		}
+
+		err = errors.Wrap(err, task.errMsg)
+		if !a.failOnError {
+			a.log.WithError(err).Warn("inventory collector error")
+		}
+
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+// fetch executes the fetch method of the tasks with bounded concurrency and returns their results in the order of the tasks.
+func (a *InventoryCollectorAction) fetch(ctx context.Context, tasks []*collectorTask) []collectorTaskResult {
+	results := make([]collectorTaskResult, len(tasks))
+	locks := collectorLocks(tasks)
+
+	concurrency := a.collectorConcurrency
+	if concurrency <= 0 {
+		concurrency = defaultCollectorConcurrency
+	}
+
+	sem := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+
+	for idx, task := range tasks {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			// tasks sharing a collector are serialized since the collector executor is not safe for concurrent use.
+			if lock := locks[idx]; lock != nil {
+				lock.Lock()
+				defer lock.Unlock()
+			}
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			results[idx] = a.fetchTask(ctx, task)
+		}()
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+// fetchTask executes the task fetch method with the collector timeout applied.
+func (a *InventoryCollectorAction) fetchTask(ctx context.Context, task *collectorTask) (result collectorTaskResult) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = collectorTaskResult{err: errors.Wrap(ErrPanic, string(debug.Stack()))}
+		}
+	}()
+
+	if a.collectorTimeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, a.collectorTimeout)
+		defer cancel()
+	}
+
+	started := time.Now()
+	merge, err := task.fetch(ctx)
+
+	return collectorTaskResult{merge: merge, err: err, duration: time.Since(started)}
+}
+
+// mergeTask invokes the merge method recovering from any panic.
+func mergeTask(merge mergeFunc) (touched int, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Wrap(ErrPanic, string(debug.Stack()))
+		}
+	}()
+
+	return merge()
+}
+
+// collectorLocks returns a mutex for each task, tasks sharing a collector are given the same mutex.
+func collectorLocks(tasks []*collectorTask) []*sync.Mutex {
+	locks := make([]*sync.Mutex, len(tasks))
+	shared := map[UtilAttributeGetter]*sync.Mutex{}
+
+	for idx, task := range tasks {
+		if task.collector == nil || !reflect.TypeOf(task.collector).Comparable() {
+			continue
+		}
+
+		if _, exists := shared[task.collector]; !exists {
+			shared[task.collector] = &sync.Mutex{}
+		}
+
+		locks[idx] = shared[task.collector]
+	}
+
+	return locks
+}
+
+func nonNilTasks(tasks []*collectorTask) []*collectorTask {
+	filtered := make([]*collectorTask, 0, len(tasks))
+
+	for _, task := range tasks {
+		if task != nil {
+			filtered = append(filtered, task)
+		}
+	}
+
+	return filtered
+}
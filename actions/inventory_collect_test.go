@@ -0,0 +1,167 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	common "github.com/metal-toolbox/bmc-common"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metal-toolbox/ironlib/model"
+	"github.com/metal-toolbox/ironlib/utils"
+)
+
+// fakeDriveCollector returns its drives after the delay given,
+// it tracks the number of collectors executing at the same time in the shared counter.
+type fakeDriveCollector struct {
+	name    string
+	delay   time.Duration
+	drives  []*common.Drive
+	err     error
+	running *runningCounter
+}
+
+type runningCounter struct {
+	mu      sync.Mutex
+	current int
+	max     int
+}
+
+func (r *runningCounter) inc() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.current++
+	r.max = max(r.max, r.current)
+}
+
+func (r *runningCounter) dec() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.current--
+}
+
+func (f *fakeDriveCollector) Attributes() (model.CollectorUtility, string, error) {
+	return model.CollectorUtility(f.name), "/usr/sbin/" + f.name, nil
+}
+
+func (f *fakeDriveCollector) Drives(ctx context.Context) ([]*common.Drive, error) {
+	if f.running != nil {
+		f.running.inc()
+		defer f.running.dec()
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(f.delay):
+	}
+
+	return f.drives, f.err
+}
+
+func newFakeDrive(serial, model string) *common.Drive {
+	return &common.Drive{Common: common.Common{Serial: serial, Model: model}}
+}
+
+func Test_CollectDrivesMergeOrder(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	// the first collector completes last, the drives are still merged in the collector order,
+	// when merged in the order of completion the drive order and model would differ.
+	collectors := &Collectors{
+		DriveCollectors: []DriveCollector{
+			&fakeDriveCollector{name: "slow", delay: 50 * time.Millisecond, drives: []*common.Drive{newFakeDrive("a", "slow-model")}},
+			&fakeDriveCollector{name: "fast", drives: []*common.Drive{newFakeDrive("b", "fast-model"), newFakeDrive("a", "fast-model")}},
+		},
+	}
+
+	a := NewInventoryCollectorAction(logger, WithCollectors(collectors), WithCollectorConcurrency(2))
+	a.device = &common.Device{}
+
+	require.NoError(t, a.CollectDrives(context.Background()))
+
+	assert.Equal(t, []*common.Drive{newFakeDrive("a", "fast-model"), newFakeDrive("b", "fast-model")}, a.device.Drives)
+}
+
+func Test_CollectConcurrency(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	running := &runningCounter{}
+	shared := &fakeDriveCollector{name: "shared", delay: 10 * time.Millisecond, running: &runningCounter{}}
+
+	collectors := &Collectors{}
+	for range 6 {
+		collectors.DriveCollectors = append(collectors.DriveCollectors, &fakeDriveCollector{name: "c", delay: 10 * time.Millisecond, running: running})
+	}
+
+	// a collector shared between tasks is never executed concurrently
+	collectors.DriveCollectors = append(collectors.DriveCollectors, shared, shared, shared)
+
+	a := NewInventoryCollectorAction(logger, WithCollectors(collectors), WithCollectorConcurrency(3))
+	a.device = &common.Device{}
+
+	require.NoError(t, a.CollectDrives(context.Background()))
+
+	assert.Equal(t, 3, running.max)
+	assert.Equal(t, 1, shared.running.max)
+}
+
+func Test_CollectTimeout(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	collectors := &Collectors{
+		DriveCollectors: []DriveCollector{
+			&fakeDriveCollector{name: "hung", delay: time.Minute},
+			&fakeDriveCollector{name: "ok", drives: []*common.Drive{newFakeDrive("a", "model")}},
+		},
+	}
+
+	a := NewInventoryCollectorAction(logger, WithCollectors(collectors), WithCollectorTimeout(10*time.Millisecond))
+	a.device = &common.Device{}
+
+	err := a.CollectDrives(context.Background())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// data from the remaining collectors is merged when failOnError is not set
+	assert.Equal(t, []*common.Drive{newFakeDrive("a", "model")}, a.device.Drives)
+
+	a = NewInventoryCollectorAction(logger, WithCollectors(collectors), WithCollectorTimeout(10*time.Millisecond), WithFailOnError())
+	a.device = &common.Device{}
+
+	err = a.CollectDrives(context.Background())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Empty(t, a.device.Drives)
+}
+
+func Test_CollectVirtualDisks(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	mdadm, _ := utils.NewFakeMdadm("../fixtures/utils/mdadm")
+
+	a := NewInventoryCollectorAction(logger, WithCollectors(&Collectors{VirtualDiskCollectors: []VirtualDiskCollector{mdadm}}))
+	a.device = &common.Device{}
+
+	// the virtual disks are not listed again when collected more than once
+	for range 2 {
+		require.NoError(t, a.CollectVirtualDisks(context.Background()))
+	}
+
+	virtualDisks := []*common.VirtualDisk{}
+	require.NoError(t, json.Unmarshal([]byte(a.device.Metadata[model.DeviceMetadataVirtualDisks]), &virtualDisks))
+
+	require.Len(t, virtualDisks, 2)
+	assert.Equal(t, "raid0", virtualDisks[0].RaidType)
+	assert.Equal(t, "os", virtualDisks[1].Name)
+	assert.Equal(t, "degraded", virtualDisks[1].Status)
+}
@@ -0,0 +1,202 @@
+package actions
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	common "github.com/metal-toolbox/bmc-common"
+	"github.com/pkg/errors"
+	"github.com/r3labs/diff/v3"
+
+	"github.com/metal-toolbox/ironlib/model"
+)
+
+var ErrInventoryDiffDeviceNil = errors.New("inventory diff requires both device snapshots")
+
+// commonType is the type of the component attributes embedded in the inventory component types
+var commonType = reflect.TypeOf(common.Common{})
+
+// inventoryComponent is a component from an inventory snapshot and its identifier
+type inventoryComponent struct {
+	id       *model.InventoryComponent
+	firmware *common.Firmware
+	value    any
+}
+
+// DiffInventory compares two device inventory snapshots,
+// for example collected before and after a firmware update, and returns the components added, removed and changed.
+//
+// Components are matched by their type and serial, falling back to the logical name
+// and then a type specific identifier when the serial is not available.
+//
+// The component Metadata is not compared, it includes values changing between collections
+// like the drive health metrics and the virtual disks on a drive.
+func DiffInventory(before, after *common.Device) (*model.InventoryDiff, error) {
+	if before == nil || after == nil {
+		return nil, ErrInventoryDiffDeviceNil
+	}
+
+	beforeComponents := inventoryComponents(before)
+	afterComponents := inventoryComponents(after)
+
+	afterIndex := make(map[string]*inventoryComponent, len(afterComponents))
+	for _, c := range afterComponents {
+		afterIndex[c.id.Slug+"/"+c.id.Key] = c
+	}
+
+	result := &model.InventoryDiff{}
+	matched := map[string]bool{}
+
+	for _, b := range beforeComponents {
+		k := b.id.Slug + "/" + b.id.Key
+
+		a, exists := afterIndex[k]
+		if !exists {
+			result.Removed = append(result.Removed, b.id)
+			continue
+		}
+
+		matched[k] = true
+
+		changelog, err := diff.Diff(b.value, a.value, diff.Filter(excludeMetadata))
+		if err != nil {
+			return nil, errors.Wrap(err, "error comparing "+k)
+		}
+
+		if len(changelog) > 0 {
+			change := &model.InventoryComponentChange{InventoryComponent: *a.id}
+			for _, c := range changelog {
+				// fields promoted from the embedded common.Common type are reported without the Common prefix
+				path := c.Path
+				if len(path) > 1 && path[0] == "Common" {
+					path = path[1:]
+				}
+
+				change.Fields = append(change.Fields, &model.InventoryFieldChange{Path: strings.Join(path, "."), From: c.From, To: c.To})
+			}
+
+			result.Changed = append(result.Changed, change)
+		}
+
+		if from, to := firmwareInstalled(b.firmware), firmwareInstalled(a.firmware); from != to {
+			result.Firmware = append(result.Firmware, &model.FirmwareDelta{InventoryComponent: *a.id, From: from, To: to})
+		}
+	}
+
+	for _, a := range afterComponents {
+		if !matched[a.id.Slug+"/"+a.id.Key] {
+			result.Added = append(result.Added, a.id)
+		}
+	}
+
+	return result, nil
+}
+
+// excludeMetadata is the diff filter to skip the component Metadata
+func excludeMetadata(_ []string, parent reflect.Type, field reflect.StructField) bool {
+	return parent != commonType || field.Name != "Metadata"
+}
+
+func firmwareInstalled(firmware *common.Firmware) string {
+	if firmware == nil {
+		return ""
+	}
+
+	return firmware.Installed
+}
+
+// inventoryComponents returns the device components with their identifiers
+func inventoryComponents(device *common.Device) []*inventoryComponent {
+	components := []*inventoryComponent{}
+	keys := map[string]int{}
+
+	add := func(slug string, c *common.Common, value any, fallback string) {
+		key := c.Serial
+		if key == "" {
+			key = c.LogicalName
+		}
+
+		if key == "" {
+			key = fallback
+		}
+
+		// components sharing a key are told apart by their order in the snapshot
+		keys[slug+"/"+key]++
+		if n := keys[slug+"/"+key]; n > 1 {
+			key = fmt.Sprintf("%s#%d", key, n)
+		}
+
+		components = append(components, &inventoryComponent{
+			id:       &model.InventoryComponent{Slug: slug, Key: key, Vendor: c.Vendor, Model: c.Model, Serial: c.Serial},
+			firmware: c.Firmware,
+			value:    value,
+		})
+	}
+
+	if device.BIOS != nil {
+		add(common.SlugBIOS, &device.BIOS.Common, device.BIOS, common.SlugBIOS)
+	}
+
+	if device.BMC != nil {
+		add(common.SlugBMC, &device.BMC.Common, device.BMC, common.SlugBMC)
+	}
+
+	if device.Mainboard != nil {
+		add(common.SlugMainboard, &device.Mainboard.Common, device.Mainboard, common.SlugMainboard)
+	}
+
+	for _, c := range device.CPLDs {
+		add(common.SlugCPLD, &c.Common, c, common.SlugCPLD)
+	}
+
+	for _, c := range device.TPMs {
+		add(common.SlugTPM, &c.Common, c, common.SlugTPM)
+	}
+
+	for _, c := range device.GPUs {
+		add(common.SlugGPU, &c.Common, c, common.SlugGPU)
+	}
+
+	for _, c := range device.CPUs {
+		add(common.SlugCPU, &c.Common, c, firstNonEmpty(c.Slot, c.ID))
+	}
+
+	for _, c := range device.Memory {
+		add(common.SlugPhysicalMem, &c.Common, c, firstNonEmpty(c.Slot, c.ID))
+	}
+
+	for _, c := range device.NICs {
+		add(common.SlugNIC, &c.Common, c, c.ID)
+	}
+
+	for _, c := range device.Drives {
+		add(common.SlugDrive, &c.Common, c, c.ID)
+	}
+
+	for _, c := range device.StorageControllers {
+		add(common.SlugStorageController, &c.Common, c, c.ID)
+	}
+
+	for _, c := range device.PSUs {
+		add(common.SlugPSU, &c.Common, c, c.ID)
+	}
+
+	for _, c := range device.Enclosures {
+		add(common.SlugEnclosure, &c.Common, c, c.ID)
+		// the enclosure firmware field shadows the common firmware field
+		components[len(components)-1].firmware = c.Firmware
+	}
+
+	return components
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,72 @@
+package actions
+
+import (
+	"testing"
+
+	common "github.com/metal-toolbox/bmc-common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/metal-toolbox/ironlib/model"
+)
+
+func Test_DiffInventory(t *testing.T) {
+	before := common.NewDevice()
+	before.BIOS.Firmware = &common.Firmware{Installed: "2.2.4"}
+	before.Drives = []*common.Drive{
+		{Common: common.Common{Serial: "S1", Model: "Micron_5200", Firmware: &common.Firmware{Installed: "D1MU020"}}},
+		{Common: common.Common{Serial: "S2", Model: "Micron_5200"}},
+		{Common: common.Common{
+			Serial:   "S4",
+			Model:    "Micron_5200",
+			Metadata: map[string]string{model.DriveHealthPowerOnHours: "1200", model.DriveHealthTemperature: "31"},
+		}},
+		{Common: common.Common{LogicalName: "/dev/nvme0n1", Model: "KXG60ZNV256G"}},
+	}
+	before.Memory = []*common.Memory{{Slot: "DIMM.A1", SizeBytes: 32 << 30}}
+
+	after := common.NewDevice()
+	after.BIOS.Firmware = &common.Firmware{Installed: "2.3.6"}
+	after.Drives = []*common.Drive{
+		{Common: common.Common{Serial: "S3", Model: "Micron_5300"}},
+		{Common: common.Common{Serial: "S1", Model: "Micron_5200", Firmware: &common.Firmware{Installed: "D1MU404"}}},
+		// the drive health metrics changing between collections is not a change
+		{Common: common.Common{
+			Serial:   "S4",
+			Model:    "Micron_5200",
+			Metadata: map[string]string{model.DriveHealthPowerOnHours: "1206", model.DriveHealthTemperature: "34"},
+		}},
+		{Common: common.Common{LogicalName: "/dev/nvme0n1", Model: "KXG60ZNV256G"}},
+	}
+	after.Memory = []*common.Memory{{Slot: "DIMM.A1", SizeBytes: 64 << 30}}
+
+	got, err := DiffInventory(&before, &after)
+	assert.NoError(t, err)
+	assert.False(t, got.Empty())
+
+	assert.Equal(t, []*model.InventoryComponent{{Slug: common.SlugDrive, Key: "S3", Model: "Micron_5300", Serial: "S3"}}, got.Added)
+	assert.Equal(t, []*model.InventoryComponent{{Slug: common.SlugDrive, Key: "S2", Model: "Micron_5200", Serial: "S2"}}, got.Removed)
+
+	assert.Equal(t, []*model.FirmwareDelta{
+		{InventoryComponent: model.InventoryComponent{Slug: common.SlugBIOS, Key: common.SlugBIOS}, From: "2.2.4", To: "2.3.6"},
+		{InventoryComponent: model.InventoryComponent{Slug: common.SlugDrive, Key: "S1", Model: "Micron_5200", Serial: "S1"}, From: "D1MU020", To: "D1MU404"},
+	}, got.Firmware)
+
+	changed := map[string][]*model.InventoryFieldChange{}
+	for _, c := range got.Changed {
+		changed[c.Slug+"/"+c.Key] = c.Fields
+	}
+
+	assert.Equal(t, map[string][]*model.InventoryFieldChange{
+		"BIOS/BIOS":              {{Path: "Firmware.Installed", From: "2.2.4", To: "2.3.6"}},
+		"Drive/S1":               {{Path: "Firmware.Installed", From: "D1MU020", To: "D1MU404"}},
+		"PhysicalMemory/DIMM.A1": {{Path: "SizeBytes", From: int64(32 << 30), To: int64(64 << 30)}},
+	}, changed)
+
+	// no changes
+	got, err = DiffInventory(&after, &after)
+	assert.NoError(t, err)
+	assert.True(t, got.Empty())
+
+	_, err = DiffInventory(nil, &after)
+	assert.ErrorIs(t, err, ErrInventoryDiffDeviceNil)
+}
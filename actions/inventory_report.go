@@ -0,0 +1,114 @@
+package actions
+
+import (
+	"time"
+
+	common "github.com/metal-toolbox/bmc-common"
+	"github.com/r3labs/diff/v3"
+
+	"github.com/metal-toolbox/ironlib/model"
+)
+
+// WithCollectionReport sets the report the collectors executed are recorded in,
+// the report is populated when Collect returns, including when Collect returns an error.
+func WithCollectionReport(report *model.CollectionReport) Option {
+	return func(a *InventoryCollectorAction) {
+		a.report = report
+	}
+}
+
+// reportCollector records the collector result in the collection report,
+// results of a collector executed more than once - for example once for each drive, are added up into a single entry.
+func (a *InventoryCollectorAction) reportCollector(collector UtilAttributeGetter, duration time.Duration, touched int, err error) {
+	if a.report == nil {
+		return
+	}
+
+	var name model.CollectorUtility
+	var path string
+
+	if collector != nil {
+		name, path, _ = collector.Attributes()
+	}
+
+	var entry *model.CollectorReport
+
+	for _, existing := range a.report.Collectors {
+		if existing.Name == name && existing.Path == path {
+			entry = existing
+			break
+		}
+	}
+
+	if entry == nil {
+		entry = &model.CollectorReport{Name: name, Path: path}
+		a.report.Collectors = append(a.report.Collectors, entry)
+	}
+
+	entry.Duration += duration
+	entry.ComponentsTouched += touched
+
+	if err != nil {
+		if entry.Error != "" {
+			entry.Error += "; "
+		}
+
+		entry.Error += err.Error()
+	}
+}
+
+// changedComponents returns the number of slice elements changed in the changelog
+// of a diff between two slices of components.
+func changedComponents(changelog diff.Changelog) int {
+	changed := map[string]struct{}{}
+
+	for _, change := range changelog {
+		if len(change.Path) > 0 {
+			changed[change.Path[0]] = struct{}{}
+		}
+	}
+
+	return len(changed)
+}
+
+// changedComponent returns 1 when the changelog of a diff between two components includes changes.
+func changedComponent(changelog diff.Changelog) int {
+	if len(changelog) > 0 {
+		return 1
+	}
+
+	return 0
+}
+
+// deviceComponents returns the number of components with data in the device.
+func deviceComponents(device *common.Device) int {
+	count := len(device.CPLDs) +
+		len(device.TPMs) +
+		len(device.GPUs) +
+		len(device.CPUs) +
+		len(device.Memory) +
+		len(device.NICs) +
+		len(device.Drives) +
+		len(device.StorageControllers) +
+		len(device.PSUs) +
+		len(device.Enclosures)
+
+	if device.BIOS != nil && populated(&device.BIOS.Common) {
+		count++
+	}
+
+	if device.BMC != nil && populated(&device.BMC.Common) {
+		count++
+	}
+
+	if device.Mainboard != nil && populated(&device.Mainboard.Common) {
+		count++
+	}
+
+	return count
+}
+
+// populated returns true when the component includes identifying or firmware data
+func populated(c *common.Common) bool {
+	return c.Vendor != "" || c.Model != "" || c.Serial != "" || (c.Firmware != nil && c.Firmware.Installed != "")
+}
@@ -0,0 +1,102 @@
+package actions
+
+import (
+	"context"
+	"testing"
+
+	common "github.com/metal-toolbox/bmc-common"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metal-toolbox/ironlib/model"
+	"github.com/metal-toolbox/ironlib/utils"
+)
+
+func Test_CollectionReport(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	errSmartctl := errors.New("smartctl exited with code 2")
+
+	collectors := &Collectors{
+		InventoryCollector: &utils.Sysfs{Root: "../fixtures/utils/sysfs"},
+		DriveCollectors: []DriveCollector{
+			&fakeDriveCollector{name: "smartctl", err: errSmartctl},
+			&fakeDriveCollector{
+				name: "lsblk",
+				drives: []*common.Drive{
+					// updates the existing drive
+					{Common: common.Common{Serial: "18371E8B5A31", Description: "ATA Micron 5200 Series SSD"}},
+					// adds a drive
+					{Common: common.Common{Serial: "NEW0001", LogicalName: "/dev/sdc"}},
+				},
+			},
+		},
+	}
+
+	report := &model.CollectionReport{}
+
+	a := NewInventoryCollectorAction(
+		logger,
+		WithCollectors(collectors),
+		WithCollectionReport(report),
+		WithDisabledCollectorUtilities([]model.CollectorUtility{"dmidecode", "hdparm", "nvme", "sg3_utils"}),
+	)
+
+	device := common.NewDevice()
+	require.NoError(t, a.Collect(context.Background(), &device))
+
+	assert.False(t, report.Complete())
+	assert.False(t, report.Started.IsZero())
+	assert.False(t, report.Ended.Before(report.Started))
+
+	require.Len(t, report.Collectors, 3)
+
+	assert.Equal(t, model.CollectorUtility("sysfs"), report.Collectors[0].Name)
+	assert.Equal(t, "../fixtures/utils/sysfs", report.Collectors[0].Path)
+	// 2 storage controllers, 1 GPU, 2 NICs, 3 drives and 2 CPUs
+	assert.Equal(t, 10, report.Collectors[0].ComponentsTouched)
+	assert.Empty(t, report.Collectors[0].Error)
+
+	assert.Equal(t, model.CollectorUtility("smartctl"), report.Collectors[1].Name)
+	assert.Equal(t, "/usr/sbin/smartctl", report.Collectors[1].Path)
+	assert.Contains(t, report.Collectors[1].Error, errSmartctl.Error())
+	assert.Equal(t, 0, report.Collectors[1].ComponentsTouched)
+
+	assert.Equal(t, model.CollectorUtility("lsblk"), report.Collectors[2].Name)
+	assert.Equal(t, 2, report.Collectors[2].ComponentsTouched)
+	assert.Empty(t, report.Collectors[2].Error)
+
+	assert.Equal(t, []*model.CollectorReport{report.Collectors[1]}, report.Failed())
+}
+
+func Test_CollectionReportFailOnError(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	collectors := &Collectors{
+		InventoryCollector: &utils.Sysfs{Root: t.TempDir()},
+		DriveCollectors:    []DriveCollector{&fakeDriveCollector{name: "lsblk"}},
+	}
+
+	report := &model.CollectionReport{}
+
+	a := NewInventoryCollectorAction(
+		logger,
+		WithCollectors(collectors),
+		WithCollectionReport(report),
+		WithDisabledCollectorUtilities([]model.CollectorUtility{"dmidecode"}),
+		WithFailOnError(),
+	)
+
+	err := a.Collect(context.Background(), nil)
+	assert.ErrorIs(t, err, utils.ErrSysfsCollect)
+
+	// the report is populated with the collectors executed up to the error
+	require.Len(t, report.Collectors, 1)
+	assert.Equal(t, model.CollectorUtility("sysfs"), report.Collectors[0].Name)
+	assert.NotEmpty(t, report.Collectors[0].Error)
+	assert.False(t, report.Ended.IsZero())
+}
@@ -179,6 +179,11 @@ func TestNewInventoryCollectorAction(t *testing.T) {
 			[]Option{},
 			&InventoryCollectorAction{},
 		},
+		{
+			"lshw-disabled-sysfs-collector",
+			[]Option{WithDisabledCollectorUtilities([]model.CollectorUtility{"lshw"})},
+			&InventoryCollectorAction{},
+		},
 		{
 			"default-drive-collectors",
 			[]Option{},
@@ -211,6 +216,8 @@ func TestNewInventoryCollectorAction(t *testing.T) {
 				assert.Equal(t, false, got.collectors.Empty())
 			case "default-lshw-collector":
 				assert.Equal(t, false, got.collectors.InventoryCollector == nil)
+			case "lshw-disabled-sysfs-collector":
+				assert.IsType(t, &utils.Sysfs{}, got.collectors.InventoryCollector)
 			case "default-drive-collector":
 				assert.Equal(t, false, len(got.collectors.DriveCollectors) == 0)
 			case "default-drive-capabilities-collector":
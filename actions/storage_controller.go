@@ -2,7 +2,6 @@ package actions
 
 import (
 	"context"
-	"fmt"
 	"strings"
 
 	common "github.com/metal-toolbox/bmc-common"
@@ -16,28 +15,71 @@ import (
 var ErrVirtualDiskManagerUtilNotIdentified = errors.New("virtual disk management utility not identifed")
 
 type StorageControllerAction struct {
-	Logger *logrus.Logger
-	trace  bool
+	Logger               *logrus.Logger
+	trace                bool
+	forbiddenWipeMethods []WipeMethod
+	wipeJournalDir       string
 }
 
-func NewStorageControllerAction(logger *logrus.Logger) *StorageControllerAction {
-	return &StorageControllerAction{
+// StorageControllerOption returns a function that sets a StorageControllerAction parameter
+type StorageControllerOption func(*StorageControllerAction)
+
+// WithForbiddenWipeMethods excludes the given wipe methods from the methods used to wipe drives,
+// for example WipeMethodBlkdiscard to forbid TRIM only wipes.
+func WithForbiddenWipeMethods(methods ...WipeMethod) StorageControllerOption {
+	return func(s *StorageControllerAction) {
+		s.forbiddenWipeMethods = methods
+	}
+}
+
+// WithWipeJournalDir sets the directory to persist the progress of zero fill wipes to,
+// so an interrupted wipe can be resumed. Each drive is journaled to a file named by the drive serial.
+func WithWipeJournalDir(dir string) StorageControllerOption {
+	return func(s *StorageControllerAction) {
+		s.wipeJournalDir = dir
+	}
+}
+
+func NewStorageControllerAction(logger *logrus.Logger, options ...StorageControllerOption) *StorageControllerAction {
+	s := &StorageControllerAction{
 		Logger: logger,
 		trace:  logger.Level >= logrus.TraceLevel,
 	}
+
+	for _, opt := range options {
+		opt(s)
+	}
+
+	return s
+}
+
+// SoftwareRAIDController returns the storage controller Linux software RAID md arrays are managed through,
+// the md array member drives are set by their logical names in the CreateVirtualDiskOptions.PhysicalDiskNames.
+func SoftwareRAIDController() *common.StorageController {
+	return &common.StorageController{
+		Common: common.Common{
+			Vendor:      utils.MdadmVendor,
+			Model:       utils.MdadmModel,
+			Description: "Linux software RAID",
+		},
+	}
 }
 
 func (s *StorageControllerAction) CreateVirtualDisk(ctx context.Context, hba *common.StorageController, options *model.CreateVirtualDiskOptions) error {
-	util, err := s.GetControllerUtility(hba.Vendor, hba.Model)
+	util, err := s.controllerUtility(ctx, hba)
 	if err != nil {
 		return err
 	}
 
+	if mdadm, ok := util.(*utils.Mdadm); ok {
+		mdadm.SetDevices(options.PhysicalDiskNames...)
+	}
+
 	return util.CreateVirtualDisk(ctx, options.RaidMode, options.PhysicalDiskIDs, options.Name, options.BlockSize)
 }
 
 func (s *StorageControllerAction) DestroyVirtualDisk(ctx context.Context, hba *common.StorageController, options *model.DestroyVirtualDiskOptions) error {
-	util, err := s.GetControllerUtility(hba.Vendor, hba.Model)
+	util, err := s.controllerUtility(ctx, hba)
 	if err != nil {
 		return err
 	}
@@ -46,64 +88,91 @@ func (s *StorageControllerAction) DestroyVirtualDisk(ctx context.Context, hba *c
 }
 
 func (s *StorageControllerAction) ListVirtualDisks(ctx context.Context, hba *common.StorageController) ([]*common.VirtualDisk, error) {
-	util, err := s.GetControllerUtility(hba.Vendor, hba.Model)
+	util, err := s.controllerUtility(ctx, hba)
 	if err != nil {
 		return nil, err
 	}
 
-	virtualDisks, err := util.VirtualDisks(ctx)
+	return util.ListVirtualDisks(ctx)
+}
+
+// GetControllerUtility returns the utility command for the given vendor
+func (s *StorageControllerAction) GetControllerUtility(vendorName, modelName string) (VirtualDiskManager, error) {
+	if strings.EqualFold(vendorName, common.VendorMarvell) {
+		return utils.NewMvcliCmd(s.trace), nil
+	}
+
+	if isStoreCLIVendor(vendorName) {
+		return utils.NewStoreCLICmd(s.trace), nil
+	}
+
+	if strings.EqualFold(vendorName, utils.MdadmVendor) && strings.EqualFold(modelName, utils.MdadmModel) {
+		return utils.NewMdadmCmd(s.trace), nil
+	}
+
+	return nil, errors.Wrap(ErrVirtualDiskManagerUtilNotIdentified, "vendor: "+vendorName+" model: "+modelName)
+}
+
+// controllerUtility returns the utility command for the storage controller,
+// storecli is set to manage the virtual disks on the storage controller - see setVirtualDiskController.
+func (s *StorageControllerAction) controllerUtility(ctx context.Context, hba *common.StorageController) (VirtualDiskManager, error) {
+	util, err := s.GetControllerUtility(hba.Vendor, hba.Model)
 	if err != nil {
 		return nil, err
 	}
 
-	cVirtualDisks := []*common.VirtualDisk{}
-
-	for _, vd := range virtualDisks {
-		cVirtualDisks = append(cVirtualDisks, &common.VirtualDisk{
-			ID:       fmt.Sprintf("%d", vd.ID),
-			Name:     vd.Name,
-			RaidType: vd.Type,
-		})
+	if err := setVirtualDiskController(ctx, util, hba); err != nil {
+		return nil, err
 	}
 
-	return cVirtualDisks, nil
+	return util, nil
 }
 
-// GetControllerUtility returns the utility command for the given vendor
-func (s *StorageControllerAction) GetControllerUtility(vendorName, modelName string) (VirtualDiskManager, error) {
-	if strings.EqualFold(vendorName, common.VendorMarvell) {
-		return utils.NewMvcliCmd(s.trace), nil
+// setVirtualDiskController sets storecli to manage the virtual disks on the controller whose /cN index
+// is looked up by the storage controller serial or PCI bus address.
+//
+// The storage controller ID is used as the index only when neither is known,
+// since it is set as the storcli index just by the storcli inventory collector.
+func setVirtualDiskController(ctx context.Context, util VirtualDiskManager, hba *common.StorageController) error {
+	storecli, ok := util.(*utils.StoreCLI)
+	if !ok {
+		return nil
 	}
 
-	return nil, errors.Wrap(ErrVirtualDiskManagerUtilNotIdentified, "vendor: "+vendorName+" model: "+modelName)
+	switch {
+	case hba.Serial != "" || hba.BusInfo != "":
+		return storecli.ResolveController(ctx, hba.Serial, hba.BusInfo)
+	case hba.ID != "":
+		storecli.SetController(hba.ID)
+	}
+
+	return nil
 }
 
 // GetWipeUtility returns the wipe utility based on the disk wipping features
+//
+// The wipe utility returned tries the wipe methods applicable to the drive protocol and capabilities
+// in order of preference, falling back to the next method when one fails.
 func (s *StorageControllerAction) GetWipeUtility(drive *common.Drive) (DriveWiper, error) {
 	s.Logger.Tracef("%s | Detecting wipe utility", drive.LogicalName)
-	// TODO: use disk wipping features to return the best wipe utility, currently only one available
-
-	return utils.NewFillZeroCmd(s.trace), nil
-}
 
-func (s *StorageControllerAction) WipeDrive(ctx context.Context, log *logrus.Logger, drive *common.Drive) error {
-	util, err := s.GetWipeUtility(drive)
+	chain, err := s.wipeChainForDrive(drive)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Watermark disk
-	// Before wiping the disk, we apply watermarks to later verify successful deletion
-	check, err := utils.ApplyWatermarks(drive)
-	if err != nil {
-		return err
-	}
+	s.Logger.WithField("drive", drive.LogicalName).WithField("wipe-methods", chain.Methods()).Trace("wipe methods identified")
 
-	// Wipe the disk
-	err = util.WipeDrive(ctx, log, drive)
+	return chain, nil
+}
+
+// WipeDrive wipes the drive with the wipe utility returned by GetWipeUtility,
+// each of the wipe methods applies watermarks before the wipe and verifies they were overwritten.
+func (s *StorageControllerAction) WipeDrive(ctx context.Context, log *logrus.Logger, drive *common.Drive) error {
+	util, err := s.GetWipeUtility(drive)
 	if err != nil {
 		return err
 	}
 
-	return check()
+	return util.WipeDrive(ctx, log, drive)
 }
@@ -0,0 +1,55 @@
+package actions
+
+import (
+	"context"
+	"testing"
+
+	common "github.com/metal-toolbox/bmc-common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/metal-toolbox/ironlib/utils"
+)
+
+func Test_SetVirtualDiskController(t *testing.T) {
+	testcases := []struct {
+		name       string
+		hba        *common.StorageController
+		controller string
+		err        error
+	}{
+		{
+			"lshw controller by serial",
+			&common.StorageController{Common: common.Common{Vendor: common.VendorBroadcom, Serial: "500304801C71E8D0"}},
+			"1",
+			nil,
+		},
+		{
+			"lshw controller by bus info",
+			&common.StorageController{Common: common.Common{Vendor: common.VendorBroadcom}, BusInfo: "pci@0000:65:00.0"},
+			"0",
+			nil,
+		},
+		{
+			"serial not listed",
+			&common.StorageController{Common: common.Common{Vendor: common.VendorBroadcom, Serial: "SKC0000000"}},
+			"",
+			utils.ErrStoreCLIControllerNotFound,
+		},
+		{
+			"storcli controller ID",
+			&common.StorageController{Common: common.Common{Vendor: common.VendorBroadcom}, ID: "1"},
+			"1",
+			nil,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			storecli := &utils.StoreCLI{Executor: utils.NewFakeStoreCLIExecutor("storecli", "../fixtures/utils/storecli")}
+
+			err := setVirtualDiskController(context.TODO(), storecli, tc.hba)
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.controller, storecli.Controller)
+		})
+	}
+}
@@ -2,6 +2,8 @@ package actions
 
 import (
 	"context"
+	"fmt"
+	"regexp"
 	"strings"
 
 	common "github.com/metal-toolbox/bmc-common"
@@ -15,6 +17,7 @@ import (
 var (
 	ErrUpdaterUtilNotIdentified = errors.New("updater utility not identifed")
 	ErrVendorComponentOptions   = errors.New("component vendor does not match update options vendor attribute")
+	ErrDryRunUnsupported        = errors.New("updater utility does not support dry run")
 )
 
 // Updaters is a struct acting as a registry of various hardware component updaters
@@ -23,55 +26,197 @@ type Updaters struct {
 	NICs               NICUpdater
 	BMC                BMCUpdater
 	BIOS               BIOSUpdater
+	CPLD               CPLDUpdater
 	StorageControllers StorageControllerUpdater
 }
 
+// updateSlugs are the component slugs with an update handler
+var updateSlugs = []string{
+	common.SlugBIOS,
+	common.SlugBMC,
+	common.SlugCPLD,
+	common.SlugDrive,
+	common.SlugNIC,
+	common.SlugStorageController,
+}
+
+// normalizeSlug returns the component slug in the case declared by the common package,
+// the slug is returned as is when it has no update handler.
+func normalizeSlug(slug string) string {
+	for _, s := range updateSlugs {
+		if strings.EqualFold(s, slug) {
+			return s
+		}
+	}
+
+	return slug
+}
+
+// controllerResolver is implemented by updaters that install the firmware on a controller identified by the updater index,
+// the index is resolved from the controller serial or PCI bus address since the inventory collectors do not set it.
+type controllerResolver interface {
+	ResolveController(ctx context.Context, serial, busInfo string) error
+}
+
+// dryRunSetter is implemented by updaters that can return the update commands instead of executing them
+type dryRunSetter interface {
+	SetDryRun(dryRun bool)
+}
+
+// setDryRun sets the updater dry run mode when the options DryRun is set,
+// ErrDryRunUnsupported is returned when the updater does not support it.
+func setDryRun(updater any, options *model.UpdateOptions) error {
+	if !options.DryRun {
+		return nil
+	}
+
+	setter, ok := updater.(dryRunSetter)
+	if !ok {
+		return errors.Wrap(ErrDryRunUnsupported, fmt.Sprintf("%T", updater))
+	}
+
+	setter.SetDryRun(true)
+
+	return nil
+}
+
+// UpdateComponent installs the firmware update for the component identified by the options slug,
+// the options are validated with PreflightUpdate before the firmware update utility is executed.
+//
+// When the options Verify is set, the component inventory is collected after the install
+// and the error returned indicates the status of the install - see UpdateResultError.
+//
+// When the options DryRun is set, the update commands are not executed and an *errs.UpdateDryRunError is returned
+// with the update commands and the inventory components they apply to.
 func UpdateComponent(ctx context.Context, device *common.Device, option *model.UpdateOptions) error {
-	var err error
+	_, err := installComponent(ctx, device, option)
+
+	return err
+}
+
+// installComponent installs the firmware update for the component identified by the options slug,
+// the update result is returned when the options Verify or DryRun is set.
+func installComponent(ctx context.Context, device *common.Device, option *model.UpdateOptions) (*model.UpdateResult, error) {
+	var update func() error
+
 	switch {
 	// Update BIOS
 	case strings.EqualFold(common.SlugBIOS, option.Slug):
-		err = UpdateBIOS(ctx, device.BIOS, option)
-		if err != nil {
-			return errors.Wrap(err, "error updating bios")
+		update = func() error {
+			return errors.Wrap(UpdateBIOS(ctx, device.BIOS, option), "error updating bios")
 		}
 
 	// Update Drive
 	case strings.EqualFold(common.SlugDrive, option.Slug):
-		err = UpdateDrive(ctx, device.Drives, option)
-		if err != nil {
-			return errors.Wrap(err, "error updating drive")
+		update = func() error {
+			return errors.Wrap(UpdateDrive(ctx, device.Drives, option), "error updating drive")
 		}
 
 	// Update NIC
 	case strings.EqualFold(common.SlugNIC, option.Slug):
-		err = UpdateNIC(ctx, device.NICs, option)
-		if err != nil {
-			return errors.Wrap(err, "error updating nic")
+		update = func() error {
+			return errors.Wrap(UpdateNIC(ctx, device.NICs, option), "error updating nic")
 		}
 
 	// Update BMC
 	case strings.EqualFold(common.SlugBMC, option.Slug):
-		err = UpdateBMC(ctx, device.BMC, option)
-		if err != nil {
-			return errors.Wrap(err, "error updating bmc")
+		update = func() error {
+			return errors.Wrap(UpdateBMC(ctx, device.BMC, option), "error updating bmc")
+		}
+
+	// Update StorageController
+	case strings.EqualFold(common.SlugStorageController, option.Slug):
+		update = func() error {
+			return errors.Wrap(UpdateStorageController(ctx, device.StorageControllers, option), "error updating storage controller")
+		}
+
+	// Update CPLD
+	case strings.EqualFold(common.SlugCPLD, option.Slug):
+		update = func() error {
+			return errors.Wrap(UpdateCPLD(ctx, device.CPLDs, option), "error updating cpld")
 		}
 	default:
-		return errors.Wrap(errs.ErrNoUpdateHandlerForComponent, "slug: "+option.Slug)
+		return nil, errors.Wrap(errs.ErrNoUpdateHandlerForComponent, "slug: "+option.Slug)
 	}
 
-	return nil
+	if err := PreflightUpdate(device, option); err != nil {
+		return nil, err
+	}
+
+	err := update()
+	if option.DryRun {
+		return dryRunResult(device, option, err)
+	}
+
+	if !option.Verify {
+		return nil, err
+	}
+
+	if err != nil && !errors.Is(err, utils.ErrRebootRequired) {
+		return nil, err
+	}
+
+	collectors, cerr := GetVerifyCollectors(option.Slug, option.Vendor)
+	if cerr != nil {
+		return nil, cerr
+	}
+
+	return verifyInstall(ctx, collectors, option, err)
 }
 
-// UpdateAll installs all updates based on given options, options acts as a filter
-func UpdateAll(ctx context.Context, device *common.Device, options []*model.UpdateOptions) error {
-	for _, option := range options {
-		if err := UpdateComponent(ctx, device, option); err != nil {
-			return err
+// verifyInstall verifies the firmware install with the inventory collected,
+// installErr is the error returned by the updater - the utils.ErrRebootRequired returned
+// for the firmware installed pending activation, the update is verified as staged in that case.
+func verifyInstall(ctx context.Context, collectors *Collectors, option *model.UpdateOptions, installErr error) (*model.UpdateResult, error) {
+	result, err := VerifyUpdate(ctx, collectors, option, errors.Is(installErr, utils.ErrRebootRequired))
+	if err != nil {
+		return nil, err
+	}
+
+	return result, UpdateResultError(result)
+}
+
+// dryRunResult returns the update result with the update commands returned by the updater in dry run mode,
+// the commands the updater returned without components are set to apply to the inventory components matched by the options.
+func dryRunResult(device *common.Device, option *model.UpdateOptions, err error) (*model.UpdateResult, error) {
+	dryRun := &errs.UpdateDryRunError{}
+	if !errors.As(err, &dryRun) {
+		return nil, err
+	}
+
+	targets, err := updateTargets(device, option)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, cmd := range dryRun.Commands {
+		if len(cmd.Components) == 0 {
+			cmd.Components = targets
 		}
 	}
 
-	return nil
+	result := &model.UpdateResult{
+		Slug:         option.Slug,
+		Vendor:       option.Vendor,
+		Model:        option.Model,
+		Serial:       option.Serial,
+		Version:      option.Version,
+		Status:       model.UpdateStatusDryRun,
+		Commands:     dryRun.Commands,
+		Requirements: postInstallRequirements(option),
+	}
+
+	return result, dryRun
+}
+
+// UpdateAll installs all updates based on given options, options acts as a filter
+//
+// The updates are installed in the order of the component dependencies for the device vendor with the UpdatePlanner,
+// the updates continue past failures and the updates of components requiring a failed component update are skipped.
+// The plan result includes the result of each of the updates and their merged post install requirements,
+// ErrUpdatePlanIncomplete is returned along with the plan result when one or more updates failed or were skipped.
+func UpdateAll(ctx context.Context, device *common.Device, options []*model.UpdateOptions) (*model.UpdatePlanResult, error) {
+	return NewUpdatePlanner().Install(ctx, device, options)
 }
 
 // UpdateRequirements returns requirements to be met before and after a firmware install,
@@ -114,6 +259,10 @@ func UpdateBMC(ctx context.Context, bmc *common.BMC, options *model.UpdateOption
 		return err
 	}
 
+	if err := setDryRun(updater, options); err != nil {
+		return err
+	}
+
 	return updater.UpdateBMC(ctx, options.UpdateFile, options.Model)
 }
 
@@ -137,6 +286,10 @@ func UpdateBIOS(ctx context.Context, bios *common.BIOS, options *model.UpdateOpt
 		return err
 	}
 
+	if err := setDryRun(updater, options); err != nil {
+		return err
+	}
+
 	return updater.UpdateBIOS(ctx, options.UpdateFile, options.Model)
 }
 
@@ -162,6 +315,10 @@ func UpdateNIC(ctx context.Context, nics []*common.NIC, options *model.UpdateOpt
 			return err
 		}
 
+		if err := setDryRun(updater, options); err != nil {
+			return err
+		}
+
 		return updater.UpdateNIC(ctx, options.UpdateFile, options.Model, options.ForceInstall)
 	}
 
@@ -189,8 +346,101 @@ func UpdateDrive(ctx context.Context, drives []*common.Drive, options *model.Upd
 			return err
 		}
 
+		if err := setDryRun(updater, options); err != nil {
+			return err
+		}
+
 		return updater.UpdateDrive(ctx, options.UpdateFile, options.Model, options.Serial)
 	}
 
 	return errors.Wrap(ErrUpdaterUtilNotIdentified, options.Vendor)
 }
+
+// GetCPLDUpdater returns the updater for the given vendor
+func GetCPLDUpdater(vendor string) (CPLDUpdater, error) {
+	if strings.EqualFold(vendor, common.VendorSupermicro) {
+		return utils.NewSupermicroSUM(true), nil
+	}
+
+	return nil, errors.Wrap(ErrUpdaterUtilNotIdentified, "vendor: "+vendor)
+}
+
+// UpdateCPLD identifies the cpld eligible for update from the inventory and runs the firmware update utility based on the cpld vendor
+func UpdateCPLD(ctx context.Context, cplds []*common.CPLD, options *model.UpdateOptions) error {
+	for _, cpld := range cplds {
+		cpldVendor := common.FormatVendorName(cpld.Vendor)
+		if !strings.EqualFold(common.FormatVendorName(options.Vendor), cpldVendor) {
+			continue
+		}
+
+		updater, err := GetCPLDUpdater(cpldVendor)
+		if err != nil {
+			return err
+		}
+
+		if err := setDryRun(updater, options); err != nil {
+			return err
+		}
+
+		return updater.UpdateCPLD(ctx, options.UpdateFile, options.Model)
+	}
+
+	return errors.Wrap(ErrUpdaterUtilNotIdentified, options.Vendor)
+}
+
+// sas3HBAModel matches the Broadcom/LSI SAS3 Fusion-MPT HBA models, these are updated with sas3flash
+var sas3HBAModel = regexp.MustCompile(`(?i)(-IT$|fusion-mpt|sas3[0-9]{3}|lsi3[0-9]{3}|hba)`)
+
+// GetStorageControllerUpdater returns the updater for the given vendor and model,
+// Broadcom/LSI MegaRAID controllers are updated with storecli and the SAS3 HBAs with sas3flash.
+func GetStorageControllerUpdater(vendor, modelName string) (StorageControllerUpdater, error) {
+	if strings.EqualFold(common.FormatVendorName(vendor), common.VendorMarvell) {
+		return utils.NewMvcliCmd(true), nil
+	}
+
+	if isStoreCLIVendor(vendor) {
+		if sas3HBAModel.MatchString(modelName) {
+			return utils.NewSas3flashCmd(true), nil
+		}
+
+		return utils.NewStoreCLICmd(true), nil
+	}
+
+	return nil, errors.Wrap(ErrUpdaterUtilNotIdentified, "vendor: "+vendor+" model: "+modelName)
+}
+
+// UpdateStorageController identifies the storage controller eligible for update from the inventory
+// and runs the firmware update utility based on the storage controller vendor and model.
+//
+// When the update options include a serial, the storage controller serial is required to match,
+// the firmware is installed on the first storage controller matched.
+func UpdateStorageController(ctx context.Context, controllers []*common.StorageController, options *model.UpdateOptions) error {
+	for _, controller := range controllers {
+		if !strings.EqualFold(common.FormatVendorName(options.Vendor), common.FormatVendorName(controller.Vendor)) {
+			continue
+		}
+
+		if options.Serial != "" && !strings.EqualFold(options.Serial, controller.Serial) {
+			continue
+		}
+
+		updater, err := GetStorageControllerUpdater(controller.Vendor, controller.Model)
+		if err != nil {
+			return err
+		}
+
+		if resolver, ok := updater.(controllerResolver); ok {
+			if err := resolver.ResolveController(ctx, controller.Serial, controller.BusInfo); err != nil {
+				return err
+			}
+		}
+
+		if err := setDryRun(updater, options); err != nil {
+			return err
+		}
+
+		return updater.UpdateStorageController(ctx, options.UpdateFile, options.Model)
+	}
+
+	return errors.Wrap(ErrUpdaterUtilNotIdentified, options.Vendor)
+}
@@ -0,0 +1,317 @@
+package actions
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	common "github.com/metal-toolbox/bmc-common"
+	"github.com/pkg/errors"
+
+	"github.com/metal-toolbox/ironlib/errs"
+	"github.com/metal-toolbox/ironlib/model"
+)
+
+var (
+	ErrFirmwareBundle         = errors.New("firmware bundle invalid")
+	ErrFirmwareBundleManifest = errors.New("firmware bundle manifest invalid")
+)
+
+// FirmwareBundle is a firmware bundle opened from a directory or a tarball
+type FirmwareBundle struct {
+	// Dir is the bundle root directory, a tarball is extracted into a temporary directory.
+	Dir string
+
+	// Manifest lists the firmware files in the bundle and the components they apply to.
+	Manifest *model.FirmwareBundleManifest
+
+	// extracted is set when Dir is a temporary directory removed on Close.
+	extracted bool
+}
+
+// InstallBundle installs the firmware bundle components applicable to the device inventory,
+// the bundle is a directory or a tarball with a manifest.json at its root - see model.FirmwareBundleManifest.
+//
+// The updates are installed in the order of the component dependencies - see UpdatePlanner,
+// the options ForceInstall, Verify and DryRun are applied to each of the bundle components installed.
+// errs.ErrNoUpdatesApplicable is returned when none of the bundle components apply to the device.
+func InstallBundle(ctx context.Context, device *common.Device, bundlePath string, option *model.UpdateOptions) (*model.UpdatePlanResult, error) {
+	bundle, err := OpenFirmwareBundle(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+
+	defer bundle.Close()
+
+	options, err := bundle.UpdateOptions(device, option)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(options) == 0 {
+		return nil, errors.Wrap(errs.ErrNoUpdatesApplicable, "bundle: "+bundlePath)
+	}
+
+	return NewUpdatePlanner().Install(ctx, device, options)
+}
+
+// OpenFirmwareBundle opens the firmware bundle directory or tarball and reads its manifest,
+// gzip compressed tarballs are supported. The caller is expected to Close the bundle.
+func OpenFirmwareBundle(bundlePath string) (*FirmwareBundle, error) {
+	info, err := os.Stat(bundlePath)
+	if err != nil {
+		return nil, errors.Wrap(ErrFirmwareBundle, err.Error())
+	}
+
+	bundle := &FirmwareBundle{Dir: bundlePath}
+
+	if !info.IsDir() {
+		dir, err := os.MkdirTemp("", "ironlib-bundle-")
+		if err != nil {
+			return nil, err
+		}
+
+		bundle.Dir = dir
+		bundle.extracted = true
+
+		if err := extractTarball(bundlePath, dir); err != nil {
+			bundle.Close()
+			return nil, err
+		}
+	}
+
+	bundle.Manifest, err = readBundleManifest(bundle.Dir)
+	if err != nil {
+		bundle.Close()
+		return nil, err
+	}
+
+	return bundle, nil
+}
+
+// Close removes the directory the bundle tarball was extracted into
+func (b *FirmwareBundle) Close() error {
+	if !b.extracted {
+		return nil
+	}
+
+	return os.RemoveAll(b.Dir)
+}
+
+// UpdateOptions returns the update options to install the bundle components applicable to the device inventory.
+//
+// A bundle component applies to the inventory components of its slug and vendor with a model matched by its model rules,
+// an update option is returned for each of the models matched unless the components of the model have the version installed.
+// The option ForceInstall, Verify and DryRun values are set on the update options returned.
+func (b *FirmwareBundle) UpdateOptions(device *common.Device, option *model.UpdateOptions) ([]*model.UpdateOptions, error) {
+	if option == nil {
+		option = &model.UpdateOptions{}
+	}
+
+	options := []*model.UpdateOptions{}
+
+	for _, component := range b.Manifest.Components {
+		rules, err := compileModelRules(component.Models)
+		if err != nil {
+			return nil, err
+		}
+
+		candidates, modelName := updateCandidates(device, component.Slug)
+
+		// the models matched and if the components of the model have the version installed
+		models := []string{}
+		installed := map[string]bool{}
+
+		for _, candidate := range candidates {
+			if !strings.EqualFold(common.FormatVendorName(candidate.Vendor), common.FormatVendorName(component.Vendor)) {
+				continue
+			}
+
+			name := modelName(candidate)
+			if !matchesModelRules(rules, name) {
+				continue
+			}
+
+			versionInstalled := candidate.Firmware != nil && firmwareVersionEqual(candidate.Firmware.Installed, component.Version)
+
+			if _, exists := installed[name]; !exists {
+				models = append(models, name)
+				installed[name] = versionInstalled
+
+				continue
+			}
+
+			installed[name] = installed[name] && versionInstalled
+		}
+
+		for _, name := range models {
+			if installed[name] && !option.ForceInstall {
+				continue
+			}
+
+			options = append(options, &model.UpdateOptions{
+				ForceInstall: option.ForceInstall,
+				Verify:       option.Verify,
+				DryRun:       option.DryRun,
+				Slug:         normalizeSlug(component.Slug),
+				Vendor:       common.FormatVendorName(component.Vendor),
+				Model:        name,
+				Version:      component.Version,
+				Checksum:     component.Checksum,
+				UpdateFile:   filepath.Join(b.Dir, filepath.FromSlash(component.File)),
+			})
+		}
+	}
+
+	return options, nil
+}
+
+// readBundleManifest reads and validates the manifest at the root of the bundle directory
+func readBundleManifest(dir string) (*model.FirmwareBundleManifest, error) {
+	b, err := os.ReadFile(filepath.Join(dir, model.FirmwareBundleManifestFile))
+	if err != nil {
+		return nil, errors.Wrap(ErrFirmwareBundleManifest, err.Error())
+	}
+
+	manifest := &model.FirmwareBundleManifest{}
+	if err := json.Unmarshal(b, manifest); err != nil {
+		return nil, errors.Wrap(ErrFirmwareBundleManifest, err.Error())
+	}
+
+	if len(manifest.Components) == 0 {
+		return nil, errors.Wrap(ErrFirmwareBundleManifest, "no components listed")
+	}
+
+	for idx, component := range manifest.Components {
+		prefix := "component " + strconv.Itoa(idx) + ": "
+
+		switch {
+		case component == nil:
+			return nil, errors.Wrap(ErrFirmwareBundleManifest, prefix+"undefined")
+		case component.Slug == "" || component.Vendor == "" || component.File == "":
+			return nil, errors.Wrap(ErrFirmwareBundleManifest, prefix+"slug, vendor and file are required")
+		case !filepath.IsLocal(filepath.FromSlash(component.File)):
+			return nil, errors.Wrap(ErrFirmwareBundleManifest, prefix+"file outside of the bundle: "+component.File)
+		}
+
+		if _, err := compileModelRules(component.Models); err != nil {
+			return nil, errors.Wrap(err, prefix)
+		}
+	}
+
+	return manifest, nil
+}
+
+// compileModelRules returns the model rules compiled as case insensitive regular expressions
+func compileModelRules(models []string) ([]*regexp.Regexp, error) {
+	rules := make([]*regexp.Regexp, 0, len(models))
+
+	for _, m := range models {
+		rule, err := regexp.Compile("(?i)" + m)
+		if err != nil {
+			return nil, errors.Wrap(ErrFirmwareBundleManifest, "model rule: "+err.Error())
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// matchesModelRules returns true when any of the rules match the model or no rules are defined
+func matchesModelRules(rules []*regexp.Regexp, modelName string) bool {
+	if len(rules) == 0 {
+		return true
+	}
+
+	for _, rule := range rules {
+		if rule.MatchString(modelName) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// extractTarball extracts the regular files and directories in the tarball into dir,
+// the tarball is identified as gzip compressed by the gzip header.
+func extractTarball(name, dir string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return errors.Wrap(ErrFirmwareBundle, err.Error())
+	}
+
+	defer f.Close()
+
+	br := bufio.NewReader(f)
+
+	var r io.Reader = br
+
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return errors.Wrap(ErrFirmwareBundle, err.Error())
+		}
+
+		defer gz.Close()
+
+		r = gz
+	}
+
+	tr := tar.NewReader(r)
+
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return errors.Wrap(ErrFirmwareBundle, err.Error())
+		}
+
+		entry := filepath.FromSlash(header.Name)
+		if !filepath.IsLocal(entry) {
+			return errors.Wrap(ErrFirmwareBundle, "tarball entry outside of the bundle: "+header.Name)
+		}
+
+		target := filepath.Join(dir, entry)
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o750); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := extractTarballFile(tr, target, header.FileInfo().Mode().Perm()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// extractTarballFile writes the tarball file contents to the target file
+func extractTarballFile(r io.Reader, target string, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode&0o750|0o600)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	_, err = io.Copy(f, r)
+
+	return err
+}
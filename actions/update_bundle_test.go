@@ -0,0 +1,270 @@
+package actions
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	common "github.com/metal-toolbox/bmc-common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metal-toolbox/ironlib/errs"
+	"github.com/metal-toolbox/ironlib/model"
+)
+
+// bundleFiles are the firmware files in the test bundle, indexed by path relative to the bundle root
+var bundleFiles = map[string]string{
+	"supermicro/BIOS_X11DPH-0981_20240906_3.5.bin":  "bios",
+	"supermicro/BMC_X11AST2500-4101MS_20240711.bin": "bmc",
+	"mellanox/fw-ConnectX4Lx-rel-14_32_1010.bin":    "nic",
+	"micron/1100_MU03.bin":                          "drive",
+}
+
+func newBundleManifest() *model.FirmwareBundleManifest {
+	sum := sha256.Sum256([]byte("bios"))
+
+	return &model.FirmwareBundleManifest{
+		Name: "x11dph-2024.10",
+		Components: []*model.FirmwareBundleComponent{
+			{
+				Slug:     "bios",
+				Vendor:   "Supermicro",
+				Models:   []string{"^x11dph-t$", "^x11dpt-b$"},
+				Version:  "3.5",
+				Checksum: hex.EncodeToString(sum[:]),
+				File:     "supermicro/BIOS_X11DPH-0981_20240906_3.5.bin",
+			},
+			{
+				// installed
+				Slug:    "bmc",
+				Vendor:  "supermicro",
+				Models:  []string{"^X11"},
+				Version: "1.73.14",
+				File:    "supermicro/BMC_X11AST2500-4101MS_20240711.bin",
+			},
+			{
+				// installed on one of the NICs
+				Slug:    "nic",
+				Vendor:  "Mellanox Technologies",
+				Models:  []string{"connectx-4"},
+				Version: "14.32.1010",
+				File:    "mellanox/fw-ConnectX4Lx-rel-14_32_1010.bin",
+			},
+			{
+				// no drive of the model in the inventory
+				Slug:    "drive",
+				Vendor:  "micron",
+				Models:  []string{"^MTFDDAK960"},
+				Version: "D3MU003",
+				File:    "micron/1100_MU03.bin",
+			},
+			{
+				// no cplds in the inventory
+				Slug:    "cpld",
+				Vendor:  "supermicro",
+				Version: "02.b6.04",
+				File:    "supermicro/BIOS_X11DPH-0981_20240906_3.5.bin",
+			},
+		},
+	}
+}
+
+// writeBundleDir writes the manifest and the bundle files to a temporary directory and returns its path
+func writeBundleDir(t *testing.T, manifest *model.FirmwareBundleManifest) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	for name, contents := range bundleFiles {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o750))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o600))
+	}
+
+	b, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, model.FirmwareBundleManifestFile), b, 0o600))
+
+	return dir
+}
+
+// writeBundleTarball writes the bundle directory files to a tarball, gzip compressed when compress is set
+func writeBundleTarball(t *testing.T, dir string, compress bool, extra ...string) string {
+	t.Helper()
+
+	name := filepath.Join(t.TempDir(), "bundle.tar")
+
+	f, err := os.Create(name)
+	require.NoError(t, err)
+
+	defer f.Close()
+
+	var w io.Writer = f
+
+	if compress {
+		gz := gzip.NewWriter(f)
+		defer gz.Close()
+
+		w = gz
+	}
+
+	tw := tar.NewWriter(w)
+	defer tw.Close()
+
+	names := []string{model.FirmwareBundleManifestFile}
+	for file := range bundleFiles {
+		names = append(names, file)
+	}
+
+	for _, file := range names {
+		b, err := os.ReadFile(filepath.Join(dir, file))
+		require.NoError(t, err)
+
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: file, Mode: 0o644, Size: int64(len(b)), Typeflag: tar.TypeReg}))
+		_, err = tw.Write(b)
+		require.NoError(t, err)
+	}
+
+	for _, file := range extra {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: file, Mode: 0o644, Size: 1, Typeflag: tar.TypeReg}))
+		_, err = tw.Write([]byte("x"))
+		require.NoError(t, err)
+	}
+
+	return name
+}
+
+func Test_FirmwareBundleUpdateOptions(t *testing.T) {
+	dir := writeBundleDir(t, newBundleManifest())
+
+	testcases := []struct {
+		name       string
+		bundlePath string
+		option     *model.UpdateOptions
+		wantSlugs  []string
+	}{
+		{
+			"directory",
+			dir,
+			nil,
+			[]string{common.SlugBIOS, common.SlugNIC},
+		},
+		{
+			"tarball",
+			writeBundleTarball(t, dir, false),
+			nil,
+			[]string{common.SlugBIOS, common.SlugNIC},
+		},
+		{
+			"gzip compressed tarball",
+			writeBundleTarball(t, dir, true),
+			nil,
+			[]string{common.SlugBIOS, common.SlugNIC},
+		},
+		{
+			"force install",
+			dir,
+			&model.UpdateOptions{ForceInstall: true, Verify: true, DryRun: true},
+			[]string{common.SlugBIOS, common.SlugBMC, common.SlugNIC},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			bundle, err := OpenFirmwareBundle(tc.bundlePath)
+			require.NoError(t, err)
+
+			defer bundle.Close()
+
+			assert.Equal(t, "x11dph-2024.10", bundle.Manifest.Name)
+
+			options, err := bundle.UpdateOptions(newPreflightDevice(), tc.option)
+			require.NoError(t, err)
+
+			slugs := []string{}
+			for _, option := range options {
+				slugs = append(slugs, option.Slug)
+
+				// the update files are in the bundle directory and pass the pre-flight checks
+				assert.FileExists(t, option.UpdateFile)
+				assert.NoError(t, verifyUpdateFile(option))
+				assert.Equal(t, tc.option != nil && tc.option.Verify, option.Verify)
+				assert.Equal(t, tc.option != nil && tc.option.DryRun, option.DryRun)
+			}
+
+			assert.Equal(t, tc.wantSlugs, slugs)
+
+			bios := options[0]
+			assert.Equal(t, "supermicro", bios.Vendor)
+			assert.Equal(t, "X11DPH-T", bios.Model)
+			assert.Equal(t, "3.5", bios.Version)
+			assert.Equal(t, filepath.Join(bundle.Dir, "supermicro", "BIOS_X11DPH-0981_20240906_3.5.bin"), bios.UpdateFile)
+
+			nic := options[len(options)-1]
+			assert.Equal(t, "mellanox", nic.Vendor)
+			assert.Equal(t, "ConnectX-4 Lx", nic.Model)
+		})
+	}
+}
+
+func Test_FirmwareBundleClose(t *testing.T) {
+	dir := writeBundleDir(t, newBundleManifest())
+
+	bundle, err := OpenFirmwareBundle(writeBundleTarball(t, dir, true))
+	require.NoError(t, err)
+	require.DirExists(t, bundle.Dir)
+
+	require.NoError(t, bundle.Close())
+	assert.NoDirExists(t, bundle.Dir)
+
+	// the bundle directory is not removed
+	bundle, err = OpenFirmwareBundle(dir)
+	require.NoError(t, err)
+	require.NoError(t, bundle.Close())
+	assert.DirExists(t, dir)
+}
+
+func Test_OpenFirmwareBundleInvalid(t *testing.T) {
+	dir := writeBundleDir(t, newBundleManifest())
+
+	_, err := OpenFirmwareBundle(writeBundleTarball(t, dir, true, "../escape.bin"))
+	assert.ErrorIs(t, err, ErrFirmwareBundle)
+
+	_, err = OpenFirmwareBundle(filepath.Join(dir, "does-not-exist.tar.gz"))
+	assert.ErrorIs(t, err, ErrFirmwareBundle)
+
+	testcases := []struct {
+		name   string
+		mutate func(*model.FirmwareBundleManifest)
+	}{
+		{"no components", func(m *model.FirmwareBundleManifest) { m.Components = nil }},
+		{"no file", func(m *model.FirmwareBundleManifest) { m.Components[0].File = "" }},
+		{"file outside of the bundle", func(m *model.FirmwareBundleManifest) { m.Components[0].File = "../../bios.bin" }},
+		{"invalid model rule", func(m *model.FirmwareBundleManifest) { m.Components[0].Models = []string{"x11dph-("} }},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			manifest := newBundleManifest()
+			tc.mutate(manifest)
+
+			_, err := OpenFirmwareBundle(writeBundleDir(t, manifest))
+			assert.ErrorIs(t, err, ErrFirmwareBundleManifest)
+		})
+	}
+}
+
+func Test_InstallBundleNoUpdatesApplicable(t *testing.T) {
+	manifest := newBundleManifest()
+	manifest.Components = manifest.Components[1:2]
+
+	_, err := InstallBundle(context.TODO(), newPreflightDevice(), writeBundleDir(t, manifest), nil)
+	assert.ErrorIs(t, err, errs.ErrNoUpdatesApplicable)
+}
@@ -0,0 +1,241 @@
+package actions
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+
+	common "github.com/metal-toolbox/bmc-common"
+	"github.com/pkg/errors"
+
+	"github.com/metal-toolbox/ironlib/errs"
+	"github.com/metal-toolbox/ironlib/model"
+	"github.com/metal-toolbox/ironlib/utils"
+)
+
+var (
+	ErrUpdateDependencyFailed = errors.New("update of a component dependency failed")
+	ErrUpdatePlanIncomplete   = errors.New("one or more firmware updates were not installed")
+)
+
+// stagedActivationComponents are the components running the firmware installed after a reboot
+var stagedActivationComponents = []string{common.SlugBIOS, common.SlugCPLD, common.SlugStorageController}
+
+// updateDependency declares the components a component firmware is installed after
+type updateDependency struct {
+	// vendor is the device vendor the dependency applies to, the dependency applies to all devices when empty.
+	vendor string
+
+	// slug is the component the dependency is declared for.
+	slug string
+
+	// after are the components the firmware is installed after.
+	after []string
+
+	// requires is set when the firmware is not to be installed if the update of a component it is installed after fails.
+	requires bool
+}
+
+// updateDependencies are the component firmware install dependencies
+var updateDependencies = []updateDependency{
+	// the BMC firmware is installed before the BIOS, the BIOS firmware is installed through the BMC
+	{slug: common.SlugBIOS, after: []string{common.SlugBMC}, requires: true},
+	// the CPLD firmware is installed last
+	{
+		slug:  common.SlugCPLD,
+		after: []string{common.SlugBMC, common.SlugBIOS, common.SlugNIC, common.SlugDrive, common.SlugStorageController},
+	},
+	// Dell requires the iDRAC firmware to be installed before the other component firmware
+	{vendor: common.VendorDell, slug: common.SlugNIC, after: []string{common.SlugBMC}},
+	{vendor: common.VendorDell, slug: common.SlugDrive, after: []string{common.SlugBMC}},
+	{vendor: common.VendorDell, slug: common.SlugStorageController, after: []string{common.SlugBMC}},
+}
+
+// UpdatePlanner orders the firmware updates by the component dependencies and installs them
+type UpdatePlanner struct {
+	// install installs the firmware update, returning the update result when verified.
+	install func(ctx context.Context, device *common.Device, option *model.UpdateOptions) (*model.UpdateResult, error)
+}
+
+// NewUpdatePlanner returns an UpdatePlanner that installs the firmware updates with UpdateComponent
+func NewUpdatePlanner() *UpdatePlanner {
+	return &UpdatePlanner{install: installComponent}
+}
+
+// Plan returns the update options ordered by the component dependencies for the device vendor,
+// the update options without dependencies between them are kept in the given order.
+func (p *UpdatePlanner) Plan(deviceVendor string, options []*model.UpdateOptions) []*model.UpdateOptions {
+	planned := make([]*model.UpdateOptions, 0, len(options))
+	pending := slices.Clone(options)
+
+	for len(pending) > 0 {
+		// the first update option with none of the components it is installed after pending
+		next := slices.IndexFunc(pending, func(option *model.UpdateOptions) bool {
+			after, _ := dependencies(deviceVendor, option.Slug)
+
+			return !slices.ContainsFunc(pending, func(o *model.UpdateOptions) bool {
+				return slices.Contains(after, normalizeSlug(o.Slug))
+			})
+		})
+
+		// the dependencies are not expected to be circular, the given order is kept if they are
+		if next == -1 {
+			next = 0
+		}
+
+		planned = append(planned, pending[next])
+		pending = slices.Delete(pending, next, next+1)
+	}
+
+	return planned
+}
+
+// Install installs the firmware updates in the order of the component dependencies for the device vendor.
+//
+// The updates continue past failures, the updates of the components requiring a component update that failed are skipped.
+// The update requirements of each of the updates installed are merged in the plan result,
+// ErrUpdatePlanIncomplete is returned along with the plan result when one or more updates failed or were skipped.
+func (p *UpdatePlanner) Install(ctx context.Context, device *common.Device, options []*model.UpdateOptions) (*model.UpdatePlanResult, error) {
+	plan := &model.UpdatePlanResult{
+		Requirements: &model.UpdateRequirements{},
+		Results:      []*model.UpdateResult{},
+	}
+
+	// the slugs of the components with an update failed or skipped
+	failed := []string{}
+
+	for _, option := range p.Plan(device.Vendor, options) {
+		slug := normalizeSlug(option.Slug)
+
+		if dependency := failedDependency(device.Vendor, slug, failed); dependency != "" {
+			failed = append(failed, slug)
+			plan.Results = append(plan.Results, &model.UpdateResult{
+				Slug:    option.Slug,
+				Vendor:  option.Vendor,
+				Model:   option.Model,
+				Serial:  option.Serial,
+				Version: option.Version,
+				Status:  model.UpdateStatusSkipped,
+				Err:     errors.Wrap(ErrUpdateDependencyFailed, "component: "+dependency),
+			})
+
+			continue
+		}
+
+		result, err := p.install(ctx, device, option)
+
+		result = planResult(option, result, err)
+		if result.Status == model.UpdateStatusFailed {
+			failed = append(failed, slug)
+		}
+
+		mergeUpdateRequirements(plan.Requirements, result.Requirements)
+		plan.Results = append(plan.Results, result)
+	}
+
+	if len(failed) > 0 {
+		return plan, errors.Wrap(ErrUpdatePlanIncomplete, strconv.Itoa(len(failed))+" of "+strconv.Itoa(len(options))+" updates")
+	}
+
+	return plan, nil
+}
+
+// dependencies returns the components the firmware of the component is installed after for the device vendor,
+// and the components it requires to be updated successfully.
+func dependencies(deviceVendor, componentSlug string) (after, requires []string) {
+	slug := normalizeSlug(componentSlug)
+
+	for _, dependency := range updateDependencies {
+		if dependency.slug != slug {
+			continue
+		}
+
+		if dependency.vendor != "" && !strings.EqualFold(dependency.vendor, common.FormatVendorName(deviceVendor)) {
+			continue
+		}
+
+		after = append(after, dependency.after...)
+
+		if dependency.requires {
+			requires = append(requires, dependency.after...)
+		}
+	}
+
+	return after, requires
+}
+
+// failedDependency returns the component required by the component with an update failed, if any
+func failedDependency(deviceVendor, componentSlug string, failed []string) string {
+	_, requires := dependencies(deviceVendor, componentSlug)
+
+	for _, slug := range requires {
+		if slices.Contains(failed, slug) {
+			return slug
+		}
+	}
+
+	return ""
+}
+
+// planResult returns the update result based on the result and error returned by the install.
+//
+// The firmware installed and not verified is considered staged when the component firmware is activated on a reboot,
+// no requirements are included for the firmware already installed. The update commands are included for dry run updates.
+func planResult(option *model.UpdateOptions, result *model.UpdateResult, err error) *model.UpdateResult {
+	if result == nil {
+		result = &model.UpdateResult{
+			Slug:         option.Slug,
+			Vendor:       option.Vendor,
+			Model:        option.Model,
+			Serial:       option.Serial,
+			Version:      option.Version,
+			Requirements: postInstallRequirements(option),
+		}
+	}
+
+	alreadyInstalled := &errs.FirmwareAlreadyInstalledError{}
+	dryRun := &errs.UpdateDryRunError{}
+
+	switch {
+	case errors.As(err, &dryRun):
+		result.Status = model.UpdateStatusDryRun
+		result.Commands = dryRun.Commands
+
+	case err == nil && result.Status == "":
+		result.Status = model.UpdateStatusInstalledActive
+		if slices.Contains(stagedActivationComponents, normalizeSlug(option.Slug)) {
+			result.Status = model.UpdateStatusStagedPending
+		}
+	case errors.As(err, &alreadyInstalled):
+		result.Status = model.UpdateStatusInstalledActive
+		result.InstalledVersion = alreadyInstalled.Version
+		result.Requirements = &model.UpdateRequirements{}
+	case errors.Is(err, utils.ErrRebootRequired):
+		result.Status = model.UpdateStatusStagedPending
+	case err != nil:
+		result.Status = model.UpdateStatusFailed
+		result.Err = err
+		result.Requirements = nil
+	}
+
+	staged := result.Status == model.UpdateStatusStagedPending ||
+		result.Status == model.UpdateStatusDryRun && slices.Contains(stagedActivationComponents, normalizeSlug(option.Slug))
+
+	if staged {
+		result.Requirements.PostInstallHostReboot = true
+	}
+
+	return result
+}
+
+// mergeUpdateRequirements merges the update requirements into the plan requirements
+func mergeUpdateRequirements(plan, requirements *model.UpdateRequirements) {
+	if requirements == nil {
+		return
+	}
+
+	plan.PostInstallReconfiguration = plan.PostInstallReconfiguration || requirements.PostInstallReconfiguration
+	plan.PostInstallHostPowercycle = plan.PostInstallHostPowercycle || requirements.PostInstallHostPowercycle
+	plan.PostInstallHostReboot = plan.PostInstallHostReboot || requirements.PostInstallHostReboot
+}
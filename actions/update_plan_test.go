@@ -0,0 +1,183 @@
+package actions
+
+import (
+	"context"
+	"testing"
+
+	common "github.com/metal-toolbox/bmc-common"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metal-toolbox/ironlib/errs"
+	"github.com/metal-toolbox/ironlib/model"
+	"github.com/metal-toolbox/ironlib/utils"
+)
+
+func planSlugs(options []*model.UpdateOptions) []string {
+	slugs := []string{}
+	for _, option := range options {
+		slugs = append(slugs, option.Slug)
+	}
+
+	return slugs
+}
+
+func newPlanOptions(slugs ...string) []*model.UpdateOptions {
+	options := []*model.UpdateOptions{}
+	for _, slug := range slugs {
+		options = append(options, &model.UpdateOptions{Slug: slug, Vendor: "supermicro", Version: "1.0"})
+	}
+
+	return options
+}
+
+func Test_UpdatePlannerPlan(t *testing.T) {
+	testcases := []struct {
+		name         string
+		deviceVendor string
+		slugs        []string
+		want         []string
+	}{
+		{
+			"bmc before bios, cpld last",
+			"Supermicro",
+			[]string{common.SlugCPLD, common.SlugNIC, common.SlugBIOS, common.SlugBMC, common.SlugDrive},
+			[]string{common.SlugNIC, common.SlugBMC, common.SlugBIOS, common.SlugDrive, common.SlugCPLD},
+		},
+		{
+			"slugs in any case",
+			"Supermicro",
+			[]string{"cpld", "bios", "bmc"},
+			[]string{"bmc", "bios", "cpld"},
+		},
+		{
+			"dell idrac first",
+			"Dell Inc.",
+			[]string{common.SlugNIC, common.SlugBIOS, common.SlugStorageController, common.SlugBMC, common.SlugDrive},
+			[]string{common.SlugBMC, common.SlugNIC, common.SlugBIOS, common.SlugStorageController, common.SlugDrive},
+		},
+		{
+			"no dependencies",
+			"Supermicro",
+			[]string{common.SlugDrive, common.SlugNIC, common.SlugDrive},
+			[]string{common.SlugDrive, common.SlugNIC, common.SlugDrive},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			options := newPlanOptions(tc.slugs...)
+
+			planned := NewUpdatePlanner().Plan(tc.deviceVendor, options)
+			assert.Equal(t, tc.want, planSlugs(planned))
+
+			// the given options are not reordered
+			assert.Equal(t, tc.slugs, planSlugs(options))
+		})
+	}
+}
+
+func Test_UpdatePlannerInstall(t *testing.T) {
+	installErrors := map[string]error{
+		common.SlugBMC:   errors.New("sum exited with error"),
+		common.SlugNIC:   errors.Wrap(utils.ErrRebootRequired, "pending reset"),
+		common.SlugDrive: errs.NewFirmwareAlreadyInstalledError(common.SlugDrive, "micron", "", "1.0"),
+	}
+
+	installed := []string{}
+
+	planner := NewUpdatePlanner()
+	planner.install = func(_ context.Context, _ *common.Device, option *model.UpdateOptions) (*model.UpdateResult, error) {
+		installed = append(installed, option.Slug)
+
+		return nil, installErrors[option.Slug]
+	}
+
+	device := &common.Device{Common: common.Common{Vendor: "Supermicro"}}
+	options := newPlanOptions(common.SlugCPLD, common.SlugBIOS, common.SlugBMC, common.SlugNIC, common.SlugDrive)
+	options[3].Vendor = "mellanox"
+
+	plan, err := planner.Install(context.TODO(), device, options)
+	require.ErrorIs(t, err, ErrUpdatePlanIncomplete)
+
+	// the bios update is skipped since the bmc update failed
+	assert.Equal(t, []string{common.SlugBMC, common.SlugNIC, common.SlugDrive, common.SlugCPLD}, installed)
+
+	statuses := map[string]model.UpdateStatus{}
+	for _, result := range plan.Results {
+		statuses[result.Slug] = result.Status
+	}
+
+	expected := map[string]model.UpdateStatus{
+		common.SlugBMC:   model.UpdateStatusFailed,
+		common.SlugBIOS:  model.UpdateStatusSkipped,
+		common.SlugNIC:   model.UpdateStatusStagedPending,
+		common.SlugDrive: model.UpdateStatusInstalledActive,
+		common.SlugCPLD:  model.UpdateStatusStagedPending,
+	}
+
+	assert.Equal(t, expected, statuses)
+
+	slugs := []string{}
+	for _, result := range plan.Results {
+		slugs = append(slugs, result.Slug)
+	}
+
+	assert.Equal(t, []string{common.SlugBMC, common.SlugBIOS, common.SlugNIC, common.SlugDrive, common.SlugCPLD}, slugs)
+
+	assert.ErrorIs(t, plan.Results[1].Err, ErrUpdateDependencyFailed)
+
+	// the mellanox NIC requires a host powercycle, the NIC and CPLD firmware is activated on a reboot
+	assert.Equal(t, &model.UpdateRequirements{PostInstallHostPowercycle: true, PostInstallHostReboot: true}, plan.Requirements)
+}
+
+func Test_UpdatePlannerInstallVerified(t *testing.T) {
+	planner := NewUpdatePlanner()
+	planner.install = func(_ context.Context, _ *common.Device, option *model.UpdateOptions) (*model.UpdateResult, error) {
+		return &model.UpdateResult{
+			Slug:             option.Slug,
+			Version:          option.Version,
+			InstalledVersion: option.Version,
+			Status:           model.UpdateStatusInstalledActive,
+			Requirements:     &model.UpdateRequirements{},
+		}, nil
+	}
+
+	device := &common.Device{Common: common.Common{Vendor: "Supermicro"}}
+
+	plan, err := planner.Install(context.TODO(), device, newPlanOptions(common.SlugBIOS, common.SlugBMC))
+	require.NoError(t, err)
+
+	assert.Len(t, plan.Results, 2)
+	assert.Equal(t, model.UpdateStatusInstalledActive, plan.Results[0].Status)
+	assert.Equal(t, model.UpdateStatusInstalledActive, plan.Results[1].Status)
+	assert.Equal(t, &model.UpdateRequirements{}, plan.Requirements)
+}
+
+func Test_UpdatePlannerInstallDryRun(t *testing.T) {
+	planner := NewUpdatePlanner()
+	planner.install = func(_ context.Context, _ *common.Device, option *model.UpdateOptions) (*model.UpdateResult, error) {
+		return nil, errs.NewUpdateDryRunError(&model.UpdateCommand{Cmd: "sum --file " + option.UpdateFile})
+	}
+
+	device := &common.Device{Common: common.Common{Vendor: "Supermicro"}}
+	options := newPlanOptions(common.SlugBIOS, common.SlugBMC)
+	options[0].UpdateFile = "/tmp/bios.bin"
+	options[1].UpdateFile = "/tmp/bmc.bin"
+
+	plan, err := planner.Install(context.TODO(), device, options)
+	require.NoError(t, err)
+	require.Len(t, plan.Results, 2)
+
+	for _, result := range plan.Results {
+		assert.Equal(t, model.UpdateStatusDryRun, result.Status)
+		assert.NoError(t, result.Err)
+	}
+
+	assert.Equal(t, "sum --file /tmp/bmc.bin", plan.Results[0].Commands[0].Cmd)
+	assert.Equal(t, "sum --file /tmp/bios.bin", plan.Results[1].Commands[0].Cmd)
+
+	// the bios firmware installed is activated on a reboot
+	assert.Equal(t, &model.UpdateRequirements{PostInstallHostReboot: true}, plan.Requirements)
+}
@@ -0,0 +1,267 @@
+package actions
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"io"
+	"os"
+	"strings"
+
+	common "github.com/metal-toolbox/bmc-common"
+	"github.com/pkg/errors"
+
+	"github.com/metal-toolbox/ironlib/errs"
+	"github.com/metal-toolbox/ironlib/model"
+)
+
+var (
+	ErrUpdateFileInvalid   = errors.New("update file invalid")
+	ErrUpdateFileChecksum  = errors.New("update file checksum mismatch")
+	ErrUpdateFileSignature = errors.New("update file signature verification failed")
+	ErrUpdateModelMismatch = errors.New("update model does not match the component model")
+)
+
+// PreflightUpdate validates the update options before the firmware is installed,
+// this is invoked by UpdateComponent before the update utility is executed.
+//
+// The update file is required to exist and to match the checksum and detached signature when these are included in the options,
+// the options model is required to match the component model in the inventory.
+// When the options version is already installed on the components a *errs.FirmwareAlreadyInstalledError is returned,
+// unless the options ForceInstall is set. The options version is required to verify the update.
+func PreflightUpdate(device *common.Device, option *model.UpdateOptions) error {
+	if option.Verify && option.Version == "" {
+		return ErrUpdateVerifyVersionUndefined
+	}
+
+	if err := verifyUpdateFile(option); err != nil {
+		return err
+	}
+
+	components, err := updateTargets(device, option)
+	if err != nil {
+		return err
+	}
+
+	if option.Version == "" || option.ForceInstall || len(components) == 0 {
+		return nil
+	}
+
+	// the update is skipped only when every component matched has the version installed
+	for _, component := range components {
+		if component.Firmware == nil || !firmwareVersionEqual(component.Firmware.Installed, option.Version) {
+			return nil
+		}
+	}
+
+	return errs.NewFirmwareAlreadyInstalledError(option.Slug, option.Vendor, option.Model, option.Version)
+}
+
+// verifyUpdateFile checks the update file exists and verifies its checksum and signature when set in the options
+func verifyUpdateFile(option *model.UpdateOptions) error {
+	if option.UpdateFile == "" {
+		return errors.Wrap(ErrUpdateFileInvalid, "no update file specified")
+	}
+
+	info, err := os.Stat(option.UpdateFile)
+	if err != nil {
+		return errors.Wrap(ErrUpdateFileInvalid, err.Error())
+	}
+
+	if !info.Mode().IsRegular() {
+		return errors.Wrap(ErrUpdateFileInvalid, "not a regular file: "+option.UpdateFile)
+	}
+
+	if option.Checksum != "" {
+		sum, err := sha256File(option.UpdateFile)
+		if err != nil {
+			return errors.Wrap(ErrUpdateFileInvalid, err.Error())
+		}
+
+		if !strings.EqualFold(sum, strings.TrimSpace(option.Checksum)) {
+			return errors.Wrap(ErrUpdateFileChecksum, "expected: "+option.Checksum+", got: "+sum)
+		}
+	}
+
+	if option.SignatureFile != "" {
+		if err := verifySignature(option.UpdateFile, option.SignatureFile, option.PublicKeyFile); err != nil {
+			return errors.Wrap(ErrUpdateFileSignature, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// sha256File returns the hex encoded SHA-256 checksum of the file
+func sha256File(name string) (string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// verifySignature verifies the detached signature of the file with the PEM encoded public key,
+//
+// RSA (PKCS #1 v1.5) and ECDSA signatures are of the SHA-256 digest of the file - openssl dgst -sha256 -sign,
+// Ed25519 signatures are of the file contents.
+func verifySignature(name, signatureFile, publicKeyFile string) error {
+	if publicKeyFile == "" {
+		return errors.New("no public key specified to verify the signature")
+	}
+
+	signature, err := os.ReadFile(signatureFile)
+	if err != nil {
+		return err
+	}
+
+	keyPEM, err := os.ReadFile(publicKeyFile)
+	if err != nil {
+		return err
+	}
+
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return errors.New("no PEM data found in public key: " + publicKeyFile)
+	}
+
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return err
+	}
+
+	contents, err := os.ReadFile(name)
+	if err != nil {
+		return err
+	}
+
+	digest := sha256.Sum256(contents)
+
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature)
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, digest[:], signature) {
+			return errors.New("ecdsa signature invalid")
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, contents, signature) {
+			return errors.New("ed25519 signature invalid")
+		}
+	default:
+		return errors.Errorf("unsupported public key type: %T", publicKey)
+	}
+
+	return nil
+}
+
+// updateTargets returns the components in the inventory the update options apply to,
+//
+// ErrUpdateModelMismatch is returned when the options model is set and none of the components of the options vendor match the model,
+// the BIOS, BMC and CPLD firmware applies to the device model.
+func updateTargets(device *common.Device, option *model.UpdateOptions) ([]*common.Common, error) {
+	candidates, modelName := updateCandidates(device, option.Slug)
+
+	var vendorMatched, targets []*common.Common
+
+	for _, candidate := range candidates {
+		if !strings.EqualFold(common.FormatVendorName(candidate.Vendor), common.FormatVendorName(option.Vendor)) {
+			continue
+		}
+
+		if option.Serial != "" && !strings.EqualFold(candidate.Serial, option.Serial) {
+			continue
+		}
+
+		vendorMatched = append(vendorMatched, candidate)
+
+		if modelMatches(modelName(candidate), option.Model) {
+			targets = append(targets, candidate)
+		}
+	}
+
+	if len(vendorMatched) > 0 && len(targets) == 0 {
+		return nil, errors.Wrap(ErrUpdateModelMismatch, "model: "+option.Model)
+	}
+
+	return targets, nil
+}
+
+// updateCandidates returns the components in the inventory identified by the slug
+// along with a func that returns the model the component firmware applies to.
+func updateCandidates(device *common.Device, componentSlug string) (candidates []*common.Common, modelName func(*common.Common) string) {
+	modelName = func(c *common.Common) string { return c.Model }
+
+	if device == nil {
+		return nil, modelName
+	}
+
+	deviceModel := func(*common.Common) string { return device.Model }
+
+	switch normalizeSlug(componentSlug) {
+	case common.SlugBIOS:
+		if device.BIOS != nil {
+			candidates = append(candidates, &device.BIOS.Common)
+		}
+
+		modelName = deviceModel
+	case common.SlugBMC:
+		if device.BMC != nil {
+			candidates = append(candidates, &device.BMC.Common)
+		}
+
+		modelName = deviceModel
+	case common.SlugCPLD:
+		for _, cpld := range device.CPLDs {
+			candidates = append(candidates, &cpld.Common)
+		}
+
+		modelName = deviceModel
+	case common.SlugNIC:
+		for _, nic := range device.NICs {
+			candidates = append(candidates, &nic.Common)
+		}
+	case common.SlugDrive:
+		for _, drive := range device.Drives {
+			candidates = append(candidates, &drive.Common)
+		}
+	case common.SlugStorageController:
+		for _, controller := range device.StorageControllers {
+			candidates = append(candidates, &controller.Common)
+		}
+	}
+
+	return candidates, modelName
+}
+
+// modelMatches returns true when the options model is not set, the component model is unknown
+// or the component model includes the options model.
+func modelMatches(componentModel, optionModel string) bool {
+	if optionModel == "" || componentModel == "" {
+		return true
+	}
+
+	return strings.Contains(strings.ToLower(componentModel), strings.ToLower(optionModel))
+}
+
+// firmwareVersionEqual returns true when the firmware versions are equal ignoring case, whitespace and a v prefix
+func firmwareVersionEqual(a, b string) bool {
+	normalize := func(s string) string {
+		return strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "v")
+	}
+
+	return a != "" && normalize(a) == normalize(b)
+}
@@ -0,0 +1,243 @@
+package actions
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	common "github.com/metal-toolbox/bmc-common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metal-toolbox/ironlib/errs"
+	"github.com/metal-toolbox/ironlib/model"
+)
+
+// newUpdateFile writes the contents to a firmware file in a temporary directory and returns its path
+func newUpdateFile(t *testing.T, contents []byte) string {
+	t.Helper()
+
+	name := filepath.Join(t.TempDir(), "firmware.bin")
+	require.NoError(t, os.WriteFile(name, contents, 0o600))
+
+	return name
+}
+
+// writePublicKey writes the PEM encoded public key next to the update file and returns its path
+func writePublicKey(t *testing.T, dir string, publicKey crypto.PublicKey) string {
+	t.Helper()
+
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	require.NoError(t, err)
+
+	name := filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(name, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+
+	return name
+}
+
+func newPreflightDevice() *common.Device {
+	return &common.Device{
+		Common: common.Common{Model: "X11DPH-T"},
+		BIOS:   &common.BIOS{Common: common.Common{Vendor: "Supermicro", Firmware: &common.Firmware{Installed: "3.4"}}},
+		BMC:    &common.BMC{Common: common.Common{Vendor: "Supermicro", Firmware: &common.Firmware{Installed: "1.73.14"}}},
+		NICs: []*common.NIC{
+			{Common: common.Common{Vendor: "Mellanox", Model: "ConnectX-4 Lx", Firmware: &common.Firmware{Installed: "14.32.1010"}}},
+			{Common: common.Common{Vendor: "Mellanox", Model: "ConnectX-4 Lx", Firmware: &common.Firmware{Installed: "14.31.1014"}}},
+		},
+		Drives: []*common.Drive{
+			{Common: common.Common{Vendor: "Micron", Model: "MTFDDAK480TDN", Firmware: &common.Firmware{Installed: "D3MU001"}}},
+		},
+	}
+}
+
+func Test_PreflightUpdateFile(t *testing.T) {
+	contents := []byte("firmware")
+	updateFile := newUpdateFile(t, contents)
+	sum := sha256.Sum256(contents)
+
+	testcases := []struct {
+		name    string
+		option  *model.UpdateOptions
+		wantErr error
+	}{
+		{
+			"no update file",
+			&model.UpdateOptions{Slug: common.SlugBIOS, Vendor: "supermicro"},
+			ErrUpdateFileInvalid,
+		},
+		{
+			"update file does not exist",
+			&model.UpdateOptions{Slug: common.SlugBIOS, Vendor: "supermicro", UpdateFile: updateFile + ".missing"},
+			ErrUpdateFileInvalid,
+		},
+		{
+			"update file is a directory",
+			&model.UpdateOptions{Slug: common.SlugBIOS, Vendor: "supermicro", UpdateFile: filepath.Dir(updateFile)},
+			ErrUpdateFileInvalid,
+		},
+		{
+			"checksum matches",
+			&model.UpdateOptions{Slug: common.SlugBIOS, Vendor: "supermicro", UpdateFile: updateFile, Checksum: hex.EncodeToString(sum[:])},
+			nil,
+		},
+		{
+			"checksum mismatch",
+			&model.UpdateOptions{Slug: common.SlugBIOS, Vendor: "supermicro", UpdateFile: updateFile, Checksum: hex.EncodeToString(sum[1:])},
+			ErrUpdateFileChecksum,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := PreflightUpdate(newPreflightDevice(), tc.option)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func Test_PreflightUpdateSignature(t *testing.T) {
+	contents := []byte("firmware")
+	updateFile := newUpdateFile(t, contents)
+	dir := filepath.Dir(updateFile)
+	digest := sha256.Sum256(contents)
+
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	ecdsaSignature, err := ecdsa.SignASN1(rand.Reader, ecdsaKey, digest[:])
+	require.NoError(t, err)
+
+	ed25519Public, ed25519Private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	otherPublic, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	testcases := []struct {
+		name      string
+		publicKey crypto.PublicKey
+		signature []byte
+		wantErr   error
+	}{
+		{"ecdsa signature valid", &ecdsaKey.PublicKey, ecdsaSignature, nil},
+		{"ed25519 signature valid", ed25519Public, ed25519.Sign(ed25519Private, contents), nil},
+		{"signed with another key", otherPublic, ed25519.Sign(ed25519Private, contents), ErrUpdateFileSignature},
+		{"signature of other contents", ed25519Public, ed25519.Sign(ed25519Private, []byte("other")), ErrUpdateFileSignature},
+		{"no public key", nil, ecdsaSignature, ErrUpdateFileSignature},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			signatureFile := filepath.Join(dir, "firmware.bin.sig")
+			require.NoError(t, os.WriteFile(signatureFile, tc.signature, 0o600))
+
+			option := &model.UpdateOptions{Slug: common.SlugBIOS, Vendor: "supermicro", UpdateFile: updateFile, SignatureFile: signatureFile}
+			if tc.publicKey != nil {
+				option.PublicKeyFile = writePublicKey(t, dir, tc.publicKey)
+			}
+
+			err := PreflightUpdate(newPreflightDevice(), option)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func Test_PreflightUpdateInventory(t *testing.T) {
+	updateFile := newUpdateFile(t, []byte("firmware"))
+
+	testcases := []struct {
+		name             string
+		option           *model.UpdateOptions
+		wantErr          error
+		alreadyInstalled bool
+	}{
+		{
+			"bios version installed",
+			&model.UpdateOptions{Slug: common.SlugBIOS, Vendor: "supermicro", Model: "X11DPH-T", Version: "3.4"},
+			nil,
+			true,
+		},
+		{
+			"bios version installed forced",
+			&model.UpdateOptions{Slug: common.SlugBIOS, Vendor: "supermicro", Version: "3.4", ForceInstall: true},
+			nil,
+			false,
+		},
+		{
+			"bios version differs",
+			&model.UpdateOptions{Slug: common.SlugBIOS, Vendor: "supermicro", Version: "3.5"},
+			nil,
+			false,
+		},
+		{
+			"bios model mismatch",
+			&model.UpdateOptions{Slug: common.SlugBIOS, Vendor: "supermicro", Model: "X12STH-SYS", Version: "3.5"},
+			ErrUpdateModelMismatch,
+			false,
+		},
+		{
+			"bmc version installed with v prefix",
+			&model.UpdateOptions{Slug: common.SlugBMC, Vendor: "supermicro", Version: "v1.73.14"},
+			nil,
+			true,
+		},
+		{
+			"nic version installed on one of the nics",
+			&model.UpdateOptions{Slug: common.SlugNIC, Vendor: "mellanox", Model: "ConnectX-4", Version: "14.32.1010"},
+			nil,
+			false,
+		},
+		{
+			"nic model mismatch",
+			&model.UpdateOptions{Slug: common.SlugNIC, Vendor: "mellanox", Model: "ConnectX-6", Version: "22.32.1010"},
+			ErrUpdateModelMismatch,
+			false,
+		},
+		{
+			"drive version installed",
+			&model.UpdateOptions{Slug: "drive", Vendor: "micron", Model: "MTFDDAK480TDN", Version: "D3MU001"},
+			nil,
+			true,
+		},
+		{
+			"drive model mismatch",
+			&model.UpdateOptions{Slug: "drive", Vendor: "micron", Model: "MTFDDAK960TDN", Version: "D3MU001"},
+			ErrUpdateModelMismatch,
+			false,
+		},
+		{
+			"component vendor not in inventory",
+			&model.UpdateOptions{Slug: common.SlugNIC, Vendor: "intel", Model: "X710", Version: "9.20"},
+			nil,
+			false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.option.UpdateFile = updateFile
+
+			err := PreflightUpdate(newPreflightDevice(), tc.option)
+
+			if tc.alreadyInstalled {
+				alreadyInstalled := &errs.FirmwareAlreadyInstalledError{}
+				require.ErrorAs(t, err, &alreadyInstalled)
+				assert.Equal(t, tc.option.Version, alreadyInstalled.Version)
+
+				return
+			}
+
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package actions
+
+import (
+	"context"
+	"testing"
+
+	common "github.com/metal-toolbox/bmc-common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metal-toolbox/ironlib/errs"
+	"github.com/metal-toolbox/ironlib/model"
+	"github.com/metal-toolbox/ironlib/utils"
+)
+
+func Test_GetStorageControllerUpdater(t *testing.T) {
+	testcases := []struct {
+		vendor   string
+		model    string
+		expected StorageControllerUpdater
+		wantErr  error
+	}{
+		{"marvell", "1b4b-9230", utils.NewMvcliCmd(true), nil},
+		{"Broadcom / LSI", "MegaRAID 9560-8i 4GB", utils.NewStoreCLICmd(true), nil},
+		{"lsi", "LSI3008-IT", utils.NewSas3flashCmd(true), nil},
+		{"LSI Logic / Symbios Logic", "SAS3008 PCI-Express Fusion-MPT SAS-3", utils.NewSas3flashCmd(true), nil},
+		{"intel", "C620 Series Chipset Family SATA Controller", nil, ErrUpdaterUtilNotIdentified},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.vendor+" "+tc.model, func(t *testing.T) {
+			got, err := GetStorageControllerUpdater(tc.vendor, tc.model)
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.EqualValues(t, tc.expected, got)
+		})
+	}
+}
+
+func Test_UpdateComponentRouting(t *testing.T) {
+	updateFile := newUpdateFile(t, []byte("firmware"))
+
+	device := &common.Device{
+		StorageControllers: []*common.StorageController{
+			{Common: common.Common{Vendor: "intel", Model: "C620 Series Chipset Family SATA Controller"}},
+		},
+		CPLDs: []*common.CPLD{
+			{Common: common.Common{Vendor: "Supermicro", Model: "x11dph-t"}},
+		},
+	}
+
+	testcases := []struct {
+		name    string
+		option  *model.UpdateOptions
+		wantErr error
+	}{
+		{
+			"storage controller without an updater",
+			&model.UpdateOptions{Slug: common.SlugStorageController, Vendor: "intel"},
+			ErrUpdaterUtilNotIdentified,
+		},
+		{
+			"storage controller vendor not in inventory",
+			&model.UpdateOptions{Slug: common.SlugStorageController, Vendor: "marvell"},
+			ErrUpdaterUtilNotIdentified,
+		},
+		{
+			"cpld vendor not in inventory",
+			&model.UpdateOptions{Slug: common.SlugCPLD, Vendor: "dell"},
+			ErrUpdaterUtilNotIdentified,
+		},
+		{
+			"component without an updater",
+			&model.UpdateOptions{Slug: common.SlugPSU, Vendor: "supermicro"},
+			errs.ErrNoUpdateHandlerForComponent,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.option.UpdateFile = updateFile
+
+			err := UpdateComponent(context.TODO(), device, tc.option)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func Test_UpdateComponentDryRun(t *testing.T) {
+	device := newPreflightDevice()
+	updateFile := newUpdateFile(t, []byte("firmware"))
+
+	option := &model.UpdateOptions{Slug: common.SlugBIOS, Vendor: "supermicro", Version: "3.5", UpdateFile: updateFile, DryRun: true}
+
+	err := UpdateComponent(context.TODO(), device, option)
+
+	dryRun := &errs.UpdateDryRunError{}
+	require.ErrorAs(t, err, &dryRun)
+	require.Len(t, dryRun.Commands, 1)
+
+	assert.Equal(t, "sum -c UpdateBios --preserve_setting --file "+updateFile, dryRun.Commands[0].Cmd)
+	assert.Equal(t, []*common.Common{&device.BIOS.Common}, dryRun.Commands[0].Components)
+
+	// the firmware installed is reported by the pre-flight checks
+	option.Version = "3.4"
+
+	err = UpdateComponent(context.TODO(), device, option)
+	assert.ErrorAs(t, err, new(*errs.FirmwareAlreadyInstalledError))
+}
+
+func Test_SetDryRun(t *testing.T) {
+	sum := utils.NewSupermicroSUM(false)
+
+	require.NoError(t, setDryRun(sum, &model.UpdateOptions{}))
+	assert.False(t, sum.DryRun)
+
+	require.NoError(t, setDryRun(sum, &model.UpdateOptions{DryRun: true}))
+	assert.True(t, sum.DryRun)
+
+	err := setDryRun(struct{}{}, &model.UpdateOptions{DryRun: true})
+	assert.ErrorIs(t, err, ErrDryRunUnsupported)
+}
+
+func Test_UpdateAll(t *testing.T) {
+	device := newPreflightDevice()
+	device.Vendor = "Supermicro"
+
+	updateFile := newUpdateFile(t, []byte("firmware"))
+
+	options := []*model.UpdateOptions{
+		{Slug: common.SlugBIOS, Vendor: "supermicro", Version: "3.5", UpdateFile: updateFile, DryRun: true},
+		{Slug: common.SlugPSU, Vendor: "supermicro", Version: "1.2", UpdateFile: updateFile},
+		{Slug: common.SlugBMC, Vendor: "supermicro", Version: "1.74.5", UpdateFile: updateFile, DryRun: true},
+	}
+
+	plan, err := UpdateAll(context.TODO(), device, options)
+	assert.ErrorIs(t, err, ErrUpdatePlanIncomplete)
+	require.Len(t, plan.Results, 3)
+
+	// the updates continue past the failed update, the BMC firmware is installed before the BIOS
+	assert.Equal(t, common.SlugPSU, plan.Results[0].Slug)
+	assert.Equal(t, model.UpdateStatusFailed, plan.Results[0].Status)
+	assert.ErrorIs(t, plan.Results[0].Err, errs.ErrNoUpdateHandlerForComponent)
+
+	assert.Equal(t, common.SlugBMC, plan.Results[1].Slug)
+	assert.Equal(t, model.UpdateStatusDryRun, plan.Results[1].Status)
+
+	assert.Equal(t, common.SlugBIOS, plan.Results[2].Slug)
+	assert.Equal(t, model.UpdateStatusDryRun, plan.Results[2].Status)
+
+	// the bios firmware installed is activated on a reboot
+	assert.True(t, plan.Requirements.PostInstallHostReboot)
+}
@@ -0,0 +1,258 @@
+package actions
+
+import (
+	"context"
+	"strings"
+
+	common "github.com/metal-toolbox/bmc-common"
+	"github.com/pkg/errors"
+
+	"github.com/metal-toolbox/ironlib/model"
+	"github.com/metal-toolbox/ironlib/utils"
+)
+
+var (
+	ErrUpdateVerify                 = errors.New("firmware update verification failed")
+	ErrUpdateVerifyVersionUndefined = errors.New("firmware update verification requires the update options version")
+	ErrVerifyCollectorNotIdentified = errors.New("firmware update verification collector not identified")
+	ErrVerifyNoComponentsCollected  = errors.New("firmware update verification collected no components")
+)
+
+// GetVerifyCollectors returns the collectors to verify the firmware installed on the component identified by the slug and vendor,
+// these are the collectors that report the firmware version installed by the component updater.
+func GetVerifyCollectors(componentSlug, componentVendor string) (*Collectors, error) {
+	vendor := common.FormatVendorName(componentVendor)
+
+	switch normalizeSlug(componentSlug) {
+	case common.SlugNIC:
+		if strings.EqualFold(vendor, common.VendorMellanox) {
+			return &Collectors{NICCollector: utils.NewMlxupCmd(false)}, nil
+		}
+	case common.SlugDrive:
+		if strings.EqualFold(vendor, common.VendorMicron) {
+			return &Collectors{DriveCollectors: []DriveCollector{utils.NewMsecli(false)}}, nil
+		}
+	case common.SlugBMC, common.SlugBIOS, common.SlugCPLD:
+		if strings.EqualFold(vendor, common.VendorSupermicro) {
+			ipmicfg := utils.NewIpmicfgCmd(false)
+
+			return &Collectors{BMCCollector: ipmicfg, BIOSCollector: ipmicfg, CPLDCollector: ipmicfg}, nil
+		}
+	case common.SlugStorageController:
+		if collector := StorageControllerCollectorByVendor(vendor, false); collector != nil {
+			return &Collectors{StorageControllerCollectors: []StorageControllerCollector{collector}}, nil
+		}
+	}
+
+	return nil, errors.Wrap(ErrVerifyCollectorNotIdentified, "slug: "+componentSlug+" vendor: "+componentVendor)
+}
+
+// VerifyUpdate collects the inventory of the component the update options apply to
+// and returns the status of the firmware install based on the firmware version reported.
+//
+// pendingActivation indicates the updater reported the firmware is installed and pending a reboot or powercycle.
+func VerifyUpdate(ctx context.Context, collectors *Collectors, option *model.UpdateOptions, pendingActivation bool) (*model.UpdateResult, error) {
+	if option.Version == "" {
+		return nil, ErrUpdateVerifyVersionUndefined
+	}
+
+	components, err := collectFirmware(ctx, collectors, option.Slug)
+	if err != nil {
+		return nil, errors.Wrap(err, "error collecting inventory to verify firmware update")
+	}
+
+	if len(components) == 0 {
+		return nil, errors.Wrap(ErrVerifyNoComponentsCollected, "slug: "+option.Slug)
+	}
+
+	return VerifyFirmware(option, verifyTargets(components, option), pendingActivation), nil
+}
+
+// collectFirmware runs the collector for the component slug and returns the components collected
+func collectFirmware(ctx context.Context, collectors *Collectors, componentSlug string) ([]*common.Common, error) {
+	var components []*common.Common
+
+	switch normalizeSlug(componentSlug) {
+	case common.SlugNIC:
+		if collectors.NICCollector == nil {
+			break
+		}
+
+		nics, err := collectors.NICs(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, nic := range nics {
+			components = append(components, &nic.Common)
+		}
+	case common.SlugDrive:
+		for _, collector := range collectors.DriveCollectors {
+			drives, err := collector.Drives(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, drive := range drives {
+				components = append(components, &drive.Common)
+			}
+		}
+	case common.SlugBMC:
+		if collectors.BMCCollector == nil {
+			break
+		}
+
+		bmc, err := collectors.BMC(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if bmc != nil {
+			components = append(components, &bmc.Common)
+		}
+	case common.SlugBIOS:
+		if collectors.BIOSCollector == nil {
+			break
+		}
+
+		bios, err := collectors.BIOS(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if bios != nil {
+			components = append(components, &bios.Common)
+		}
+	case common.SlugCPLD:
+		if collectors.CPLDCollector == nil {
+			break
+		}
+
+		cplds, err := collectors.CPLDs(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, cpld := range cplds {
+			components = append(components, &cpld.Common)
+		}
+	case common.SlugStorageController:
+		for _, collector := range collectors.StorageControllerCollectors {
+			controllers, err := collector.StorageControllers(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, controller := range controllers {
+				components = append(components, &controller.Common)
+			}
+		}
+	}
+
+	return components, nil
+}
+
+// verifyTargets returns the collected components the update options apply to,
+// the vendor and model are matched when reported by the collector - ipmicfg does not report the BIOS, BMC model.
+func verifyTargets(components []*common.Common, option *model.UpdateOptions) []*common.Common {
+	var targets []*common.Common
+
+	for _, component := range components {
+		if component.Vendor != "" &&
+			!strings.EqualFold(common.FormatVendorName(component.Vendor), common.FormatVendorName(option.Vendor)) {
+			continue
+		}
+
+		if option.Serial != "" && !strings.EqualFold(component.Serial, option.Serial) {
+			continue
+		}
+
+		if !modelMatches(component.Model, option.Model) {
+			continue
+		}
+
+		targets = append(targets, component)
+	}
+
+	return targets
+}
+
+// VerifyFirmware returns the status of the firmware install on the components the update options apply to.
+//
+// The firmware is installed and active when the components report the options version installed and running,
+// it is staged when the components report the options version installed with a different version running - mlxup,
+// or when the updater reported the firmware pending activation. The install failed in any other case.
+func VerifyFirmware(option *model.UpdateOptions, components []*common.Common, pendingActivation bool) *model.UpdateResult {
+	result := &model.UpdateResult{
+		Slug:         option.Slug,
+		Vendor:       option.Vendor,
+		Model:        option.Model,
+		Serial:       option.Serial,
+		Version:      option.Version,
+		Requirements: postInstallRequirements(option),
+		Status:       model.UpdateStatusFailed,
+	}
+
+	if len(components) == 0 {
+		return result
+	}
+
+	result.Status = model.UpdateStatusInstalledActive
+
+	for _, component := range components {
+		var installed, running string
+
+		if component.Firmware != nil {
+			installed = component.Firmware.Installed
+			running = component.Firmware.Metadata["firmware_running"]
+		}
+
+		result.InstalledVersion = installed
+
+		switch {
+		case firmwareVersionEqual(installed, option.Version) && (running == "" || firmwareVersionEqual(running, option.Version)):
+			continue
+		case firmwareVersionEqual(installed, option.Version) || pendingActivation:
+			result.Status = model.UpdateStatusStagedPending
+		default:
+			result.Status = model.UpdateStatusFailed
+
+			return result
+		}
+	}
+
+	if result.Status == model.UpdateStatusStagedPending {
+		result.Requirements.PostInstallHostReboot = true
+	}
+
+	return result
+}
+
+// postInstallRequirements returns the post install requirements for the component the update options apply to
+func postInstallRequirements(option *model.UpdateOptions) *model.UpdateRequirements {
+	requirements, err := UpdateRequirements(option.Slug, option.Vendor, option.Model)
+	if err != nil || requirements == nil {
+		return &model.UpdateRequirements{}
+	}
+
+	return requirements
+}
+
+// UpdateResultError returns the error for the update result status,
+// nil is returned when the firmware is installed and active.
+//
+// utils.ErrRebootRequired is returned when the firmware is staged pending a reboot or powercycle,
+// ErrUpdateVerify is returned when the firmware version is not installed.
+func UpdateResultError(result *model.UpdateResult) error {
+	switch result.Status {
+	case model.UpdateStatusInstalledActive:
+		return nil
+	case model.UpdateStatusStagedPending:
+		return errors.Wrap(utils.ErrRebootRequired, "firmware version "+result.Version+" staged, component: "+result.Slug)
+	default:
+		return errors.Wrap(
+			ErrUpdateVerify,
+			"component: "+result.Slug+", expected version: "+result.Version+", installed version: "+result.InstalledVersion,
+		)
+	}
+}
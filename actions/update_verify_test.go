@@ -0,0 +1,215 @@
+package actions
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	common "github.com/metal-toolbox/bmc-common"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metal-toolbox/ironlib/model"
+	"github.com/metal-toolbox/ironlib/utils"
+)
+
+func newVerifyCollectors(t *testing.T) *Collectors {
+	t.Helper()
+
+	summary, err := os.ReadFile("../fixtures/utils/ipmicfg/summary")
+	require.NoError(t, err)
+
+	query, err := os.ReadFile("../fixtures/utils/mlxup/query-pending")
+	require.NoError(t, err)
+
+	mlxup, err := utils.NewFakeMlxup(bytes.NewReader(query))
+	require.NoError(t, err)
+
+	ipmicfg := utils.NewFakeIpmicfg(bytes.NewReader(summary))
+
+	return &Collectors{NICCollector: mlxup, BMCCollector: ipmicfg, BIOSCollector: ipmicfg, CPLDCollector: ipmicfg}
+}
+
+func Test_VerifyUpdate(t *testing.T) {
+	testcases := []struct {
+		name              string
+		option            *model.UpdateOptions
+		pendingActivation bool
+		wantStatus        model.UpdateStatus
+		wantInstalled     string
+		wantErr           error
+	}{
+		{
+			"bmc installed and active",
+			&model.UpdateOptions{Slug: common.SlugBMC, Vendor: "supermicro", Model: "X11DPH-T", Version: "1.71.11"},
+			false,
+			model.UpdateStatusInstalledActive,
+			"1.71.11",
+			nil,
+		},
+		{
+			"bmc version not installed",
+			&model.UpdateOptions{Slug: common.SlugBMC, Vendor: "supermicro", Version: "1.73.14"},
+			false,
+			model.UpdateStatusFailed,
+			"1.71.11",
+			nil,
+		},
+		{
+			"bmc version installed pending activation",
+			&model.UpdateOptions{Slug: common.SlugBMC, Vendor: "supermicro", Version: "1.73.14"},
+			true,
+			model.UpdateStatusStagedPending,
+			"1.71.11",
+			nil,
+		},
+		{
+			"bios staged pending reboot",
+			&model.UpdateOptions{Slug: common.SlugBIOS, Vendor: "supermicro", Version: "3.4"},
+			true,
+			model.UpdateStatusStagedPending,
+			"3.3",
+			nil,
+		},
+		{
+			"bios version not installed",
+			&model.UpdateOptions{Slug: common.SlugBIOS, Vendor: "supermicro", Version: "3.4"},
+			false,
+			model.UpdateStatusFailed,
+			"3.3",
+			nil,
+		},
+		{
+			"nic installed pending reset",
+			&model.UpdateOptions{Slug: common.SlugNIC, Vendor: "mellanox", Model: "MCX4121A", Version: "14.28.2006"},
+			false,
+			model.UpdateStatusStagedPending,
+			"14.28.2006",
+			nil,
+		},
+		{
+			"nic installed and active",
+			&model.UpdateOptions{Slug: common.SlugNIC, Vendor: "mellanox", Serial: "b8:59:9f:de:86:f8", Version: "14.28.2006"},
+			false,
+			model.UpdateStatusInstalledActive,
+			"14.28.2006",
+			nil,
+		},
+		{
+			"nic version not installed",
+			&model.UpdateOptions{Slug: common.SlugNIC, Vendor: "mellanox", Version: "14.32.1010"},
+			false,
+			model.UpdateStatusFailed,
+			"14.28.2006",
+			nil,
+		},
+		{
+			"nic model not collected",
+			&model.UpdateOptions{Slug: common.SlugNIC, Vendor: "mellanox", Model: "MCX623106AN", Version: "22.32.1010"},
+			false,
+			model.UpdateStatusFailed,
+			"",
+			nil,
+		},
+		{
+			"no version",
+			&model.UpdateOptions{Slug: common.SlugNIC, Vendor: "mellanox"},
+			false,
+			"",
+			"",
+			ErrUpdateVerifyVersionUndefined,
+		},
+		{
+			"no components collected",
+			&model.UpdateOptions{Slug: common.SlugDrive, Vendor: "micron", Version: "D3MU001"},
+			false,
+			"",
+			"",
+			ErrVerifyNoComponentsCollected,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := VerifyUpdate(context.TODO(), newVerifyCollectors(t), tc.option, tc.pendingActivation)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantStatus, result.Status)
+			assert.Equal(t, tc.wantInstalled, result.InstalledVersion)
+			assert.Equal(t, tc.option.Version, result.Version)
+		})
+	}
+}
+
+func Test_VerifyUpdateRequirements(t *testing.T) {
+	option := &model.UpdateOptions{Slug: common.SlugNIC, Vendor: "mellanox", Version: "14.28.2006"}
+
+	result, err := VerifyUpdate(context.TODO(), newVerifyCollectors(t), option, false)
+	require.NoError(t, err)
+
+	// the firmware is staged on the first NIC pending a reset
+	assert.Equal(t, model.UpdateStatusStagedPending, result.Status)
+	assert.Equal(t, &model.UpdateRequirements{PostInstallHostPowercycle: true, PostInstallHostReboot: true}, result.Requirements)
+}
+
+func Test_GetVerifyCollectors(t *testing.T) {
+	collectors, err := GetVerifyCollectors("nic", "Mellanox Technologies")
+	require.NoError(t, err)
+	assert.IsType(t, &utils.Mlxup{}, collectors.NICCollector)
+
+	collectors, err = GetVerifyCollectors(common.SlugBIOS, "Supermicro")
+	require.NoError(t, err)
+	assert.IsType(t, &utils.Ipmicfg{}, collectors.BIOSCollector)
+
+	collectors, err = GetVerifyCollectors(common.SlugStorageController, "Broadcom")
+	require.NoError(t, err)
+	assert.IsType(t, &utils.StoreCLI{}, collectors.StorageControllerCollectors[0])
+
+	_, err = GetVerifyCollectors(common.SlugNIC, "Intel")
+	assert.ErrorIs(t, err, ErrVerifyCollectorNotIdentified)
+}
+
+func Test_UpdateResultError(t *testing.T) {
+	assert.NoError(t, UpdateResultError(&model.UpdateResult{Status: model.UpdateStatusInstalledActive}))
+	assert.ErrorIs(t, UpdateResultError(&model.UpdateResult{Status: model.UpdateStatusStagedPending}), utils.ErrRebootRequired)
+	assert.ErrorIs(t, UpdateResultError(&model.UpdateResult{Status: model.UpdateStatusFailed}), ErrUpdateVerify)
+}
+
+func Test_UpdateComponentVerifyVersionUndefined(t *testing.T) {
+	device := newPreflightDevice()
+	option := &model.UpdateOptions{
+		Slug:       common.SlugBIOS,
+		Vendor:     "supermicro",
+		UpdateFile: newUpdateFile(t, []byte("firmware")),
+		Verify:     true,
+	}
+
+	err := UpdateComponent(context.TODO(), device, option)
+	assert.ErrorIs(t, err, ErrUpdateVerifyVersionUndefined)
+}
+
+func Test_VerifyInstallSUMBIOS(t *testing.T) {
+	option := &model.UpdateOptions{Slug: common.SlugBIOS, Vendor: "supermicro", Model: "X11DPH-T", Version: "3.4", Verify: true}
+
+	// the BIOS firmware installed by sum is activated on the next host reboot
+	sum := utils.NewFakeSMCSum(nil)
+	installErr := errors.Wrap(sum.UpdateBIOS(context.TODO(), "/tmp/bios.bin", option.Model), "error updating bios")
+	require.ErrorIs(t, installErr, utils.ErrRebootRequired)
+
+	result, err := verifyInstall(context.TODO(), newVerifyCollectors(t), option, installErr)
+	assert.ErrorIs(t, err, utils.ErrRebootRequired)
+	assert.Equal(t, model.UpdateStatusStagedPending, result.Status)
+	assert.Equal(t, "3.3", result.InstalledVersion)
+	assert.True(t, result.Requirements.PostInstallHostReboot)
+
+	// the install did not report the firmware pending activation
+	result, err = verifyInstall(context.TODO(), newVerifyCollectors(t), option, nil)
+	assert.ErrorIs(t, err, ErrUpdateVerify)
+	assert.Equal(t, model.UpdateStatusFailed, result.Status)
+}
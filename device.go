@@ -60,11 +60,13 @@ func CheckDependencies() {
 		utils.NewHdparmCmd(false),
 		utils.NewLshwCmd(false),
 		utils.NewLsblkCmd(false),
+		utils.NewMdadmCmd(false),
 		utils.NewMlxupCmd(false),
 		utils.NewMsecli(false),
 		utils.NewMvcliCmd(false),
 		utils.NewNvmeCmd(false),
 		utils.NewSmartctlCmd(false),
+		utils.NewSas3flashCmd(false),
 		utils.NewIpmicfgCmd(false),
 		utils.NewSupermicroSUM(false),
 		utils.NewStoreCLICmd(false),
@@ -3,6 +3,8 @@ package errs
 import (
 	"errors"
 	"fmt"
+
+	"github.com/metal-toolbox/ironlib/model"
 )
 
 var (
@@ -18,6 +20,7 @@ var (
 	ErrBinLstat                       = errors.New("failed to run lstat on bin")
 	ErrBinLookupPath                  = errors.New("failed to lookup bin path")
 	ErrUpdateReqNotImplemented        = errors.New("UpdateRequirementsGetter interface not implemented")
+	ErrBIOSConfigurationUnsupported   = errors.New("BIOS configuration not supported")
 )
 
 // DmiDecodeValueError is returned when a dmidecode value could not be retrieved
@@ -44,3 +47,47 @@ func NewDmidecodeValueError(section, field string, typeID int) *DmiDecodeValueEr
 		Field:   field,
 	}
 }
+
+// FirmwareAlreadyInstalledError is returned when the firmware version to be installed is already installed on the component
+type FirmwareAlreadyInstalledError struct {
+	Slug    string
+	Vendor  string
+	Model   string
+	Version string
+}
+
+// Error implements the error interface
+func (f *FirmwareAlreadyInstalledError) Error() string {
+	return fmt.Sprintf("firmware version %s already installed, component: %s, vendor: %s, model: %s", f.Version, f.Slug, f.Vendor, f.Model)
+}
+
+// NewFirmwareAlreadyInstalledError returns a FirmwareAlreadyInstalledError object
+func NewFirmwareAlreadyInstalledError(slug, vendor, model, version string) *FirmwareAlreadyInstalledError {
+	return &FirmwareAlreadyInstalledError{
+		Slug:    slug,
+		Vendor:  vendor,
+		Model:   model,
+		Version: version,
+	}
+}
+
+// UpdateDryRunError is returned by the firmware updaters when the update options DryRun is set,
+// it includes the update commands resolved, none of which were executed.
+type UpdateDryRunError struct {
+	Commands []*model.UpdateCommand
+}
+
+// Error implements the error interface
+func (u *UpdateDryRunError) Error() string {
+	cmds := make([]string, 0, len(u.Commands))
+	for _, cmd := range u.Commands {
+		cmds = append(cmds, cmd.Cmd)
+	}
+
+	return fmt.Sprintf("dry run, update commands not executed: %q", cmds)
+}
+
+// NewUpdateDryRunError returns a UpdateDryRunError object
+func NewUpdateDryRunError(commands ...*model.UpdateCommand) *UpdateDryRunError {
+	return &UpdateDryRunError{Commands: commands}
+}
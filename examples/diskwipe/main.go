@@ -2,8 +2,10 @@ package main
 
 import (
 	"context"
+	"crypto/ed25519"
+	"encoding/json"
 	"flag"
-	"strings"
+	"os"
 	"time"
 
 	common "github.com/metal-toolbox/bmc-common"
@@ -11,7 +13,6 @@ import (
 
 	"github.com/metal-toolbox/ironlib"
 	"github.com/metal-toolbox/ironlib/actions"
-	"github.com/metal-toolbox/ironlib/utils"
 )
 
 var (
@@ -20,6 +21,10 @@ var (
 	logicalName = flag.String("drive", "/dev/someN", "disk to wipe by filling with zeros")
 	timeout     = flag.String("timeout", defaultTimeout.String(), "time to wait for command to complete")
 	verbose     = flag.Bool("verbose", false, "show command runs and output")
+	journal     = flag.String("journal", "", "directory to persist zero fill progress to, an interrupted wipe is resumed from it")
+	noTrim      = flag.Bool("no-trim", false, "forbid wiping the drive with TRIM (blkdiscard)")
+	certFile    = flag.String("cert", "", "file to write the wipe certificate to")
+	certKey     = flag.String("cert-key", "", "file with the ed25519 private key seed to sign the wipe certificate with")
 )
 
 func main() {
@@ -61,57 +66,57 @@ func main() {
 		l.Fatal("unable to find disk")
 	}
 
-	// Pick the most appropriate wipe based on the disk type and features supported
-	var wiper actions.DriveWiper
-	switch drive.Protocol {
-	case "nvme":
-		wiper = utils.NewNvmeCmd(*verbose)
-	case "sata":
-		// Lets figure out the drive capabilities in an easier format
-		var sanitize bool
-		var esee bool
-		var trim bool
-		for _, cap := range drive.Capabilities {
-			switch {
-			case cap.Description == "encryption supports enhanced erase":
-				esee = cap.Enabled
-			case cap.Description == "SANITIZE feature":
-				sanitize = cap.Enabled
-			case strings.HasPrefix(cap.Description, "Data Set Management TRIM supported"):
-				trim = cap.Enabled
-			}
-		}
+	options := []actions.StorageControllerOption{}
+	if *journal != "" {
+		options = append(options, actions.WithWipeJournalDir(*journal))
+	}
 
-		switch {
-		case sanitize || esee:
-			// Drive supports Sanitize or Enhanced Erase, so we use hdparm
-			wiper = utils.NewHdparmCmd(*verbose)
-		case trim:
-			// Drive supports TRIM, so we use blkdiscard
-			wiper = utils.NewBlkdiscardCmd(*verbose)
-		default:
-			// Drive does not support any preferred wipe method so we fall back to filling it up with zero
-			wiper = utils.NewFillZeroCmd(*verbose)
-
-			// If the user supplied a non-default timeout then we'll honor it, otherwise we just go with a huge timeout.
-			// If this were *real* code and not an example some work could be done to guesstimate a timeout based on disk size.
-			if timeout == defaultTimeout {
-				l.WithField("timeout", timeout.String()).Debug("increasing timeout")
-				timeout = 24 * time.Hour
-				ctx, cancel = context.WithTimeout(context.WithoutCancel(ctx), timeout)
-				defer cancel()
-			}
-		}
+	if *noTrim {
+		options = append(options, actions.WithForbiddenWipeMethods(actions.WipeMethodBlkdiscard))
+	}
+
+	// Pick the most appropriate wipe based on the disk type and features supported,
+	// the wiper falls back to the next best method and finally to filling the drive with zeros.
+	wiper, err := actions.NewStorageControllerAction(logger, options...).GetWipeUtility(drive)
+	if err != nil {
+		l.WithError(err).Fatal("failed find appropriate wiper drive")
 	}
 
-	if wiper == nil {
-		l.Fatal("failed find appropriate wiper drive")
+	// If the user supplied a non-default timeout then we'll honor it, otherwise we just go with a huge timeout
+	// since the wipe may fall back to filling the drive with zeros.
+	// If this were *real* code and not an example some work could be done to guesstimate a timeout based on disk size.
+	if timeout == defaultTimeout {
+		l.WithField("timeout", timeout.String()).Debug("increasing timeout")
+		timeout = 24 * time.Hour
+		ctx, cancel = context.WithTimeout(context.WithoutCancel(ctx), timeout)
+		defer cancel()
+	}
+
+	var key ed25519.PrivateKey
+	if *certKey != "" {
+		seed, err := os.ReadFile(*certKey)
+		if err != nil || len(seed) != ed25519.SeedSize {
+			l.WithError(err).Fatal("failed to read ed25519 private key seed")
+		}
+
+		key = ed25519.NewKeyFromSeed(seed)
 	}
 
 	ll := logger
 	ll.SetLevel(logrus.DebugLevel)
-	err = wiper.WipeDrive(ctx, ll, drive)
+	cert, err := actions.WipeDriveWithCertificate(ctx, ll, wiper, drive, key)
 	if err != nil {
 		l.Fatal("failed to wipe drive")
 	}
+
+	if *certFile != "" {
+		b, err := json.MarshalIndent(cert, "", "  ")
+		if err != nil {
+			l.WithError(err).Fatal("failed to marshal wipe certificate")
+		}
+
+		if err := os.WriteFile(*certFile, b, 0o600); err != nil {
+			l.WithError(err).Fatal("failed to write wipe certificate")
+		}
+	}
 }
@@ -3071,6 +3071,14 @@ var (
 						Previous:   nil,
 					},
 					Status: nil,
+					Metadata: map[string]string{
+						model.DriveHealthPowerOnHours:    "301",
+						model.DriveHealthTemperature:     "37",
+						model.DriveHealthPercentageUsed:  "0",
+						model.DriveHealthMediaErrors:     "0",
+						model.DriveHealthCriticalWarning: "0",
+						model.DriveHealthAvailableSpare:  "100",
+					},
 				},
 				ID:                       "",
 				OemID:                    "",
@@ -3104,6 +3112,14 @@ var (
 						Previous:   nil,
 					},
 					Status: nil,
+					Metadata: map[string]string{
+						model.DriveHealthPowerOnHours:    "301",
+						model.DriveHealthTemperature:     "36",
+						model.DriveHealthPercentageUsed:  "0",
+						model.DriveHealthMediaErrors:     "0",
+						model.DriveHealthCriticalWarning: "0",
+						model.DriveHealthAvailableSpare:  "100",
+					},
 				},
 				ID:                       "",
 				OemID:                    "",
@@ -3137,6 +3153,13 @@ var (
 						Previous:   nil,
 					},
 					Status: nil,
+					Metadata: map[string]string{
+						model.DriveHealthReallocatedSectors:   "0",
+						model.DriveHealthUncorrectableSectors: "0",
+						model.DriveHealthWearLeveling:         "100",
+						model.DriveHealthPowerOnHours:         "306",
+						model.DriveHealthTemperature:          "49",
+					},
 				},
 				ID:                       "",
 				OemID:                    "DELL(tm)",
@@ -3170,6 +3193,13 @@ var (
 						Previous:   nil,
 					},
 					Status: nil,
+					Metadata: map[string]string{
+						model.DriveHealthReallocatedSectors:   "0",
+						model.DriveHealthUncorrectableSectors: "0",
+						model.DriveHealthWearLeveling:         "100",
+						model.DriveHealthPowerOnHours:         "306",
+						model.DriveHealthTemperature:          "47",
+					},
 				},
 				ID:                       "",
 				OemID:                    "DELL(tm)",
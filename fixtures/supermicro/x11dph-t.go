@@ -2388,6 +2388,14 @@ var (
 						Previous:   nil,
 					},
 					Status: nil,
+					Metadata: map[string]string{
+						model.DriveHealthReallocatedSectors:   "0",
+						model.DriveHealthPendingSectors:       "0",
+						model.DriveHealthUncorrectableSectors: "0",
+						model.DriveHealthWearLeveling:         "100",
+						model.DriveHealthPowerOnHours:         "4238",
+						model.DriveHealthTemperature:          "39",
+					},
 				},
 				ID:                       "",
 				OemID:                    "",
@@ -2420,6 +2428,14 @@ var (
 						Previous:   nil,
 					},
 					Status: nil,
+					Metadata: map[string]string{
+						model.DriveHealthReallocatedSectors:   "0",
+						model.DriveHealthPendingSectors:       "0",
+						model.DriveHealthUncorrectableSectors: "0",
+						model.DriveHealthWearLeveling:         "100",
+						model.DriveHealthPowerOnHours:         "4277",
+						model.DriveHealthTemperature:          "38",
+					},
 				},
 				ID:                       "",
 				OemID:                    "",
@@ -2452,6 +2468,14 @@ var (
 						Previous:   nil,
 					},
 					Status: nil,
+					Metadata: map[string]string{
+						model.DriveHealthPowerOnHours:    "4279",
+						model.DriveHealthTemperature:     "44",
+						model.DriveHealthPercentageUsed:  "2",
+						model.DriveHealthMediaErrors:     "0",
+						model.DriveHealthCriticalWarning: "0",
+						model.DriveHealthAvailableSpare:  "100",
+					},
 				},
 				ID:                       "",
 				OemID:                    "",
@@ -2484,6 +2508,14 @@ var (
 						Previous:   nil,
 					},
 					Status: nil,
+					Metadata: map[string]string{
+						model.DriveHealthPowerOnHours:    "4279",
+						model.DriveHealthTemperature:     "44",
+						model.DriveHealthPercentageUsed:  "2",
+						model.DriveHealthMediaErrors:     "0",
+						model.DriveHealthCriticalWarning: "0",
+						model.DriveHealthAvailableSpare:  "100",
+					},
 				},
 				ID:                       "",
 				OemID:                    "",
@@ -2516,6 +2548,13 @@ var (
 						Previous:   nil,
 					},
 					Status: nil,
+					Metadata: map[string]string{
+						model.DriveHealthReallocatedSectors:   "0",
+						model.DriveHealthPendingSectors:       "0",
+						model.DriveHealthUncorrectableSectors: "0",
+						model.DriveHealthPowerOnHours:         "4280",
+						model.DriveHealthTemperature:          "32",
+					},
 				},
 				ID:                       "",
 				OemID:                    "",
@@ -2548,6 +2587,13 @@ var (
 						Previous:   nil,
 					},
 					Status: nil,
+					Metadata: map[string]string{
+						model.DriveHealthReallocatedSectors:   "0",
+						model.DriveHealthPendingSectors:       "0",
+						model.DriveHealthUncorrectableSectors: "0",
+						model.DriveHealthPowerOnHours:         "4280",
+						model.DriveHealthTemperature:          "35",
+					},
 				},
 				ID:                       "",
 				OemID:                    "",
@@ -2580,6 +2626,13 @@ var (
 						Previous:   nil,
 					},
 					Status: nil,
+					Metadata: map[string]string{
+						model.DriveHealthReallocatedSectors:   "0",
+						model.DriveHealthPendingSectors:       "0",
+						model.DriveHealthUncorrectableSectors: "0",
+						model.DriveHealthPowerOnHours:         "4280",
+						model.DriveHealthTemperature:          "34",
+					},
 				},
 				ID:                       "",
 				OemID:                    "",
@@ -2612,6 +2665,13 @@ var (
 						Previous:   nil,
 					},
 					Status: nil,
+					Metadata: map[string]string{
+						model.DriveHealthReallocatedSectors:   "0",
+						model.DriveHealthPendingSectors:       "0",
+						model.DriveHealthUncorrectableSectors: "0",
+						model.DriveHealthPowerOnHours:         "4280",
+						model.DriveHealthTemperature:          "33",
+					},
 				},
 				ID:                       "",
 				OemID:                    "",
@@ -2644,6 +2704,13 @@ var (
 						Previous:   nil,
 					},
 					Status: nil,
+					Metadata: map[string]string{
+						model.DriveHealthReallocatedSectors:   "0",
+						model.DriveHealthPendingSectors:       "0",
+						model.DriveHealthUncorrectableSectors: "0",
+						model.DriveHealthPowerOnHours:         "4280",
+						model.DriveHealthTemperature:          "33",
+					},
 				},
 				ID:                       "",
 				OemID:                    "",
@@ -2676,6 +2743,13 @@ var (
 						Previous:   nil,
 					},
 					Status: nil,
+					Metadata: map[string]string{
+						model.DriveHealthReallocatedSectors:   "0",
+						model.DriveHealthPendingSectors:       "0",
+						model.DriveHealthUncorrectableSectors: "0",
+						model.DriveHealthPowerOnHours:         "4280",
+						model.DriveHealthTemperature:          "32",
+					},
 				},
 				ID:                       "",
 				OemID:                    "",
@@ -2708,6 +2782,13 @@ var (
 						Previous:   nil,
 					},
 					Status: nil,
+					Metadata: map[string]string{
+						model.DriveHealthReallocatedSectors:   "0",
+						model.DriveHealthPendingSectors:       "0",
+						model.DriveHealthUncorrectableSectors: "0",
+						model.DriveHealthPowerOnHours:         "4280",
+						model.DriveHealthTemperature:          "31",
+					},
 				},
 				ID:                       "",
 				OemID:                    "",
@@ -2740,6 +2821,13 @@ var (
 						Previous:   nil,
 					},
 					Status: nil,
+					Metadata: map[string]string{
+						model.DriveHealthReallocatedSectors:   "0",
+						model.DriveHealthPendingSectors:       "0",
+						model.DriveHealthUncorrectableSectors: "0",
+						model.DriveHealthPowerOnHours:         "4280",
+						model.DriveHealthTemperature:          "32",
+					},
 				},
 				ID:                       "",
 				OemID:                    "",
@@ -2772,6 +2860,13 @@ var (
 						Previous:   nil,
 					},
 					Status: nil,
+					Metadata: map[string]string{
+						model.DriveHealthReallocatedSectors:   "0",
+						model.DriveHealthPendingSectors:       "0",
+						model.DriveHealthUncorrectableSectors: "0",
+						model.DriveHealthPowerOnHours:         "4280",
+						model.DriveHealthTemperature:          "32",
+					},
 				},
 				ID:                       "",
 				OemID:                    "",
@@ -2804,6 +2899,13 @@ var (
 						Previous:   nil,
 					},
 					Status: nil,
+					Metadata: map[string]string{
+						model.DriveHealthReallocatedSectors:   "0",
+						model.DriveHealthPendingSectors:       "0",
+						model.DriveHealthUncorrectableSectors: "0",
+						model.DriveHealthPowerOnHours:         "4280",
+						model.DriveHealthTemperature:          "32",
+					},
 				},
 				ID:                       "",
 				OemID:                    "",
@@ -2836,6 +2938,13 @@ var (
 						Previous:   nil,
 					},
 					Status: nil,
+					Metadata: map[string]string{
+						model.DriveHealthReallocatedSectors:   "0",
+						model.DriveHealthPendingSectors:       "0",
+						model.DriveHealthUncorrectableSectors: "0",
+						model.DriveHealthPowerOnHours:         "4280",
+						model.DriveHealthTemperature:          "33",
+					},
 				},
 				ID:                       "",
 				OemID:                    "",
@@ -2868,6 +2977,13 @@ var (
 						Previous:   nil,
 					},
 					Status: nil,
+					Metadata: map[string]string{
+						model.DriveHealthReallocatedSectors:   "0",
+						model.DriveHealthPendingSectors:       "0",
+						model.DriveHealthUncorrectableSectors: "0",
+						model.DriveHealthPowerOnHours:         "4280",
+						model.DriveHealthTemperature:          "33",
+					},
 				},
 				ID:                       "",
 				OemID:                    "",
@@ -0,0 +1,57 @@
+package model
+
+// BIOSConfigurationChanges is returned when BIOS settings are applied,
+// the settings are keyed by the vendor native setting name.
+type BIOSConfigurationChanges struct {
+	// Applied holds settings that were read back with the requested value.
+	Applied map[string]string `json:"applied,omitempty"`
+	// Pending holds settings that were written but are not reflected in the BIOS configuration read back,
+	// generally these take effect once the host is rebooted.
+	Pending map[string]string `json:"pending,omitempty"`
+	// Unchanged holds settings that already had the requested value and were not written.
+	Unchanged map[string]string `json:"unchanged,omitempty"`
+}
+
+// NewBIOSConfigurationChanges returns an initialized BIOSConfigurationChanges object
+func NewBIOSConfigurationChanges() *BIOSConfigurationChanges {
+	return &BIOSConfigurationChanges{
+		Applied:   map[string]string{},
+		Pending:   map[string]string{},
+		Unchanged: map[string]string{},
+	}
+}
+
+// BIOSConfigurationDiff is the result of comparing a desired BIOS configuration with the current configuration,
+// the settings are keyed by the setting name as given in the desired configuration.
+type BIOSConfigurationDiff struct {
+	// Matching holds settings where the current value is the desired value.
+	Matching map[string]string `json:"matching,omitempty"`
+	// Differing holds settings where the current value differs from the desired value.
+	Differing map[string]BIOSSettingDiff `json:"differing,omitempty"`
+	// Missing holds normalized settings that are not present in the current configuration,
+	// generally these are settings that are not applicable to the vendor or platform.
+	Missing map[string]string `json:"missing,omitempty"`
+	// Unknown holds raw or vendor native settings that are not present in the current configuration.
+	Unknown map[string]string `json:"unknown,omitempty"`
+}
+
+// BIOSSettingDiff holds the desired and current value of a BIOS setting
+type BIOSSettingDiff struct {
+	Desired string `json:"desired"`
+	Current string `json:"current"`
+}
+
+// NewBIOSConfigurationDiff returns an initialized BIOSConfigurationDiff object
+func NewBIOSConfigurationDiff() *BIOSConfigurationDiff {
+	return &BIOSConfigurationDiff{
+		Matching:  map[string]string{},
+		Differing: map[string]BIOSSettingDiff{},
+		Missing:   map[string]string{},
+		Unknown:   map[string]string{},
+	}
+}
+
+// InSync returns true when all the desired settings match the current configuration
+func (d *BIOSConfigurationDiff) InSync() bool {
+	return len(d.Differing) == 0 && len(d.Missing) == 0 && len(d.Unknown) == 0
+}
@@ -0,0 +1,21 @@
+package model
+
+// FirmwareBundleManifestFile is the name of the manifest file at the root of a firmware bundle
+const FirmwareBundleManifestFile = "manifest.json"
+
+// FirmwareBundleManifest lists the firmware files included in a firmware bundle
+// and the components they apply to.
+type FirmwareBundleManifest struct {
+	Name       string                     `json:"name"`
+	Components []*FirmwareBundleComponent `json:"components"`
+}
+
+// FirmwareBundleComponent is a firmware file in a firmware bundle and the components it applies to
+type FirmwareBundleComponent struct {
+	Slug     string   `json:"slug"`     // The component slug - BIOS, BMC, NIC...
+	Vendor   string   `json:"vendor"`   // The component vendor
+	Models   []string `json:"models"`   // Regular expressions matched against the component model, the firmware applies to any component when empty
+	Version  string   `json:"version"`  // The firmware version the file installs
+	Checksum string   `json:"checksum"` // The SHA-256 checksum of the file, hex encoded
+	File     string   `json:"file"`     // The firmware file path relative to the bundle root
+}
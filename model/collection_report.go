@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+)
+
+// CollectionReport records the collectors executed for an inventory collection,
+// it allows a partial inventory - where one or more collectors failed, to be told apart from a complete one.
+type CollectionReport struct {
+	Started time.Time `json:"started"`
+	Ended   time.Time `json:"ended"`
+	// Collectors are the collectors executed in the order they were executed in.
+	Collectors []*CollectorReport `json:"collectors"`
+}
+
+// CollectorReport is the result of a collector executed for an inventory collection
+type CollectorReport struct {
+	// Name is the collector utility name as returned by its Attributes() method.
+	Name CollectorUtility `json:"name"`
+	// Path is the absolute path to the collector utility binary, this is empty when the utility is not a binary or not found.
+	Path string `json:"path,omitempty"`
+	// Duration is the time spent executing the collector.
+	Duration time.Duration `json:"duration"`
+	// Error is the collector error, this is empty when the collector succeeded.
+	Error string `json:"error,omitempty"`
+	// ComponentsTouched is the number of device components the collector added or updated.
+	ComponentsTouched int `json:"components_touched"`
+}
+
+// Complete returns true when none of the collectors failed
+func (r *CollectionReport) Complete() bool {
+	return len(r.Failed()) == 0
+}
+
+// Failed returns the collectors that returned an error
+func (r *CollectionReport) Failed() []*CollectorReport {
+	failed := []*CollectorReport{}
+
+	for _, c := range r.Collectors {
+		if c.Error != "" {
+			failed = append(failed, c)
+		}
+	}
+
+	return failed
+}
@@ -0,0 +1,31 @@
+package model
+
+// Drive health metadata keys
+//
+// The drive collectors record the drive SMART health metrics in the common.Drive Metadata under these keys,
+// a key is only set when the drive reports the metric.
+const (
+	// DriveHealthReallocatedSectors is the ATA reallocated sector count - attribute 5.
+	DriveHealthReallocatedSectors = "smart-reallocated-sectors"
+	// DriveHealthPendingSectors is the ATA current pending sector count - attribute 197.
+	DriveHealthPendingSectors = "smart-pending-sectors"
+	// DriveHealthUncorrectableSectors is the ATA offline uncorrectable sector count - attribute 198.
+	DriveHealthUncorrectableSectors = "smart-uncorrectable-sectors"
+	// DriveHealthWearLeveling is the normalized value of the ATA SSD wear attribute,
+	// this counts down from 100 as the drive wears out.
+	DriveHealthWearLeveling = "smart-wear-leveling"
+	// DriveHealthPowerOnHours is the number of hours the drive has been powered on.
+	DriveHealthPowerOnHours = "smart-power-on-hours"
+	// DriveHealthTemperature is the current drive temperature in degrees celsius.
+	DriveHealthTemperature = "smart-temperature-celsius"
+	// DriveHealthPercentageUsed is the NVMe estimate of the drive life used in percent, this may exceed 100.
+	DriveHealthPercentageUsed = "smart-percentage-used"
+	// DriveHealthMediaErrors is the NVMe count of unrecovered data integrity errors.
+	DriveHealthMediaErrors = "smart-media-errors"
+	// DriveHealthCriticalWarning is the NVMe critical warning bit field, 0 when there are no warnings.
+	DriveHealthCriticalWarning = "smart-critical-warning"
+	// DriveHealthAvailableSpare is the NVMe remaining spare capacity in percent.
+	DriveHealthAvailableSpare = "smart-available-spare"
+	// DriveHealthGrownDefects is the number of entries in the SCSI grown defect list.
+	DriveHealthGrownDefects = "smart-grown-defects"
+)
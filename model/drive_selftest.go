@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+)
+
+// DriveSelfTestType is the type of drive self-test to run
+type DriveSelfTestType string
+
+const (
+	// DriveSelfTestShort is the short self-test, this usually completes within a few minutes.
+	DriveSelfTestShort DriveSelfTestType = "short"
+	// DriveSelfTestExtended is the extended self-test, this reads the whole drive surface and may take hours to complete.
+	DriveSelfTestExtended DriveSelfTestType = "extended"
+)
+
+// DriveSelfTestOptions are the options for running self-tests on a batch of drives
+type DriveSelfTestOptions struct {
+	// Type is the type of self-test to run, defaults to the short self-test.
+	Type DriveSelfTestType
+	// Concurrency is the maximum number of drives tested at a time, defaults to the number of drives.
+	Concurrency int
+	// PollInterval is the interval the drive self-test progress is polled at.
+	PollInterval time.Duration
+}
+
+// DriveSelfTestStatus is the self-test state reported by a drive
+type DriveSelfTestStatus struct {
+	// InProgress is set when a self-test is running on the drive.
+	InProgress bool
+	// RemainingPercent is the percentage of the running self-test remaining, this is 0 when no self-test is running.
+	RemainingPercent int
+	// Latest is the most recent self-test log entry, this is nil when the drive self-test log is empty.
+	Latest *DriveSelfTestLogEntry
+	// LogEntries is the number of entries in the drive self-test log, this is 0 when not reported - SCSI drives.
+	//
+	// The drive self-test log holds a limited number of entries, the count stops increasing once the log is full.
+	LogEntries int
+}
+
+// DriveSelfTestLogEntry is an entry in the drive self-test log
+type DriveSelfTestLogEntry struct {
+	// Type is the self-test type as described by the drive, for example Short offline, Extended.
+	Type string `json:"type"`
+	// Status is the self-test result as described by the drive.
+	Status string `json:"status"`
+	Passed bool   `json:"passed"`
+	// PowerOnHours is the drive power on hours when the self-test completed.
+	PowerOnHours int64 `json:"power_on_hours"`
+	// FailingLBA is the first logical block address the self-test failed on, this is nil when not reported.
+	FailingLBA *uint64 `json:"failing_lba,omitempty"`
+}
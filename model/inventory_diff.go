@@ -0,0 +1,51 @@
+package model
+
+// InventoryDiff is the difference between two device inventory snapshots
+type InventoryDiff struct {
+	// Added are the components present only in the later snapshot.
+	Added []*InventoryComponent `json:"added,omitempty"`
+	// Removed are the components present only in the earlier snapshot.
+	Removed []*InventoryComponent `json:"removed,omitempty"`
+	// Changed are the components present in both snapshots with differing fields.
+	Changed []*InventoryComponentChange `json:"changed,omitempty"`
+	// Firmware are the firmware version changes of the components present in both snapshots.
+	Firmware []*FirmwareDelta `json:"firmware,omitempty"`
+}
+
+// Empty returns true when the snapshots compared are the same
+func (d *InventoryDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0 && len(d.Firmware) == 0
+}
+
+// InventoryComponent identifies a component in an inventory snapshot
+type InventoryComponent struct {
+	// Slug is the component type, for example Drive, NIC.
+	Slug string `json:"slug"`
+	// Key identifies the component among components of the same type,
+	// this is the serial, the logical name or a type specific identifier in that order of preference.
+	Key    string `json:"key"`
+	Vendor string `json:"vendor,omitempty"`
+	Model  string `json:"model,omitempty"`
+	Serial string `json:"serial,omitempty"`
+}
+
+// InventoryComponentChange is a component with fields that differ between the snapshots
+type InventoryComponentChange struct {
+	InventoryComponent
+	Fields []*InventoryFieldChange `json:"fields"`
+}
+
+// InventoryFieldChange is a component field that differs between the snapshots
+type InventoryFieldChange struct {
+	// Path is the dot separated path to the field, for example Firmware.Installed.
+	Path string `json:"path"`
+	From any    `json:"from"`
+	To   any    `json:"to"`
+}
+
+// FirmwareDelta is a component firmware version that differs between the snapshots
+type FirmwareDelta struct {
+	InventoryComponent
+	From string `json:"from"`
+	To   string `json:"to"`
+}
@@ -1,10 +1,17 @@
 package model
 
+// DeviceMetadataVirtualDisks is the common.Device Metadata key the virtual disks collected are recorded under,
+// the value is a JSON list of the common.VirtualDisk listed by the virtual disk collectors.
+const DeviceMetadataVirtualDisks = "virtual-disks"
+
 type CreateVirtualDiskOptions struct {
 	RaidMode        string
 	PhysicalDiskIDs []uint
-	Name            string
-	BlockSize       uint
+	// PhysicalDiskNames are the logical names of the drives - /dev/sdX, used for software RAID
+	// where the drives are not identified by a storage controller ID.
+	PhysicalDiskNames []string
+	Name              string
+	BlockSize         uint
 }
 
 type DestroyVirtualDiskOptions struct {
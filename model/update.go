@@ -1,5 +1,9 @@
 package model
 
+import (
+	common "github.com/metal-toolbox/bmc-common"
+)
+
 // UpdateOptions sets firmware update options for a device component
 type UpdateOptions struct {
 	ForceInstall      bool // Allow firmware to be downgraded
@@ -14,6 +18,12 @@ type UpdateOptions struct {
 	InstallerVersion  string // The all available updates installer version (specific to dell DSU)
 	RepositoryVersion string // The update repository version to activate when defined
 	BaseURL           string // The BaseURL for the updates
+	Version           string // The firmware version the UpdateFile installs, the update is skipped when the version is installed
+	Checksum          string // The SHA-256 checksum of the UpdateFile, hex encoded
+	SignatureFile     string // Location of the detached signature of the UpdateFile
+	PublicKeyFile     string // Location of the PEM encoded public key the SignatureFile is verified with
+	Verify            bool   // Re-collect the component inventory after the install to verify the Version is installed
+	DryRun            bool   // Resolve the components and the update commands, the update commands are not executed
 }
 
 // UpdateRequirements are returned by utilities to help the caller identify actions (if any)
@@ -21,4 +31,47 @@ type UpdateOptions struct {
 type UpdateRequirements struct {
 	PostInstallReconfiguration bool // The component requires a re-configuration post firmware install
 	PostInstallHostPowercycle  bool // The component requires a host power-cycle post firmware install
+	PostInstallHostReboot      bool // The component requires a host reboot post firmware install
+}
+
+// UpdateStatus is the status of a firmware install determined from the component inventory collected after the install
+type UpdateStatus string
+
+const (
+	// The firmware version is installed and running on the component
+	UpdateStatusInstalledActive UpdateStatus = "installed-active"
+	// The firmware version is installed and is activated on a reboot or powercycle
+	UpdateStatusStagedPending UpdateStatus = "staged-pending"
+	// The firmware version is not installed on the component
+	UpdateStatusFailed UpdateStatus = "failed"
+	// The firmware was not installed since the update of a component it depends on failed
+	UpdateStatusSkipped UpdateStatus = "skipped"
+	// The update commands were resolved and not executed
+	UpdateStatusDryRun UpdateStatus = "dry-run"
+)
+
+// UpdateResult is the result of a firmware install verified with the component inventory
+type UpdateResult struct {
+	Slug             string
+	Vendor           string
+	Model            string
+	Serial           string
+	Version          string              // The firmware version expected to be installed
+	InstalledVersion string              // The firmware version reported by the inventory collector
+	Status           UpdateStatus        // The status of the firmware install
+	Requirements     *UpdateRequirements // The requirements to be met post firmware install, if any
+	Err              error               // The error returned installing the firmware, if any
+	Commands         []*UpdateCommand    // The update commands resolved when the options DryRun is set
+}
+
+// UpdateCommand is a command the firmware updater executes to install the firmware
+type UpdateCommand struct {
+	Cmd        string           // The command line
+	Components []*common.Common // The components the firmware is installed on
+}
+
+// UpdatePlanResult is the result of the firmware updates installed in the order of the component dependencies
+type UpdatePlanResult struct {
+	Requirements *UpdateRequirements // The requirements merged from each of the firmware updates installed
+	Results      []*UpdateResult     // The result of each of the firmware updates in the order installed
 }
@@ -0,0 +1,122 @@
+package model
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrWipeCertificateUnsigned  = errors.New("wipe certificate is not signed")
+	ErrWipeCertificateSignature = errors.New("wipe certificate signature is not valid")
+)
+
+// WipeCertificate is the record of a successful drive wipe
+type WipeCertificate struct {
+	Drive   WipeCertificateDrive `json:"drive"`
+	Started time.Time            `json:"started"`
+	Ended   time.Time            `json:"ended"`
+	// Methods are the wipe methods attempted in order, the last method is the one that succeeded.
+	Methods []WipeMethodAttempt `json:"methods"`
+	// Verifications are the results of verifying the drive was wiped.
+	Verifications []WipeVerification `json:"verifications"`
+	// PublicKey is the ed25519 public key to verify the signature with.
+	PublicKey []byte `json:"public_key,omitempty"`
+	// Signature is the ed25519 signature of the certificate JSON without the signature field.
+	Signature []byte `json:"signature,omitempty"`
+}
+
+// WipeCertificateDrive identifies the wiped drive
+type WipeCertificateDrive struct {
+	LogicalName   string `json:"logical_name"`
+	Vendor        string `json:"vendor,omitempty"`
+	Model         string `json:"model,omitempty"`
+	Serial        string `json:"serial"`
+	Firmware      string `json:"firmware,omitempty"`
+	Protocol      string `json:"protocol,omitempty"`
+	CapacityBytes int64  `json:"capacity_bytes,omitempty"`
+}
+
+// WipeMethodAttempt is a wipe method attempted on the drive
+type WipeMethodAttempt struct {
+	// Method is the wipe method, for example sanitize-crypto-erase, format-ses-user-data-erase, zero-fill.
+	Method  string    `json:"method"`
+	Started time.Time `json:"started"`
+	Ended   time.Time `json:"ended"`
+	// Error is set when the method failed.
+	Error string `json:"error,omitempty"`
+}
+
+// WipeVerification is the result of verifying the drive was wiped
+type WipeVerification struct {
+	// Method is the verification method, watermarks or readback.
+	Method string `json:"method"`
+	Passed bool   `json:"passed"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Sign signs the certificate with the given ed25519 key
+func (c *WipeCertificate) Sign(key ed25519.PrivateKey) error {
+	publicKey, ok := key.Public().(ed25519.PublicKey)
+	if !ok {
+		return errors.New("unexpected ed25519 public key type")
+	}
+
+	c.PublicKey = publicKey
+
+	payload, err := c.signedPayload()
+	if err != nil {
+		return err
+	}
+
+	c.Signature = ed25519.Sign(key, payload)
+
+	return nil
+}
+
+// Verify verifies the certificate signature with the public key included in the certificate,
+// callers should ensure the public key is one they trust.
+func (c *WipeCertificate) Verify() error {
+	if len(c.Signature) == 0 || len(c.PublicKey) != ed25519.PublicKeySize {
+		return ErrWipeCertificateUnsigned
+	}
+
+	payload, err := c.signedPayload()
+	if err != nil {
+		return err
+	}
+
+	if !ed25519.Verify(ed25519.PublicKey(c.PublicKey), payload, c.Signature) {
+		return ErrWipeCertificateSignature
+	}
+
+	return nil
+}
+
+// signedPayload returns the certificate JSON without the signature
+func (c *WipeCertificate) signedPayload() ([]byte, error) {
+	unsigned := *c
+	unsigned.Signature = nil
+
+	return json.Marshal(unsigned)
+}
+
+// WipeDrivesOptions are the options for wiping a batch of drives
+type WipeDrivesOptions struct {
+	// Concurrency is the maximum number of drives wiped at a time, defaults to the number of drives.
+	Concurrency int
+	// ControllerConcurrency is the maximum number of drives wiped at a time on the same storage controller,
+	// no limit is applied when set to 0.
+	ControllerConcurrency int
+	// MinTimeout is the minimum time allowed to wipe a drive, the drive timeout is this value
+	// plus the time to write the drive capacity at BytesPerSecond.
+	MinTimeout time.Duration
+	// BytesPerSecond is the expected worst case write throughput used to derive the drive timeout from its capacity.
+	BytesPerSecond int64
+	// MaxTimeout is the timeout for drives with an unknown capacity and the upper bound for the derived timeout.
+	MaxTimeout time.Duration
+	// CertificateKey is the ed25519 key to sign the wipe certificates with, the certificates are not signed when nil.
+	CertificateKey ed25519.PrivateKey
+}
@@ -3,12 +3,30 @@ package asrockrack
 import (
 	"context"
 
+	common "github.com/metal-toolbox/bmc-common"
+	"github.com/metal-toolbox/ironlib/actions"
 	"github.com/metal-toolbox/ironlib/model"
 	"github.com/metal-toolbox/ironlib/utils"
 )
 
-func (a *asrockrack) SetBIOSConfiguration(context.Context, map[string]string) error {
-	return nil
+func (a *asrockrack) SetBIOSConfiguration(ctx context.Context, cfg map[string]string) (*model.BIOSConfigurationChanges, error) {
+	asrr := utils.NewAsrrBioscontrol(false)
+
+	vendorCfg, err := utils.DenormalizeBIOSConfiguration(common.VendorAsrockrack, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := actions.SetBIOSConfiguration(ctx, asrr, model.FormatProductName(a.GetModel()), vendorCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(changes.Pending) > 0 {
+		a.hw.PendingReboot = true
+	}
+
+	return changes, nil
 }
 
 func (a *asrockrack) GetBIOSConfiguration(ctx context.Context) (map[string]string, error) {
@@ -4,12 +4,44 @@ import (
 	"context"
 	"os"
 
+	common "github.com/metal-toolbox/bmc-common"
+	"github.com/metal-toolbox/ironlib/actions"
 	"github.com/metal-toolbox/ironlib/model"
 	"github.com/metal-toolbox/ironlib/utils"
 )
 
-func (d *dell) SetBIOSConfiguration(context.Context, map[string]string) error {
-	return nil
+// SetBIOSConfiguration applies the given racadm BIOS settings as a job, which completes on the next host reboot.
+func (d *dell) SetBIOSConfiguration(ctx context.Context, cfg map[string]string) (*model.BIOSConfigurationChanges, error) {
+	vendorCfg, err := utils.DenormalizeBIOSConfiguration(common.VendorDell, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if envRacadmUtil := os.Getenv("IRONLIB_UTIL_RACADM7"); envRacadmUtil == "" {
+		err := d.pre(ctx) // ensure runtime pre-requisites are installed
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Make sure service that loads ipmi modules is running before attempting to set the bios config
+	err = d.startSrvHelper(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	racadm := utils.NewDellRacadm(d.trace)
+
+	changes, err := actions.SetBIOSConfiguration(ctx, racadm, model.FormatProductName(d.GetModel()), vendorCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(changes.Pending) > 0 {
+		d.hw.PendingReboot = true
+	}
+
+	return changes, nil
 }
 
 func (d *dell) GetBIOSConfiguration(ctx context.Context) (map[string]string, error) {
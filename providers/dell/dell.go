@@ -172,19 +172,44 @@ func (d *dell) UpdateRequirements(_ context.Context, _, _, _ string) (*model.Upd
 }
 
 // InstallUpdates for Dells based on updateOptions
+//
+// When the options Verify is set, the update file install is verified with the DSU inventory,
+// updates installed with InstallAll are not verified.
+//
+// When the options DryRun is set, the update commands are returned as an *errs.UpdateDryRunError and not executed.
 func (d *dell) InstallUpdates(ctx context.Context, options *model.UpdateOptions) error {
 	d.setUpdateOptions(options)
 
+	if options.DryRun {
+		return d.dryRunUpdates(ctx, options)
+	}
+
 	if options.InstallAll {
 		return d.installAvailableUpdates(ctx, options.DownloadOnly)
 	}
 
+	if options.Verify && options.Version == "" {
+		return actions.ErrUpdateVerifyVersionUndefined
+	}
+
 	exitCode, err := d.installUpdate(ctx, options.UpdateFile, options.ForceInstall)
-	if err != nil {
+
+	// the firmware is pending activation only when this install exits with a reboot required exit code,
+	// the device PendingReboot flag remains set from earlier installs.
+	pendingActivation := rebootRequiredExitCode(exitCode)
+	if err != nil && !pendingActivation {
 		return err
 	}
 
-	return d.checkExitCode(exitCode)
+	if err := d.checkExitCode(exitCode); err != nil {
+		return err
+	}
+
+	if !options.Verify {
+		return nil
+	}
+
+	return d.verifyUpdate(ctx, options, pendingActivation)
 }
 
 // installAvailableUpdates runs DSU to install all available updates
@@ -10,8 +10,10 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/metal-toolbox/ironlib/actions"
+	"github.com/metal-toolbox/ironlib/errs"
 	dellFixtures "github.com/metal-toolbox/ironlib/fixtures/dell"
 	"github.com/metal-toolbox/ironlib/model"
 	"github.com/metal-toolbox/ironlib/utils"
@@ -129,3 +131,153 @@ func TestListUpdates(t *testing.T) {
 
 	assert.Equal(t, dellFixtures.R6515_updatePreview, device)
 }
+
+func TestInstallUpdatesVerify(t *testing.T) {
+	b, err := os.ReadFile(r6515fixtures + "/dsu_inventory")
+	if err != nil {
+		t.Error(err)
+	}
+
+	// skip the update file and "/usr/libexec/instsvcdrv-helper start" from being executed
+	os.Setenv("IRONLIB_TEST", "1")
+
+	testcases := []struct {
+		name    string
+		options *model.UpdateOptions
+		wantErr error
+	}{
+		{
+			"bios installed",
+			&model.UpdateOptions{Slug: common.SlugBIOS, UpdateFile: "BIOS_CR1K4_LN_1.7.4.BIN", Version: "1.7.4", Verify: true},
+			nil,
+		},
+		{
+			// the update bin exits without the reboot required exit code
+			"bios version not installed",
+			&model.UpdateOptions{Slug: common.SlugBIOS, UpdateFile: "BIOS_CR1K4_LN_2.9.4.BIN", Version: "2.9.4", Verify: true},
+			actions.ErrUpdateVerify,
+		},
+		{
+			"verify without version",
+			&model.UpdateOptions{Slug: common.SlugBIOS, UpdateFile: "BIOS_CR1K4_LN_2.9.4.BIN", Verify: true},
+			actions.ErrUpdateVerifyVersionUndefined,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			logger, hook := test.NewNullLogger()
+			defer hook.Reset()
+
+			dell := newFakeDellDevice(logger)
+
+			dsu, err := utils.NewFakeDsu(bytes.NewReader(b))
+			if err != nil {
+				t.Error(err)
+			}
+
+			dell.dsu = dsu
+
+			err = dell.InstallUpdates(context.TODO(), tc.options)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestVerifyUpdatePendingActivation(t *testing.T) {
+	b, err := os.ReadFile(r6515fixtures + "/dsu_inventory")
+	require.NoError(t, err)
+
+	// skip "/usr/libexec/instsvcdrv-helper start" from being executed
+	t.Setenv("IRONLIB_TEST", "1")
+
+	testcases := []struct {
+		name     string
+		exitCode int
+		wantErr  error
+	}{
+		{"update bin reboot required", BinUpdateExitCodeRebootRequired, utils.ErrRebootRequired},
+		{"dsu reboot required", utils.DSUExitCodeRebootRequired, utils.ErrRebootRequired},
+		{"update applied", utils.DSUExitCodeUpdatesApplied, actions.ErrUpdateVerify},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			logger, _ := test.NewNullLogger()
+
+			dell := newFakeDellDevice(logger)
+
+			dsu, err := utils.NewFakeDsu(bytes.NewReader(b))
+			require.NoError(t, err)
+
+			dell.dsu = dsu
+
+			// the flag set by an earlier install does not stage the update
+			dell.hw.PendingReboot = true
+
+			options := &model.UpdateOptions{Slug: common.SlugBIOS, Version: "2.9.4", Verify: true}
+
+			err = dell.verifyUpdate(context.TODO(), options, rebootRequiredExitCode(tc.exitCode))
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestInstallUpdatesDryRun(t *testing.T) {
+	testcases := []struct {
+		name           string
+		fixture        string
+		options        *model.UpdateOptions
+		wantCmds       []string
+		wantComponents int
+	}{
+		{
+			"update file",
+			"/dsu_inventory",
+			&model.UpdateOptions{Slug: common.SlugBIOS, UpdateFile: "BIOS_CR1K4_LN_2.9.4.BIN", ForceInstall: true, DryRun: true},
+			[]string{"BIOS_CR1K4_LN_2.9.4.BIN -q -f"},
+			1,
+		},
+		{
+			"install all",
+			"/dsu_preview",
+			&model.UpdateOptions{InstallAll: true, DryRun: true},
+			[]string{
+				"dsu --destination-type=CBD --destination-location=" + utils.LocalUpdatesDirectory,
+				"dsu --non-interactive --log-level=4 --source-type=REPOSITORY --source-location=" + utils.LocalUpdatesDirectory +
+					" --ic-location=" + utils.LocalUpdatesDirectory + "/invcol_*.BIN",
+			},
+			5,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := os.ReadFile(r6515fixtures + tc.fixture)
+			require.NoError(t, err)
+
+			logger, hook := test.NewNullLogger()
+			defer hook.Reset()
+
+			dell := newFakeDellDevice(logger)
+
+			dell.dsu, err = utils.NewFakeDsu(bytes.NewReader(b))
+			require.NoError(t, err)
+
+			err = dell.InstallUpdates(context.TODO(), tc.options)
+
+			dryRun := &errs.UpdateDryRunError{}
+			require.ErrorAs(t, err, &dryRun)
+
+			cmds := []string{}
+			for _, cmd := range dryRun.Commands {
+				cmds = append(cmds, cmd.Cmd)
+				assert.Len(t, cmd.Components, tc.wantComponents)
+			}
+
+			assert.Equal(t, tc.wantCmds, cmds)
+			assert.False(t, dell.hw.PendingReboot)
+			assert.False(t, dell.hw.UpdatesInstalled)
+		})
+	}
+}
@@ -4,11 +4,16 @@ import (
 	"context"
 	"os"
 	"strconv"
+	"strings"
 
-	"github.com/metal-toolbox/ironlib/model"
-	"github.com/metal-toolbox/ironlib/utils"
+	common "github.com/metal-toolbox/bmc-common"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+
+	"github.com/metal-toolbox/ironlib/actions"
+	"github.com/metal-toolbox/ironlib/errs"
+	"github.com/metal-toolbox/ironlib/model"
+	"github.com/metal-toolbox/ironlib/utils"
 )
 
 const (
@@ -63,15 +68,7 @@ func (d *dell) installUpdate(ctx context.Context, updateFile string, downgrade b
 		return 0, err
 	}
 
-	// non-interactive
-	args := []string{"-q"}
-
-	if downgrade {
-		args = append(args, "-f")
-	}
-
-	e := utils.NewExecutor(updateFile)
-	e.SetArgs(args...)
+	e := newDUPExecutor(updateFile, downgrade)
 
 	if d.logger.Level >= logrus.TraceLevel {
 		e.SetVerbose()
@@ -95,6 +92,66 @@ func (d *dell) installUpdate(ctx context.Context, updateFile string, downgrade b
 	return 0, nil
 }
 
+// newDUPExecutor returns the executor to install the dell update file (DUP) non-interactively
+func newDUPExecutor(updateFile string, downgrade bool) utils.Executor {
+	// non-interactive
+	args := []string{"-q"}
+
+	if downgrade {
+		args = append(args, "-f")
+	}
+
+	e := utils.NewExecutor(updateFile)
+	e.SetArgs(args...)
+
+	return e
+}
+
+// dryRunUpdates returns the update commands InstallUpdates executes for the options, along with the components they apply to.
+//
+// The DSU is queried for the components only when it is installed,
+// since the DSU prerequisites setup installs packages and configures repositories on the host.
+func (d *dell) dryRunUpdates(ctx context.Context, options *model.UpdateOptions) error {
+	dsuInstalled := d.DsuPrequisitesInstalled || d.dsu.Executor.CheckExecutable() == nil
+
+	if options.InstallAll {
+		components := []*common.Common{}
+
+		if dsuInstalled {
+			updates, exitCode, err := d.dsu.ComponentFirmwareUpdatePreview(ctx)
+			if err != nil && exitCode != utils.DSUExitCodeNoUpdatesAvailable {
+				return errors.Wrap(err, "error running dsu update preview")
+			}
+
+			for _, update := range updates {
+				components = append(components, dsuCommonComponent(update))
+			}
+		}
+
+		commands := []*model.UpdateCommand{}
+		for _, cmd := range d.dsu.UpdateCommands(utils.LocalUpdatesDirectory, options.DownloadOnly) {
+			commands = append(commands, &model.UpdateCommand{Cmd: cmd, Components: components})
+		}
+
+		return errs.NewUpdateDryRunError(commands...)
+	}
+
+	components := []*common.Common{}
+
+	if dsuInstalled {
+		inventory, err := d.dsu.Inventory(ctx)
+		if err != nil {
+			return errors.Wrap(err, "error collecting dsu inventory to resolve the update components")
+		}
+
+		components = dsuUpdateComponents(inventory, options)
+	}
+
+	cmd := newDUPExecutor(options.UpdateFile, options.ForceInstall).GetCmd()
+
+	return errs.NewUpdateDryRunError(&model.UpdateCommand{Cmd: cmd, Components: components})
+}
+
 // dsuListUpdates runs the dell-system-update utility to retrieve device inventory
 func (d *dell) dsuListUpdates(ctx context.Context) ([]*model.Component, error) {
 	err := d.pre(ctx)
@@ -121,6 +178,58 @@ func (d *dell) dsuInventory(ctx context.Context) ([]*model.Component, error) {
 	return d.dsu.Inventory(ctx)
 }
 
+// verifyUpdate collects the DSU inventory to verify the firmware version installed on the component the options apply to,
+// the DSU inventory components are matched by the options slug and name.
+//
+// pendingActivation indicates the install exited with a reboot required exit code - see rebootRequiredExitCode.
+func (d *dell) verifyUpdate(ctx context.Context, options *model.UpdateOptions, pendingActivation bool) error {
+	inventory, err := d.dsuInventory(ctx)
+	if err != nil {
+		return errors.Wrap(err, "error collecting dsu inventory to verify firmware update")
+	}
+
+	components := dsuUpdateComponents(inventory, options)
+
+	result := actions.VerifyFirmware(options, components, pendingActivation)
+
+	d.logger.WithFields(
+		logrus.Fields{"slug": result.Slug, "version": result.Version, "installed": result.InstalledVersion, "status": result.Status},
+	).Debug("firmware update verified")
+
+	return actions.UpdateResultError(result)
+}
+
+// dsuUpdateComponents returns the DSU inventory components the update options apply to,
+// the components are matched by the options slug and name.
+func dsuUpdateComponents(inventory []*model.Component, options *model.UpdateOptions) []*common.Common {
+	components := []*common.Common{}
+
+	for _, component := range inventory {
+		if !strings.EqualFold(component.Slug, options.Slug) {
+			continue
+		}
+
+		if options.Name != "" && !strings.Contains(strings.ToLower(component.Name), strings.ToLower(options.Name)) {
+			continue
+		}
+
+		components = append(components, dsuCommonComponent(component))
+	}
+
+	return components
+}
+
+// dsuCommonComponent returns the DSU listed component as a common.Common component
+func dsuCommonComponent(component *model.Component) *common.Common {
+	return &common.Common{
+		Vendor:      component.Vendor,
+		Model:       component.Model,
+		Serial:      component.Serial,
+		Description: component.Name,
+		Firmware:    &common.Firmware{Installed: component.FirmwareInstalled, Available: component.FirmwareAvailable},
+	}
+}
+
 // pre sets up prequisites for dealing with updates
 func (d *dell) pre(ctx context.Context) (err error) {
 	errPrefix := "dell dsu prereqs setup error: "
@@ -215,6 +324,11 @@ func (d *dell) startSrvHelper(ctx context.Context) error {
 	return nil
 }
 
+// rebootRequiredExitCode returns true when the DSU or update bin exit code indicates the update was applied pending a reboot
+func rebootRequiredExitCode(exitCode int) bool {
+	return exitCode == utils.DSUExitCodeRebootRequired || exitCode == BinUpdateExitCodeRebootRequired
+}
+
 // checkExitCode looks up the various DSU/update bin exitcodes
 // and returns an error if its an actual error
 func (d *dell) checkExitCode(exitCode int) error {
@@ -2,10 +2,16 @@ package generic
 
 import (
 	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/metal-toolbox/ironlib/errs"
+	"github.com/metal-toolbox/ironlib/model"
 )
 
-func (g *Generic) SetBIOSConfiguration(_ context.Context, _ map[string]string) error {
-	return nil
+// SetBIOSConfiguration is not supported on generic devices, errs.ErrBIOSConfigurationUnsupported is returned
+func (g *Generic) SetBIOSConfiguration(_ context.Context, _ map[string]string) (*model.BIOSConfigurationChanges, error) {
+	return nil, errors.Wrap(errs.ErrBIOSConfigurationUnsupported, "provider: generic")
 }
 
 func (g *Generic) GetBIOSConfiguration(_ context.Context) (map[string]string, error) {
@@ -3,12 +3,31 @@ package supermicro
 import (
 	"context"
 
+	common "github.com/metal-toolbox/bmc-common"
+	"github.com/metal-toolbox/ironlib/actions"
+	"github.com/metal-toolbox/ironlib/model"
 	"github.com/metal-toolbox/ironlib/utils"
 )
 
 // SetBIOSConfiguration sets bios configuration settings
-func (s *supermicro) SetBIOSConfiguration(context.Context, map[string]string) error {
-	return nil
+func (s *supermicro) SetBIOSConfiguration(ctx context.Context, cfg map[string]string) (*model.BIOSConfigurationChanges, error) {
+	sum := utils.NewSupermicroSUM(s.trace)
+
+	vendorCfg, err := utils.DenormalizeBIOSConfiguration(common.VendorSupermicro, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := actions.SetBIOSConfiguration(ctx, sum, "", vendorCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(changes.Pending) > 0 {
+		s.hw.PendingReboot = true
+	}
+
+	return changes, nil
 }
 
 // GetBIOSConfiguration returns bios configuration settings
@@ -2,6 +2,8 @@ package supermicro
 
 import (
 	"context"
+	"slices"
+	"strings"
 
 	common "github.com/metal-toolbox/bmc-common"
 	"github.com/pkg/errors"
@@ -14,6 +16,9 @@ import (
 	"github.com/metal-toolbox/ironlib/utils"
 )
 
+// boardFirmwareSlugs are the component slugs with the firmware applicable to the board model
+var boardFirmwareSlugs = []string{common.SlugBIOS, common.SlugBMC, common.SlugCPLD}
+
 type supermicro struct {
 	trace     bool
 	hw        *model.Hardware
@@ -85,6 +90,7 @@ func (s *supermicro) GetInventory(ctx context.Context, options ...actions.Option
 		DriveCapabilitiesCollectors: []actions.DriveCapabilityCollector{
 			utils.NewHdparmCmd(s.trace),
 			utils.NewNvmeCmd(s.trace),
+			utils.NewSg3UtilsCmd(s.trace),
 		},
 		StorageControllerCollectors: []actions.StorageControllerCollector{
 			utils.NewStoreCLICmd(s.trace),
@@ -130,12 +136,16 @@ func (s *supermicro) InstallUpdates(ctx context.Context, option *model.UpdateOpt
 		}
 	}
 
-	if option.Model == "" {
+	// the BIOS, BMC and CPLD firmware applies to the board model,
+	// the firmware of the other components applies to the component model.
+	if option.Model == "" && slices.ContainsFunc(boardFirmwareSlugs, func(slug string) bool { return strings.EqualFold(slug, option.Slug) }) {
 		option.Model = s.hw.Device.Model
 	}
 
+	// the firmware installed pending a reboot - BIOS, CPLD, storage controllers, is returned as utils.ErrRebootRequired,
+	// the update commands are returned as an *errs.UpdateDryRunError when the options DryRun is set
 	err = actions.UpdateComponent(ctx, s.hw.Device, option)
-	if err != nil {
+	if err != nil && !errors.Is(err, utils.ErrRebootRequired) {
 		return err
 	}
 
@@ -144,7 +154,7 @@ func (s *supermicro) InstallUpdates(ctx context.Context, option *model.UpdateOpt
 	s.hw.PendingReboot = true
 	s.hw.UpdatesInstalled = true
 
-	return nil
+	return err
 }
 
 // GetInventoryOEM collects device inventory using vendor specific tooling
@@ -1,3 +1,65 @@
 package supermicro
 
-// tests are covered in actions/inventory_test.go
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	common "github.com/metal-toolbox/bmc-common"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metal-toolbox/ironlib/actions"
+	"github.com/metal-toolbox/ironlib/errs"
+	"github.com/metal-toolbox/ironlib/model"
+)
+
+// inventory tests are covered in actions/inventory_test.go
+
+func newFakeSupermicro(t *testing.T) *supermicro {
+	t.Helper()
+
+	logger, _ := test.NewNullLogger()
+
+	device := common.NewDevice()
+	device.Model = "X11DPH-T"
+	device.Vendor = "Supermicro"
+	device.BIOS = &common.BIOS{Common: common.Common{Vendor: "Supermicro", Firmware: &common.Firmware{Installed: "3.4"}}}
+	device.NICs = []*common.NIC{
+		{Common: common.Common{Vendor: "Mellanox", Model: "MCX4121A-ACA_Ax", Firmware: &common.Firmware{Installed: "14.27.1016"}}},
+	}
+
+	return &supermicro{hw: model.NewHardware(&device), logger: logger}
+}
+
+func TestInstallUpdatesNIC(t *testing.T) {
+	updateFile := filepath.Join(t.TempDir(), "fw-ConnectX4Lx.bin")
+	require.NoError(t, os.WriteFile(updateFile, []byte("firmware"), 0o600))
+
+	s := newFakeSupermicro(t)
+
+	// the NIC firmware version installed is reported by the pre-flight checks before mlxup is run,
+	// the checks match the NIC by the options model.
+	option := &model.UpdateOptions{Slug: common.SlugNIC, Vendor: "mellanox", Version: "14.27.1016", UpdateFile: updateFile}
+
+	err := s.InstallUpdates(context.TODO(), option)
+	assert.ErrorAs(t, err, new(*errs.FirmwareAlreadyInstalledError))
+	assert.NotErrorIs(t, err, actions.ErrUpdateModelMismatch)
+
+	// the NIC model is not set to the board model
+	assert.Empty(t, option.Model)
+	assert.False(t, s.hw.UpdatesInstalled)
+}
+
+func TestInstallUpdatesBoardModel(t *testing.T) {
+	s := newFakeSupermicro(t)
+
+	// the update file is invalid to fail the pre-flight checks
+	option := &model.UpdateOptions{Slug: "bios", Vendor: "supermicro", Version: "3.5"}
+
+	err := s.InstallUpdates(context.TODO(), option)
+	assert.Error(t, err)
+	assert.Equal(t, "X11DPH-T", option.Model)
+}
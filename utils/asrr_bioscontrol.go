@@ -5,7 +5,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -38,14 +40,17 @@ const (
 	asrrKernelModule = "/opt/asrr/asrdev-%s.ko"
 
 	asrTmpBIOSConfigJSON = "/tmp/biosconfig-asrr.json"
+
+	asrTmpBIOSConfigSetJSON = "/tmp/biosconfig-asrr-set.json"
 )
 
 var ErrASRRBIOSKernelModule = errors.New("error loading asrr bios kernel module")
 
 // AsrrBiosControl is a asrr-bioscontrol executor
 type AsrrBioscontrol struct {
-	Executor    Executor
-	tmpJSONFile string
+	Executor       Executor
+	tmpJSONFile    string
+	tmpSetJSONFile string
 }
 
 // NewAsrrBioscontrol returns a new Asrr bios control utility executor
@@ -63,7 +68,7 @@ func NewAsrrBioscontrol(trace bool) *AsrrBioscontrol {
 		e.SetQuiet()
 	}
 
-	return &AsrrBioscontrol{Executor: e, tmpJSONFile: asrTmpBIOSConfigJSON}
+	return &AsrrBioscontrol{Executor: e, tmpJSONFile: asrTmpBIOSConfigJSON, tmpSetJSONFile: asrTmpBIOSConfigSetJSON}
 }
 
 // Attributes implements the actions.UtilAttributeGetter interface
@@ -165,6 +170,53 @@ func loadAsrrBiosKernelModule(ctx context.Context) error {
 func (a *AsrrBioscontrol) GetBIOSConfiguration(ctx context.Context, _ string) (map[string]string, error) {
 	var cfg map[string]string
 
+	bytesJSON, err := a.currentBIOSConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err = asrrBiosConfigurationJSON(ctx, bytesJSON)
+	if err != nil {
+		return nil, errors.Wrap(err, "error parsing bios config JSON")
+	}
+
+	return NormalizeBIOSConfiguration(cfg), nil
+}
+
+// SetBIOSConfiguration applies the given BIOS settings, the settings take effect on the next host reboot.
+func (a *AsrrBioscontrol) SetBIOSConfiguration(ctx context.Context, _ string, cfg map[string]string) error {
+	current, err := a.currentBIOSConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	b, err := asrrBiosConfigurationChange(current, cfg)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(a.tmpSetJSONFile, b, 0o600); err != nil {
+		return err
+	}
+
+	defer os.Remove(a.tmpSetJSONFile)
+
+	a.Executor.SetArgs("/s", a.tmpSetJSONFile)
+
+	result, err := a.Executor.Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	if result.ExitCode != 0 {
+		return newExecError(a.Executor.GetCmd(), result)
+	}
+
+	return nil
+}
+
+// currentBIOSConfig loads the kernel module and returns the BIOS configuration JSON exported by the utility
+func (a *AsrrBioscontrol) currentBIOSConfig(ctx context.Context) ([]byte, error) {
 	// load kernel module
 	err := loadAsrrBiosKernelModule(ctx)
 	if err != nil {
@@ -178,17 +230,101 @@ func (a *AsrrBioscontrol) GetBIOSConfiguration(ctx context.Context, _ string) (m
 		return nil, err
 	}
 
-	bytesJSON, err := os.ReadFile(a.tmpJSONFile)
-	if err != nil {
+	return os.ReadFile(a.tmpJSONFile)
+}
+
+// asrrBiosConfigurationChange sets the given settings in the BIOS configuration JSON and returns the updated JSON
+//
+// The settings are identified by their title as returned by asrrBiosConfigurationJSON,
+// the values are either the title of a valid value or the numeric value.
+func asrrBiosConfigurationChange(configBytes []byte, cfg map[string]string) ([]byte, error) {
+	params := []map[string]interface{}{}
+
+	decoder := json.NewDecoder(bytes.NewReader(configBytes))
+	// retain the numeric values as is
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&params); err != nil {
 		return nil, err
 	}
 
-	cfg, err = asrrBiosConfigurationJSON(ctx, bytesJSON)
-	if err != nil {
-		return nil, errors.Wrap(err, "error parsing bios config JSON")
+	seen := map[string]bool{}
+	notFound := maps.Clone(cfg)
+
+	for _, p := range params {
+		title, _ := p["Title"].(string)
+
+		key := asrrBiosParamTitle(title)
+		if seen[key] {
+			key = "[dup]" + key
+		}
+
+		seen[key] = true
+
+		value, exists := cfg[key]
+		if !exists {
+			continue
+		}
+
+		valueType, _ := p["Value Type"].(string)
+
+		v, err := asrrBiosConfigValue(value, valueType, p["Valid Value"])
+		if err != nil {
+			return nil, errors.Wrap(err, key)
+		}
+
+		p["Value"] = v
+
+		delete(notFound, key)
+	}
+
+	if len(notFound) > 0 {
+		return nil, errors.Wrap(ErrBIOSConfigKeyUnknown, strings.Join(slices.Sorted(maps.Keys(notFound)), ", "))
+	}
+
+	return json.MarshalIndent(params, "", "\t")
+}
+
+// asrrBiosConfigValue returns the numeric value for the given BIOS attribute value title,
+// this is the inverse of asrrBiosConfigValueTitle
+func asrrBiosConfigValue(value, valueType string, validValues interface{}) (uint64, error) {
+	values, _ := validValues.([]interface{})
+
+	for _, m := range values {
+		switch param := m.(type) {
+		// UINT8, UINT16 fields
+		case map[string]interface{}:
+			title, _ := param["Title"].(string)
+			if !biosValueMatches(title, value) && strings.TrimSpace(value) != fmt.Sprintf("%v", param["Value"]) {
+				continue
+			}
+
+			if n, ok := param["Value"].(json.Number); ok {
+				return strconv.ParseUint(n.String(), 10, 64)
+			}
+		// BOOLEAN fields
+		case json.Number:
+			if valueType != "BOOLEAN" {
+				continue
+			}
+
+			switch normalizeValue(strings.TrimSpace(value)) {
+			case disabledValue, "0":
+				return 0, nil
+			case enabledValue, "1":
+				return 1, nil
+			}
+		}
+	}
+
+	// parameters without a list of valid values accept a numeric value
+	if len(values) == 0 {
+		if v, err := strconv.ParseUint(strings.TrimSpace(value), 10, 64); err == nil {
+			return v, nil
+		}
 	}
 
-	return normalizeBIOSConfiguration(cfg), nil
+	return 0, errors.Wrap(ErrBIOSConfigValueInvalid, value)
 }
 
 type asrrBiosParam struct {
@@ -210,10 +346,7 @@ func asrrBiosConfigurationJSON(_ context.Context, configBytes []byte) (map[strin
 	}
 
 	for _, p := range params {
-		// trim garbage characters - most likely terminal colors for the parameter titles
-		key := strings.Replace(p.Title, "\x1b{a1#\x1b{f4#\x1b{w1125#", "", -1)
-		key = strings.Replace(key, "\x1b{a1#", "", -1)
-		key = strings.Trim(key, " ")
+		key := asrrBiosParamTitle(p.Title)
 
 		base10 := 10
 		value := strconv.FormatUint(p.Value, base10)
@@ -235,6 +368,15 @@ func asrrBiosConfigurationJSON(_ context.Context, configBytes []byte) (map[strin
 	return cfg, nil
 }
 
+// asrrBiosParamTitle returns the BIOS parameter title trimmed of garbage characters
+func asrrBiosParamTitle(title string) string {
+	// trim garbage characters - most likely terminal colors for the parameter titles
+	key := strings.Replace(title, "\x1b{a1#\x1b{f4#\x1b{w1125#", "", -1)
+	key = strings.Replace(key, "\x1b{a1#", "", -1)
+
+	return strings.Trim(key, " ")
+}
+
 // asrrBiosConfigValueTitle returns the Title name of the BIOS attribute value
 func asrrBiosConfigValueTitle(value, valueType string, validValues interface{}) string {
 	values, ok := validValues.([]interface{})
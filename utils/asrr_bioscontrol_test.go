@@ -140,3 +140,55 @@ func Test_asrrBiosConfigValueTitle(t *testing.T) {
 		assert.Equal(t, tt.expected, got, tt.testName)
 	}
 }
+
+func Test_asrrBiosConfigurationChange(t *testing.T) {
+	b, err := os.ReadFile("../fixtures/asrr/e3c246d4i-nl/bios.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name        string
+		cfg         map[string]string
+		expected    map[string]string
+		expectedErr error
+	}{
+		{
+			"values set",
+			map[string]string{"Erase Event Log": "Yes, Every reset", "MachineCheck": "Disabled", "Sata Port 0": "enable"},
+			map[string]string{"Erase Event Log": "Yes, Every reset", "MachineCheck": "Disabled", "Sata Port 0": "Enabled"},
+			nil,
+		},
+		{
+			"unknown setting",
+			map[string]string{"MachineCheck": "Disabled", "foo": "bar"},
+			nil,
+			ErrBIOSConfigKeyUnknown,
+		},
+		{
+			"invalid value",
+			map[string]string{"Erase Event Log": "Sometimes"},
+			nil,
+			ErrBIOSConfigValueInvalid,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := asrrBiosConfigurationChange(b, tc.cfg)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+
+			cfg, err := asrrBiosConfigurationJSON(context.TODO(), got)
+			assert.NoError(t, err)
+
+			for k, v := range tc.expected {
+				assert.Equal(t, v, cfg[k], k)
+			}
+		})
+	}
+}
@@ -46,13 +46,13 @@ func (b *Blkdiscard) Discard(ctx context.Context, drive *common.Drive) error {
 		return err
 	}
 
-	return verify()
+	return verifyWipe(ctx, wipeVerificationWatermarks, verify)
 }
 
 // WipeDrive implements DriveWipe by calling Discard
 func (b *Blkdiscard) WipeDrive(ctx context.Context, logger *logrus.Logger, drive *common.Drive) error {
 	logger.WithField("drive", drive.LogicalName).WithField("method", "blkdiscard").Debug("wiping")
-	return b.Discard(ctx, drive)
+	return attemptWipeMethod(ctx, "blkdiscard", func() error { return b.Discard(ctx, drive) })
 }
 
 // NewFakeBlkdiscard returns a mock implementation of the Blkdiscard interface for use in tests.
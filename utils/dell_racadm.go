@@ -2,8 +2,11 @@ package utils
 
 import (
 	"context"
+	"encoding/json"
 	"io"
+	"maps"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/beevik/etree"
@@ -15,6 +18,9 @@ import (
 const (
 	DellRacadmPath = "/opt/dell/srvadmin/bin/idracadm7"
 	EnvVarRacadm7  = "IRONLIB_UTIL_RACADM7"
+
+	// the FQDD of the BIOS component in the server configuration profile
+	racadmBIOSFQDD = "BIOS.Setup.1-1"
 )
 
 var (
@@ -25,10 +31,11 @@ var (
 
 // DellRacadm is a dell racadm executor
 type DellRacadm struct {
-	Executor       Executor
-	ConfigJSON     string
-	BIOSCfgTmpFile string // where we dump the BIOS config to before processing it
-	KeepConfigFile bool   // flag to keep the BIOS config file generated (mainly for testing)
+	Executor          Executor
+	ConfigJSON        string
+	BIOSCfgTmpFile    string // where we dump the BIOS config to before processing it
+	BIOSCfgImportFile string // where we write the BIOS config to be imported
+	KeepConfigFile    bool   // flag to keep the BIOS config file generated (mainly for testing)
 }
 
 // Return a new Dell racadm command executor
@@ -45,7 +52,7 @@ func NewDellRacadm(trace bool) *DellRacadm {
 		e.SetQuiet()
 	}
 
-	return &DellRacadm{Executor: e, BIOSCfgTmpFile: "/tmp/bioscfg"}
+	return &DellRacadm{Executor: e, BIOSCfgTmpFile: "/tmp/bioscfg", BIOSCfgImportFile: "/tmp/bioscfg-import"}
 }
 
 // Attributes implements the actions.UtilAttributeGetter interface
@@ -82,7 +89,7 @@ func (s *DellRacadm) GetBIOSConfiguration(ctx context.Context, deviceModel strin
 		return nil, ErrDellBiosCfgNil
 	}
 
-	return normalizeBIOSConfiguration(cfg), nil
+	return NormalizeBIOSConfiguration(cfg), nil
 }
 
 // racadmBIOSConfigXML executes racadm to retrieve BIOS config as XML and returns a map[string]string object
@@ -105,7 +112,7 @@ func (s *DellRacadm) racadmBIOSConfigXML(ctx context.Context) (map[string]string
 		defer os.Remove(s.BIOSCfgTmpFile)
 	}
 
-	return findXMLAttributes(s.BIOSCfgTmpFile, "//Component[@FQDD='BIOS.Setup.1-1']//Attribute")
+	return findXMLAttributes(s.BIOSCfgTmpFile, "//Component[@FQDD='"+racadmBIOSFQDD+"']//Attribute")
 }
 
 // findXMLAttributes runs the xml query and returns a map of BIOS attributes to values
@@ -164,7 +171,7 @@ func (s *DellRacadm) racadmBIOSConfigJSON(ctx context.Context) (map[string]strin
 
 	attrs := map[string]string{}
 
-	attrJSON := gjson.Get(s.ConfigJSON, `SystemConfiguration.Components.#(FQDD=="BIOS.Setup.1-1").Attributes`)
+	attrJSON := gjson.Get(s.ConfigJSON, `SystemConfiguration.Components.#(FQDD=="`+racadmBIOSFQDD+`").Attributes`)
 	attrJSON.ForEach(func(_, value gjson.Result) bool {
 		n := value.Get("Name").String()
 		v := value.Get("Value").String()
@@ -181,6 +188,98 @@ func (s *DellRacadm) racadmBIOSConfigJSON(ctx context.Context) (map[string]strin
 	return attrs, nil
 }
 
+// SetBIOSConfiguration imports the given BIOS settings as a server configuration profile,
+// racadm creates a configuration job which is applied by the iDRAC on the next host reboot.
+func (s *DellRacadm) SetBIOSConfiguration(ctx context.Context, deviceModel string, cfg map[string]string) error {
+	if s.BIOSCfgImportFile == "" {
+		return ErrDellBiosCfgFileUndefined
+	}
+
+	var b []byte
+
+	var err error
+
+	format := "json"
+
+	// older hardware expects the BIOS config as XML
+	if strings.EqualFold(deviceModel, "c6320") {
+		format = "xml"
+		b, err = racadmBIOSConfigImportXML(cfg)
+	} else {
+		b, err = racadmBIOSConfigImportJSON(cfg)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(s.BIOSCfgImportFile, b, 0o600); err != nil {
+		return err
+	}
+
+	if !s.KeepConfigFile {
+		defer os.Remove(s.BIOSCfgImportFile)
+	}
+
+	// the host reboot is left to the caller
+	s.Executor.SetArgs("set", "-t", format, "-f", s.BIOSCfgImportFile, "-b", "NoReboot")
+
+	result, err := s.Executor.Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	if result.ExitCode != 0 {
+		return newExecError(s.Executor.GetCmd(), result)
+	}
+
+	return nil
+}
+
+type racadmAttribute struct {
+	Name  string `json:"Name"`
+	Value string `json:"Value"`
+}
+
+// racadmBIOSConfigImportJSON returns the server configuration profile JSON to import the given BIOS settings
+func racadmBIOSConfigImportJSON(cfg map[string]string) ([]byte, error) {
+	attributes := make([]*racadmAttribute, 0, len(cfg))
+	for _, k := range slices.Sorted(maps.Keys(cfg)) {
+		attributes = append(attributes, &racadmAttribute{Name: k, Value: cfg[k]})
+	}
+
+	profile := map[string]interface{}{
+		"SystemConfiguration": map[string]interface{}{
+			"Components": []map[string]interface{}{
+				{
+					"FQDD":       racadmBIOSFQDD,
+					"Attributes": attributes,
+				},
+			},
+		},
+	}
+
+	return json.MarshalIndent(profile, "", "  ")
+}
+
+// racadmBIOSConfigImportXML returns the server configuration profile XML to import the given BIOS settings
+func racadmBIOSConfigImportXML(cfg map[string]string) ([]byte, error) {
+	doc := etree.NewDocument()
+
+	component := doc.CreateElement("SystemConfiguration").CreateElement("Component")
+	component.CreateAttr("FQDD", racadmBIOSFQDD)
+
+	for _, k := range slices.Sorted(maps.Keys(cfg)) {
+		attribute := component.CreateElement("Attribute")
+		attribute.CreateAttr("Name", k)
+		attribute.SetText(cfg[k])
+	}
+
+	doc.Indent(1)
+
+	return doc.WriteToBytes()
+}
+
 // FakeRacadmExecute implements the utils.Executor interface for testing
 type FakeRacadmExecute struct {
 	Cmd    string
@@ -203,7 +302,7 @@ func NewFakeRacadmExecutor(cmd string) Executor {
 func NewFakeRacadm(biosCfgFile string) *DellRacadm {
 	executor := NewFakeRacadmExecutor("racadm")
 
-	return &DellRacadm{Executor: executor, BIOSCfgTmpFile: biosCfgFile, KeepConfigFile: true}
+	return &DellRacadm{Executor: executor, BIOSCfgTmpFile: biosCfgFile, BIOSCfgImportFile: biosCfgFile + "-import", KeepConfigFile: true}
 }
 
 // Exec implements the utils.Executor interface
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"context"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -313,3 +314,67 @@ func Test_RacadmBIOSConfigXML(t *testing.T) {
 
 	assert.Equal(t, expected, c)
 }
+
+func Test_RacadmSetBIOSConfiguration(t *testing.T) {
+	testCases := []struct {
+		name        string
+		deviceModel string
+		format      string
+		expected    string
+	}{
+		{
+			"json import",
+			"r6515",
+			"json",
+			`{
+  "SystemConfiguration": {
+    "Components": [
+      {
+        "Attributes": [
+          {
+            "Name": "LogicalProc",
+            "Value": "Disabled"
+          },
+          {
+            "Name": "SriovGlobalEnable",
+            "Value": "Enabled"
+          }
+        ],
+        "FQDD": "BIOS.Setup.1-1"
+      }
+    ]
+  }
+}`,
+		},
+		{
+			"xml import",
+			"c6320",
+			"xml",
+			`<SystemConfiguration>
+ <Component FQDD="BIOS.Setup.1-1">
+  <Attribute Name="LogicalProc">Disabled</Attribute>
+  <Attribute Name="SriovGlobalEnable">Enabled</Attribute>
+ </Component>
+</SystemConfiguration>
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			racadm := NewFakeDellRacadm()
+			racadm.BIOSCfgImportFile = t.TempDir() + "/bioscfg-import"
+
+			cfg := map[string]string{"SriovGlobalEnable": "Enabled", "LogicalProc": "Disabled"}
+
+			err := racadm.SetBIOSConfiguration(context.TODO(), tc.deviceModel, cfg)
+			assert.NoError(t, err)
+
+			assert.Equal(t, "racadm set -t "+tc.format+" -f "+racadm.BIOSCfgImportFile+" -b NoReboot", racadm.Executor.GetCmd())
+
+			b, err := os.ReadFile(racadm.BIOSCfgImportFile)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, string(b))
+		})
+	}
+}
@@ -3,6 +3,7 @@ package utils
 import (
 	"cmp"
 	"context"
+	"fmt"
 	"os"
 	"os/exec"
 	"strings"
@@ -18,15 +19,24 @@ const EnvDmidecodeUtility = "IRONLIB_UTIL_DMIDECODE"
 
 type Dmidecode struct {
 	dmi *dmidecode.DMI
+	// smbios is the SMBIOS entry point when the data was read from the SMBIOS tables instead of the dmidecode utility
+	smbios *SMBIOSEntryPoint
 }
 
+// NewDmidecode returns a Dmidecode instance loaded from the SMBIOS tables exposed in sysfs,
+// falling back to the dmidecode utility when the tables can't be read.
 func NewDmidecode() (d *Dmidecode, err error) {
+	d, smbiosErr := NewSMBIOSDmidecode()
+	if smbiosErr == nil {
+		return d, nil
+	}
+
 	utility := cmp.Or(os.Getenv(EnvDmidecodeUtility), "dmidecode")
 
 	dmi := dmidecode.New()
 	output, err := dmi.ExecDmidecode(utility)
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrap(err, "reading SMBIOS tables: "+smbiosErr.Error())
 	}
 
 	err = dmi.ParseDmidecode(output)
@@ -39,6 +49,10 @@ func NewDmidecode() (d *Dmidecode, err error) {
 
 // Attributes implements the actions.UtilAttributeGetter interface
 func (d *Dmidecode) Attributes() (utilName model.CollectorUtility, absolutePath string, err error) {
+	if d != nil && d.smbios != nil {
+		return "dmidecode", SysfsDMITable, nil
+	}
+
 	utility := cmp.Or(os.Getenv(EnvDmidecodeUtility), "dmidecode")
 	path, err := exec.LookPath(utility)
 
@@ -130,6 +144,31 @@ func (d *Dmidecode) BIOSVersion() (string, error) {
 	return d.query("BIOS Information", "Version")
 }
 
+// OEMStrings returns the OEM strings (SMBIOS type 11) in order
+func (d *Dmidecode) OEMStrings() ([]string, error) {
+	// OEM strings type ID
+	oemStringsTypeID := 11
+
+	records, err := d.queryType(oemStringsTypeID)
+	if err != nil {
+		return nil, err
+	}
+
+	oemStrings := []string{}
+	for _, r := range records {
+		for i := 1; ; i++ {
+			v, exists := r[fmt.Sprintf("String %d", i)]
+			if !exists {
+				break
+			}
+
+			oemStrings = append(oemStrings, v)
+		}
+	}
+
+	return oemStrings, nil
+}
+
 func (d *Dmidecode) TPMs(context.Context) ([]*common.TPM, error) {
 	// TPM type ID
 	tpmTypeID := 43
@@ -182,6 +182,24 @@ func (d *Dsu) ApplyLocalUpdates(ctx context.Context, updateDir string) (int, err
 	return result.ExitCode, err
 }
 
+// UpdateCommands returns the command lines FetchUpdateFiles and ApplyLocalUpdates execute to install the available updates,
+// the ApplyLocalUpdates command is not included when downloadOnly is set.
+//
+// The inventory collector bin location is identified once the update files are fetched,
+// the glob it is identified by is included in the ApplyLocalUpdates command line.
+func (d *Dsu) UpdateCommands(updateDir string, downloadOnly bool) []string {
+	d.Executor.SetArgs("--destination-type=CBD", "--destination-location="+updateDir)
+	commands := []string{d.Executor.GetCmd()}
+
+	if downloadOnly {
+		return commands
+	}
+
+	d.Executor.SetArgs("--non-interactive", "--log-level=4", "--source-type=REPOSITORY", "--source-location="+updateDir, "--ic-location="+updateDir+"/invcol_*.BIN")
+
+	return append(commands, d.Executor.GetCmd())
+}
+
 // Inventory collects inventory with the dell-system-update utility and
 // updates device component firmware based on data listed by the dell system update tool
 func (d *Dsu) Inventory(ctx context.Context) ([]*model.Component, error) {
@@ -11,6 +11,10 @@ var (
 	ErrFakeExecutorInvalidArgs  = errors.New("invalid number of args passed to fake executor")
 	ErrRepositoryBaseURL        = errors.New("repository base URL undefined, ensure UpdateOptions.BaseURL OR UPDATE_BASE_URL env var is set")
 	ErrRebootRequired           = errors.New("reboot required")
+	ErrBIOSConfigKeyUnknown     = errors.New("BIOS setting not found in the current BIOS configuration")
+	ErrBIOSConfigValueInvalid   = errors.New("value is not valid for the BIOS setting")
+	ErrBIOSConfigKeyNotMapped   = errors.New("normalized BIOS setting has no mapping for the vendor")
+	ErrBIOSConfigKeyDuplicate   = errors.New("BIOS setting specified more than once")
 )
 
 // ExecError is returned when the command exits with an error or a non zero exit status
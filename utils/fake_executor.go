@@ -58,7 +58,7 @@ func (e *FakeExecute) Exec(_ context.Context) (*Result, error) {
 		case "attach-ns":
 			e.Stdout = []byte("attach-ns: Success, nsid:1\n")
 		case "id-ns":
-			e.Stdout = []byte(`{"lbafs":[{"ds":9},{"ds":12}]}`)
+			e.Stdout = []byte(`{"dlfeat":9,"lbafs":[{"ds":9},{"ds":12}]}`)
 		case "reset", "ns-rescan":
 		case "id-ctrl":
 			cwd, _ := os.Getwd()
@@ -78,35 +78,43 @@ func (e *FakeExecute) Exec(_ context.Context) (*Result, error) {
 
 			e.Stdout = b
 		case "format", "sanitize":
-			dev := e.Args[len(e.Args)-1]
-			f, err := os.OpenFile(dev, os.O_WRONLY, 0)
-			if err != nil {
-				return nil, err
-			}
-			size, err := f.Seek(0, io.SeekEnd)
-			if err != nil {
+			if err := fakeWipeDevice(e.Args[len(e.Args)-1]); err != nil {
 				return nil, err
 			}
-			err = f.Truncate(0)
-			if err != nil {
-				return nil, err
+		case "smart-log":
+			if os.Getenv("FAIL_NVME_SMART_LOG") != "" {
+				return &Result{Stderr: []byte("smart log: Invalid Field in Command"), ExitCode: 1}, fmt.Errorf("exit status 1")
 			}
-			err = f.Sync()
-			if err != nil {
-				return nil, err
+
+			cwd, _ := os.Getwd()
+			f := "../fixtures/utils/nvme/nvmecli-smart-log"
+
+			if strings.Contains(cwd, "providers") {
+				f = "../../fixtures/utils/nvme/nvmecli-smart-log"
 			}
-			err = f.Truncate(size)
+
+			b, err := os.ReadFile(f)
 			if err != nil {
 				return nil, err
 			}
-			err = f.Sync()
-			if err != nil {
-				return nil, err
+
+			e.Stdout = b
+		case "self-test-log":
+			cwd, _ := os.Getwd()
+			f := "../fixtures/utils/nvme/nvmecli-self-test-log"
+
+			if strings.Contains(cwd, "providers") {
+				f = "../../fixtures/utils/nvme/nvmecli-self-test-log"
 			}
-			err = f.Close()
+
+			b, err := os.ReadFile(f)
 			if err != nil {
 				return nil, err
 			}
+
+			e.Stdout = b
+		case "device-self-test":
+			e.Stdout = []byte("Device self-test started\n")
 		case "sanitize-log":
 			dev := e.Args[len(e.Args)-1]
 			dev = path.Base(dev)
@@ -151,6 +159,26 @@ func (e *FakeExecute) Exec(_ context.Context) (*Result, error) {
 
 			e.Stdout = b
 		}
+	case "sg_inq", "sg_opcodes":
+		cwd, _ := os.Getwd()
+		f := "../fixtures/utils/sg3_utils/" + e.Cmd
+
+		if strings.Contains(cwd, "providers") {
+			f = "../../fixtures/utils/sg3_utils/" + e.Cmd
+		}
+
+		b, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+
+		e.Stdout = b
+	case "sg_sanitize", "sg_format":
+		if err := fakeWipeDevice(e.Args[len(e.Args)-1]); err != nil {
+			return nil, err
+		}
+	case "sg_requests":
+		e.Stdout = []byte("Decode parameter data as sense data:\n Fixed format, current; Sense key: No Sense\n")
 	case "dsu":
 	case "rpm":
 		if e.Args[1] == "-1" && e.Args[2] == "dell-system-update" {
@@ -260,3 +288,34 @@ var nvmeListDummyJSON = []byte(`{
 		]
 	  }
 	`)
+
+// fakeWipeDevice zeroes the file standing in for the device as a wipe would
+func fakeWipeDevice(dev string) error {
+	f, err := os.OpenFile(dev, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+
+	size, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+
+	if err := f.Truncate(0); err != nil {
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		return err
+	}
+
+	if err := f.Truncate(size); err != nil {
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		return err
+	}
+
+	return f.Close()
+}
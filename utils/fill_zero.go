@@ -13,6 +13,10 @@ import (
 
 type FillZero struct {
 	Quiet bool
+	// JournalPath when set, persists the wipe progress to the file so an interrupted wipe can be resumed.
+	JournalPath string
+	// Readback when set, reads back the drive after the wipe to verify it reads back zeros.
+	Readback *WipeReadback
 }
 
 // Return a new zerowipe executor
@@ -24,15 +28,18 @@ func NewFillZeroCmd(trace bool) *FillZero {
 	return &z
 }
 
+// WipeDrive writes zeros to the drive and verifies the watermarks applied before the wipe were overwritten.
+//
+// When a JournalPath is set, the last synced offset and watermarks are persisted to the journal,
+// a subsequent wipe of the drive with the same serial resumes from the journaled offset.
 func (z *FillZero) WipeDrive(ctx context.Context, logger *logrus.Logger, drive *common.Drive) error {
+	return attemptWipeMethod(ctx, "zero-fill", func() error { return z.wipe(ctx, logger, drive) })
+}
+
+func (z *FillZero) wipe(ctx context.Context, logger *logrus.Logger, drive *common.Drive) error {
 	log := logger.WithField("drive", drive.LogicalName).WithField("method", "zero-fill")
 	log.Debug("wiping")
 
-	verify, err := ApplyWatermarks(drive)
-	if err != nil {
-		return err
-	}
-
 	// Write open
 	file, err := os.OpenFile(drive.LogicalName, os.O_WRONLY, 0)
 	if err != nil {
@@ -47,20 +54,31 @@ func (z *FillZero) WipeDrive(ctx context.Context, logger *logrus.Logger, drive *
 	}
 
 	log.WithField("size", fmt.Sprintf("%dB", partitionSize)).Debug("disk info detected")
-	_, err = file.Seek(0, io.SeekStart)
+
+	journal, err := z.prepareWipe(log, drive, partitionSize)
+	if err != nil {
+		return err
+	}
+
+	verify := watermarksChecker(drive, journal.watermarks())
+
+	_, err = file.Seek(journal.Offset, io.SeekStart)
 	if err != nil {
 		return err
 	}
 
 	var bytesSinceLastPrint int64
-	var totalBytesWritten int64
+	totalBytesWritten := journal.Offset
 	buffer := make([]byte, 4096)
 	start := time.Now()
-	for bytesRemaining := partitionSize; bytesRemaining > 0; {
+	for bytesRemaining := partitionSize - journal.Offset; bytesRemaining > 0; {
 		// Check if the context has been canceled
 		select {
 		case <-ctx.Done():
 			log.Debug("stopping")
+			if err := z.checkpoint(file, journal, totalBytesWritten); err != nil {
+				log.WithError(err).Warn("error writing wipe journal")
+			}
 			return ctx.Err()
 		default:
 			l := min(int64(len(buffer)), bytesRemaining)
@@ -77,6 +95,10 @@ func (z *FillZero) WipeDrive(ctx context.Context, logger *logrus.Logger, drive *
 				printProgress(log, totalBytesWritten, partitionSize, start, bytesSinceLastPrint)
 				start = time.Now()
 				bytesSinceLastPrint = 0
+
+				if err := z.checkpoint(file, journal, totalBytesWritten); err != nil {
+					return err
+				}
 			}
 		}
 	}
@@ -85,7 +107,71 @@ func (z *FillZero) WipeDrive(ctx context.Context, logger *logrus.Logger, drive *
 		return err
 	}
 
-	return verify()
+	// the drive has been written through, a rerun should start over
+	if z.JournalPath != "" {
+		if err := os.Remove(z.JournalPath); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	if err := verifyWipe(ctx, wipeVerificationWatermarks, verify); err != nil {
+		return err
+	}
+
+	if z.Readback != nil {
+		return readbackVerify(ctx, log.WithField("pass", "verify"), drive.LogicalName, repeatFiller([]byte{0x00}), z.Readback)
+	}
+
+	return nil
+}
+
+// prepareWipe returns the journal to resume the wipe from, when no journal exists
+// watermarks are applied to the drive and a new journal is returned.
+func (z *FillZero) prepareWipe(log *logrus.Entry, drive *common.Drive, size int64) (*wipeJournal, error) {
+	if z.JournalPath != "" {
+		if drive.Serial == "" {
+			return nil, ErrWipeJournalDriveSerial
+		}
+
+		journal, err := readWipeJournal(z.JournalPath, drive, size)
+		if err != nil {
+			return nil, err
+		}
+
+		if journal != nil {
+			log.WithField("offset", journal.Offset).Info("resuming wipe from journal")
+			return journal, nil
+		}
+	}
+
+	watermarks, err := applyWatermarks(drive)
+	if err != nil {
+		return nil, err
+	}
+
+	journal := newWipeJournal(drive, size, watermarks)
+	if z.JournalPath != "" {
+		if err := journal.write(z.JournalPath); err != nil {
+			return nil, err
+		}
+	}
+
+	return journal, nil
+}
+
+// checkpoint syncs the data written to the drive and records the offset in the journal
+func (z *FillZero) checkpoint(file *os.File, journal *wipeJournal, offset int64) error {
+	if z.JournalPath == "" {
+		return nil
+	}
+
+	if err := file.Sync(); err != nil {
+		return err
+	}
+
+	journal.Offset = offset
+
+	return journal.write(z.JournalPath)
 }
 
 func printProgress(log *logrus.Entry, totalBytesWritten, partitionSize int64, start time.Time, bytesSinceLastPrint int64) {
@@ -107,3 +193,14 @@ func printProgress(log *logrus.Entry, totalBytesWritten, partitionSize int64, st
 func (z *FillZero) SetQuiet() {
 	z.Quiet = true
 }
+
+// SetReadback enables reading back the given percentage of the drive after the wipe,
+// 0 or 100 reads back the whole drive.
+func (z *FillZero) SetReadback(samplePercent int) {
+	z.Readback = &WipeReadback{SamplePercent: samplePercent}
+}
+
+// SetJournalPath sets the file to persist the wipe progress to
+func (z *FillZero) SetJournalPath(path string) {
+	z.JournalPath = path
+}
@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	common "github.com/metal-toolbox/bmc-common"
+	"github.com/pkg/errors"
+)
+
+var ErrWipeJournalDriveSerial = errors.New("drive serial required to journal wipe progress")
+
+// wipeJournal is persisted to resume a wipe that was interrupted,
+// the journal is only used to resume wiping the drive with the same serial.
+type wipeJournal struct {
+	Serial      string             `json:"serial"`
+	LogicalName string             `json:"logical_name"`
+	Size        int64              `json:"size"`
+	Offset      int64              `json:"offset"`
+	Watermarks  []journalWatermark `json:"watermarks"`
+}
+
+type journalWatermark struct {
+	Position int64  `json:"position"`
+	Data     []byte `json:"data"`
+}
+
+// newWipeJournal returns a wipe journal for the drive and the watermarks applied
+func newWipeJournal(drive *common.Drive, size int64, watermarks []watermark) *wipeJournal {
+	j := &wipeJournal{
+		Serial:      drive.Serial,
+		LogicalName: drive.LogicalName,
+		Size:        size,
+		Watermarks:  make([]journalWatermark, 0, len(watermarks)),
+	}
+
+	for _, w := range watermarks {
+		j.Watermarks = append(j.Watermarks, journalWatermark{Position: w.position, Data: w.data})
+	}
+
+	return j
+}
+
+// readWipeJournal returns the wipe journal at the given path,
+// a nil journal is returned if the journal does not exist or is for a different drive.
+func readWipeJournal(path string, drive *common.Drive, size int64) (*wipeJournal, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	j := &wipeJournal{}
+	if err := json.Unmarshal(b, j); err != nil {
+		return nil, errors.Wrap(err, "error parsing wipe journal "+path)
+	}
+
+	if j.Serial != drive.Serial || j.Size != size || j.Offset > size || len(j.Watermarks) == 0 {
+		return nil, nil
+	}
+
+	return j, nil
+}
+
+// write persists the journal, the journal is written to a temporary file and renamed
+// so an interrupted write does not leave behind a partial journal.
+func (j *wipeJournal) write(path string) error {
+	b, err := json.Marshal(j)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// watermarks returns the watermarks recorded in the journal
+func (j *wipeJournal) watermarks() []watermark {
+	watermarks := make([]watermark, 0, len(j.Watermarks))
+	for _, w := range j.Watermarks {
+		watermarks = append(watermarks, watermark{position: w.Position, data: w.Data})
+	}
+
+	return watermarks
+}
@@ -75,3 +75,107 @@ func Test_FillZeroWipeDrive(t *testing.T) {
 		})
 	}
 }
+
+func Test_FillZeroWipeDriveJournal(t *testing.T) {
+	const size = 1024 * 1024
+
+	// setup returns a drive filled with random data and an interrupted wipe journal for it
+	setup := func(t *testing.T) (*FillZero, *common.Drive) {
+		t.Helper()
+
+		drive := createTestDrive(t)
+		drive.Serial = "S1"
+
+		data := make([]byte, size)
+		_, err := rand.Read(data)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(drive.LogicalName, data, 0o600))
+
+		zw := &FillZero{JournalPath: t.TempDir() + "/wipe.journal"}
+		logger, _ := test.NewNullLogger()
+
+		// a canceled context stops the wipe before any data is written
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err = zw.WipeDrive(ctx, logger, drive)
+		require.ErrorIs(t, err, context.Canceled)
+
+		journal, err := readWipeJournal(zw.JournalPath, drive, size)
+		require.NoError(t, err)
+		require.NotNil(t, journal)
+		require.Equal(t, int64(0), journal.Offset)
+		require.Len(t, journal.Watermarks, 10)
+
+		return zw, drive
+	}
+
+	// interrupt records the wipe as interrupted half way through
+	interrupt := func(t *testing.T, zw *FillZero, drive *common.Drive) {
+		t.Helper()
+
+		journal, err := readWipeJournal(zw.JournalPath, drive, size)
+		require.NoError(t, err)
+
+		journal.Offset = size / 2
+		require.NoError(t, journal.write(zw.JournalPath))
+	}
+
+	t.Run("resumed wipe", func(t *testing.T) {
+		zw, drive := setup(t)
+		interrupt(t, zw, drive)
+
+		// the first half was wiped before the interruption
+		f, err := os.OpenFile(drive.LogicalName, os.O_WRONLY, 0)
+		require.NoError(t, err)
+		_, err = f.Write(make([]byte, size/2))
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		logger, _ := test.NewNullLogger()
+		require.NoError(t, zw.WipeDrive(context.Background(), logger, drive))
+
+		b, err := os.ReadFile(drive.LogicalName)
+		require.NoError(t, err)
+		require.Equal(t, make([]byte, size), b)
+
+		_, err = os.Stat(zw.JournalPath)
+		require.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("resumed wipe verifies original watermarks", func(t *testing.T) {
+		zw, drive := setup(t)
+		interrupt(t, zw, drive)
+
+		// the first half is not wiped, the watermarks in it remain
+		logger, _ := test.NewNullLogger()
+		require.ErrorIs(t, zw.WipeDrive(context.Background(), logger, drive), ErrIneffectiveWipe)
+
+		b, err := os.ReadFile(drive.LogicalName)
+		require.NoError(t, err)
+		require.Equal(t, make([]byte, size/2), b[size/2:])
+		require.NotEqual(t, make([]byte, size/2), b[:size/2])
+	})
+
+	t.Run("journal for another drive is ignored", func(t *testing.T) {
+		zw, drive := setup(t)
+		interrupt(t, zw, drive)
+
+		drive.Serial = "S2"
+
+		logger, _ := test.NewNullLogger()
+		require.NoError(t, zw.WipeDrive(context.Background(), logger, drive))
+
+		b, err := os.ReadFile(drive.LogicalName)
+		require.NoError(t, err)
+		require.Equal(t, make([]byte, size), b)
+	})
+
+	t.Run("drive serial required", func(t *testing.T) {
+		drive := createTestDrive(t)
+		zw := &FillZero{JournalPath: t.TempDir() + "/wipe.journal"}
+
+		logger, _ := test.NewNullLogger()
+		require.ErrorIs(t, zw.WipeDrive(context.Background(), logger, drive), ErrWipeJournalDriveSerial)
+	})
+}
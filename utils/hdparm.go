@@ -241,7 +241,7 @@ func (h *Hdparm) WipeDrive(ctx context.Context, logger *logrus.Logger, drive *co
 		// nolint:govet
 		l := l.WithField("method", "sanitize").WithField("action", "sanitize-crypto-scramble")
 		l.Debug("wiping")
-		err := h.Sanitize(ctx, drive, CryptoErase)
+		err := attemptWipeMethod(ctx, "sanitize-crypto-scramble", func() error { return h.Sanitize(ctx, drive, CryptoErase) })
 		if err == nil {
 			return nil
 		}
@@ -251,7 +251,7 @@ func (h *Hdparm) WipeDrive(ctx context.Context, logger *logrus.Logger, drive *co
 		// nolint:govet
 		l := l.WithField("method", "sanitize").WithField("action", "sanitize-block-erase")
 		l.Debug("wiping")
-		err := h.Sanitize(ctx, drive, BlockErase)
+		err := attemptWipeMethod(ctx, "sanitize-block-erase", func() error { return h.Sanitize(ctx, drive, BlockErase) })
 		if err == nil {
 			return nil
 		}
@@ -261,7 +261,7 @@ func (h *Hdparm) WipeDrive(ctx context.Context, logger *logrus.Logger, drive *co
 		// nolint:govet
 		l := l.WithField("method", "security-erase-enhanced")
 		l.Debug("wiping")
-		err := h.Erase(ctx, drive, CryptographicErase)
+		err := attemptWipeMethod(ctx, "security-erase-enhanced", func() error { return h.Erase(ctx, drive, CryptographicErase) })
 		if err == nil {
 			return nil
 		}
@@ -306,7 +306,7 @@ func (h *Hdparm) Sanitize(ctx context.Context, drive *common.Drive, sanact Sanit
 		time.Sleep(100 * time.Millisecond)
 	}
 
-	return verify()
+	return verifyWipe(ctx, wipeVerificationWatermarks, verify)
 }
 
 func (h *Hdparm) sanitizeDone(output []byte) bool {
@@ -348,7 +348,7 @@ func (h *Hdparm) Erase(ctx context.Context, drive *common.Drive, ses SecureErase
 		logrus.WithField("drive", drive.LogicalName).Debug("hdparm --security-disable succeeded - this is unexpected")
 	}
 
-	return verify()
+	return verifyWipe(ctx, wipeVerificationWatermarks, verify)
 }
 
 // NewFakeHdparm returns a mock hdparm collector that returns mock data for use in tests.
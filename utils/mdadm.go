@@ -0,0 +1,393 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	common "github.com/metal-toolbox/bmc-common"
+	"github.com/pkg/errors"
+
+	"github.com/metal-toolbox/ironlib/model"
+)
+
+const (
+	EnvMdadmUtility = "IRONLIB_UTIL_MDADM"
+
+	// MdadmVendor and MdadmModel identify the Linux software RAID storage controller,
+	// the virtual disks of the pseudo controller are the md arrays.
+	MdadmVendor = "linux"
+	MdadmModel  = "mdadm"
+
+	mdstatPath = "/proc/mdstat"
+)
+
+var (
+	ErrMdadmNoDevices = errors.New("no member devices set for the md array")
+
+	// mdadmRaidLevels are the md RAID levels arrays can be created with
+	mdadmRaidLevels = []string{"0", "1", "4", "5", "6", "10"}
+
+	// md0 : active raid1 sdb[1] sda[0]
+	mdstatArrayLine = regexp.MustCompile(`^md(\d+)\s*:\s*(\S+)\s*(?:\(\S+\)\s*)?(\S+)?`)
+
+	//       1046528 blocks super 1.2 [2/1] [U_]
+	mdstatBlocksLine = regexp.MustCompile(`^\s+(\d+) blocks.*?(?:\[(\d+)/(\d+)\])?(?:\s+\[[U_]+\])?$`)
+)
+
+// Mdadm wraps mdadm to manage Linux software RAID arrays,
+// the md arrays are listed from /proc/mdstat and their details obtained through mdadm --detail --export.
+type Mdadm struct {
+	Executor Executor
+	// MdstatPath is the path to the md status file - /proc/mdstat.
+	MdstatPath string
+	// Devices are the logical names of the drives the md array is created with.
+	Devices []string
+}
+
+// mdstatArray is an md array listed in /proc/mdstat
+type mdstatArray struct {
+	id        int
+	state     string
+	level     string
+	sizeBytes int64
+	degraded  bool
+}
+
+// Return a new mdadm executor
+func NewMdadmCmd(trace bool) *Mdadm {
+	e := NewExecutor(cmp.Or(os.Getenv(EnvMdadmUtility), "mdadm"))
+	e.SetEnv([]string{"LC_ALL=C.UTF-8"})
+
+	if !trace {
+		e.SetQuiet()
+	}
+
+	return &Mdadm{Executor: e, MdstatPath: mdstatPath}
+}
+
+// Attributes implements the actions.UtilAttributeGetter interface
+func (m *Mdadm) Attributes() (utilName model.CollectorUtility, absolutePath string, err error) {
+	// Call CheckExecutable first so that the Executable CmdPath is resolved.
+	er := m.Executor.CheckExecutable()
+
+	return "mdadm", m.Executor.CmdPath(), er
+}
+
+// SetDevices sets the logical names of the drives - /dev/sdX, the md array is created with
+func (m *Mdadm) SetDevices(devices ...string) {
+	m.Devices = devices
+}
+
+// ListVirtualDisks returns the md arrays as common.VirtualDisk objects,
+// the virtual disk ID is the md device number - 0 for /dev/md0.
+//
+// This method implements the actions.VirtualDiskManager interface.
+func (m *Mdadm) ListVirtualDisks(ctx context.Context) ([]*common.VirtualDisk, error) {
+	arrays, err := m.mdstat()
+	if err != nil {
+		return nil, err
+	}
+
+	virtualDisks := []*common.VirtualDisk{}
+
+	for _, array := range arrays {
+		details, err := m.detail(ctx, array.id)
+		if err != nil {
+			return nil, err
+		}
+
+		virtualDisks = append(virtualDisks, array.virtualDisk(details))
+	}
+
+	return virtualDisks, nil
+}
+
+// virtualDisk returns the md array as a common.VirtualDisk including the member drives listed in the mdadm details
+func (a *mdstatArray) virtualDisk(details map[string]string) *common.VirtualDisk {
+	vd := &common.VirtualDisk{
+		ID:             strconv.Itoa(a.id),
+		RaidType:       cmp.Or(details["MD_LEVEL"], a.level),
+		SizeBytes:      a.sizeBytes,
+		Status:         a.state,
+		PhysicalDrives: []*common.Drive{},
+	}
+
+	if a.degraded {
+		vd.Status = "degraded"
+	}
+
+	// the array name is prefixed with the homehost - host:name
+	if name := details["MD_NAME"]; name != "" {
+		_, vd.Name, _ = strings.Cut(name, ":")
+		vd.Name = cmp.Or(vd.Name, name)
+	}
+
+	for _, member := range mdadmMembers(details) {
+		vd.PhysicalDrives = append(vd.PhysicalDrives, &common.Drive{Common: common.Common{LogicalName: member}})
+	}
+
+	return vd
+}
+
+// mdadmMembers returns the logical names of the member drives listed in the md array details
+//
+// MD_DEVICE_dev_sda_ROLE=0
+// MD_DEVICE_dev_sda_DEV=/dev/sda
+func mdadmMembers(details map[string]string) []string {
+	members := []string{}
+
+	for key, value := range details {
+		if strings.HasPrefix(key, "MD_DEVICE_") && strings.HasSuffix(key, "_DEV") {
+			members = append(members, value)
+		}
+	}
+
+	slices.Sort(members)
+
+	return members
+}
+
+// CreateVirtualDisk creates an md array with the devices set through SetDevices,
+// the array is created on the first unused md device number.
+//
+// The raidMode is the RAID level - 0, 1, 4, 5, 6, 10, the blockSize is the chunk size in KB,
+// the mdadm default chunk size is used when 0. The physicalDisks IDs are not applicable to md arrays
+// since the drives are not attached to a controller, the drives are identified by their logical names instead.
+//
+// This method implements the actions.VirtualDiskManager interface.
+func (m *Mdadm) CreateVirtualDisk(ctx context.Context, raidMode string, _ []uint, name string, blockSize uint) error {
+	if len(m.Devices) == 0 {
+		return ErrMdadmNoDevices
+	}
+
+	level := strings.TrimPrefix(strings.ToLower(raidMode), "raid")
+	if !slices.Contains(mdadmRaidLevels, level) {
+		return InvalidRaidModeError(raidMode)
+	}
+
+	// the chunk size is a power of 2, of at least 4KB
+	if blockSize != 0 && (blockSize < 4 || blockSize&(blockSize-1) != 0) {
+		return InvalidBlockSizeError(blockSize)
+	}
+
+	arrays, err := m.mdstat()
+	if err != nil {
+		return err
+	}
+
+	id := 0
+	for slices.ContainsFunc(arrays, func(a *mdstatArray) bool { return a.id == id }) {
+		id++
+	}
+
+	// mdadm --create /dev/md0 --run --metadata=1.2 --level=1 --raid-devices=2 --name=os --chunk=64 /dev/sda /dev/sdb
+	args := []string{
+		"--create", fmt.Sprintf("/dev/md%d", id), "--run", "--metadata=1.2",
+		"--level=" + level, "--raid-devices=" + strconv.Itoa(len(m.Devices)),
+	}
+
+	if name != "" {
+		args = append(args, "--name="+name)
+	}
+
+	// the chunk size is not applicable to mirrors
+	if blockSize != 0 && level != "1" {
+		args = append(args, "--chunk="+strconv.FormatUint(uint64(blockSize), 10))
+	}
+
+	m.Executor.SetArgs(append(args, m.Devices...)...)
+
+	if _, err := m.Executor.Exec(ctx); err != nil {
+		return errors.Wrap(ErrCreateVirtualDisk, err.Error())
+	}
+
+	return nil
+}
+
+// DestroyVirtualDisk stops the md array and clears the md superblock from its member drives,
+// the virtualDiskID is the md device number - 0 for /dev/md0.
+//
+// This method implements the actions.VirtualDiskManager interface.
+func (m *Mdadm) DestroyVirtualDisk(ctx context.Context, virtualDiskID int) error {
+	if virtualDiskID < 0 {
+		return InvalidVirtualDiskIDError(virtualDiskID)
+	}
+
+	details, err := m.detail(ctx, virtualDiskID)
+	if err != nil {
+		return errors.Wrap(ErrDestroyVirtualDisk, err.Error())
+	}
+
+	members := mdadmMembers(details)
+
+	// mdadm --stop /dev/md0
+	m.Executor.SetArgs("--stop", fmt.Sprintf("/dev/md%d", virtualDiskID))
+
+	if _, err := m.Executor.Exec(ctx); err != nil {
+		return errors.Wrap(ErrDestroyVirtualDisk, err.Error())
+	}
+
+	if len(members) == 0 {
+		return nil
+	}
+
+	// mdadm --zero-superblock /dev/sda /dev/sdb
+	m.Executor.SetArgs(append([]string{"--zero-superblock"}, members...)...)
+
+	if _, err := m.Executor.Exec(ctx); err != nil {
+		return errors.Wrap(ErrDestroyVirtualDisk, err.Error())
+	}
+
+	return nil
+}
+
+// detail runs mdadm --detail --export and returns the md array details as key values
+func (m *Mdadm) detail(ctx context.Context, id int) (map[string]string, error) {
+	// mdadm --detail --export /dev/md0
+	m.Executor.SetArgs("--detail", "--export", fmt.Sprintf("/dev/md%d", id))
+
+	result, err := m.Executor.Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	details := map[string]string{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(result.Stdout))
+	for scanner.Scan() {
+		key, value, found := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if found {
+			details[key] = strings.Trim(value, `'"`)
+		}
+	}
+
+	return details, nil
+}
+
+// mdstat returns the md arrays listed in /proc/mdstat,
+// no arrays are returned when the md driver is not loaded.
+func (m *Mdadm) mdstat() ([]*mdstatArray, error) {
+	b, err := os.ReadFile(m.MdstatPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return parseMdstat(b), nil
+}
+
+// parseMdstat returns the md arrays listed in the /proc/mdstat contents
+//
+// Personalities : [raid1]
+// md0 : active raid1 sdb[1] sda[0]
+//
+//	1046528 blocks super 1.2 [2/2] [UU]
+func parseMdstat(b []byte) []*mdstatArray {
+	arrays := []*mdstatArray{}
+
+	var array *mdstatArray
+
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if match := mdstatArrayLine.FindStringSubmatch(line); match != nil {
+			id, _ := strconv.Atoi(match[1])
+			array = &mdstatArray{id: id, state: match[2]}
+
+			// inactive arrays do not list the raid level
+			if strings.HasPrefix(match[3], "raid") || match[3] == "linear" {
+				array.level = match[3]
+			}
+
+			arrays = append(arrays, array)
+
+			continue
+		}
+
+		if array == nil || array.sizeBytes != 0 {
+			continue
+		}
+
+		if match := mdstatBlocksLine.FindStringSubmatch(line); match != nil {
+			// the size is listed in 1K blocks
+			blocks, _ := strconv.ParseInt(match[1], 10, 64)
+			array.sizeBytes = blocks * 1024
+			array.degraded = match[2] != "" && match[2] != match[3]
+		}
+	}
+
+	return arrays
+}
+
+// FakeMdadmExecute implements the utils.Executor interface for testing,
+// the mdadm --detail output is read from the detail-mdN files in DataDir.
+type FakeMdadmExecute struct {
+	Cmd     string
+	Args    []string
+	Stdout  []byte
+	DataDir string
+	// Commands records the args of each command executed
+	Commands []string
+	// Executor embedded in here to skip having to implement all the utils.Executor methods
+	Executor
+}
+
+// NewFakeMdadm returns a fake mdadm object for testing, the md arrays are listed from dataDir/mdstat
+func NewFakeMdadm(dataDir string) (*Mdadm, *FakeMdadmExecute) {
+	e := &FakeMdadmExecute{Cmd: "mdadm", DataDir: dataDir}
+
+	return &Mdadm{Executor: e, MdstatPath: dataDir + "/mdstat"}, e
+}
+
+// Exec implements the utils.Executor interface
+func (e *FakeMdadmExecute) Exec(context.Context) (*Result, error) {
+	if len(e.Args) == 0 {
+		return nil, ErrFakeExecutorInvalidArgs
+	}
+
+	e.Commands = append(e.Commands, strings.Join(e.Args, " "))
+	e.Stdout = nil
+
+	if e.Args[0] == "--detail" {
+		// --detail --export /dev/md0
+		b, err := os.ReadFile(e.DataDir + "/detail-" + path.Base(e.Args[len(e.Args)-1]))
+		if err != nil {
+			return nil, err
+		}
+
+		e.Stdout = b
+	}
+
+	return &Result{Stdout: e.Stdout}, nil
+}
+
+// CheckExecutable implements the Executor interface
+func (e *FakeMdadmExecute) CheckExecutable() error {
+	return nil
+}
+
+// SetArgs is to set cmd args to the fake execute method
+func (e *FakeMdadmExecute) SetArgs(a ...string) {
+	e.Args = a
+}
+
+func (e *FakeMdadmExecute) CmdPath() string {
+	return e.Cmd
+}
+
+func (e *FakeMdadmExecute) GetCmd() string {
+	return strings.Join(append([]string{e.Cmd}, e.Args...), " ")
+}
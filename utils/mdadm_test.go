@@ -0,0 +1,144 @@
+package utils
+
+import (
+	"context"
+	"testing"
+
+	common "github.com/metal-toolbox/bmc-common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mdadmFixturesDir = "../fixtures/utils/mdadm"
+
+func Test_MdadmListVirtualDisks(t *testing.T) {
+	mdadm, e := NewFakeMdadm(mdadmFixturesDir)
+
+	virtualDisks, err := mdadm.ListVirtualDisks(context.TODO())
+	require.NoError(t, err)
+
+	expected := []*common.VirtualDisk{
+		{
+			ID:        "1",
+			Name:      "1",
+			RaidType:  "raid0",
+			SizeBytes: 2093056 * 1024,
+			Status:    "active",
+			PhysicalDrives: []*common.Drive{
+				{Common: common.Common{LogicalName: "/dev/loop1"}},
+				{Common: common.Common{LogicalName: "/dev/loop2"}},
+			},
+		},
+		{
+			ID:        "0",
+			Name:      "os",
+			RaidType:  "raid1",
+			SizeBytes: 468719616 * 1024,
+			Status:    "degraded",
+			PhysicalDrives: []*common.Drive{
+				{Common: common.Common{LogicalName: "/dev/sda"}},
+				{Common: common.Common{LogicalName: "/dev/sdb"}},
+			},
+		},
+	}
+
+	assert.Equal(t, expected, virtualDisks)
+	assert.Equal(t, []string{"--detail --export /dev/md1", "--detail --export /dev/md0"}, e.Commands)
+}
+
+func Test_MdadmListVirtualDisksNoArrays(t *testing.T) {
+	mdadm, e := NewFakeMdadm(mdadmFixturesDir)
+
+	// the md driver is not loaded
+	mdadm.MdstatPath = mdadmFixturesDir + "/does-not-exist"
+
+	virtualDisks, err := mdadm.ListVirtualDisks(context.TODO())
+	require.NoError(t, err)
+	assert.Empty(t, virtualDisks)
+
+	mdadm.MdstatPath = mdadmFixturesDir + "/mdstat-empty"
+
+	virtualDisks, err = mdadm.ListVirtualDisks(context.TODO())
+	require.NoError(t, err)
+	assert.Empty(t, virtualDisks)
+	assert.Empty(t, e.Commands)
+}
+
+func Test_MdadmCreateVirtualDisk(t *testing.T) {
+	testcases := []struct {
+		name      string
+		devices   []string
+		raidMode  string
+		blockSize uint
+		wantCmd   string
+		wantErr   error
+	}{
+		{
+			name:     "raid1 on the first unused md device",
+			devices:  []string{"/dev/loop3", "/dev/loop4"},
+			raidMode: "RAID1",
+			// the chunk size is not applicable to mirrors
+			blockSize: 64,
+			wantCmd:   "--create /dev/md2 --run --metadata=1.2 --level=1 --raid-devices=2 --name=data /dev/loop3 /dev/loop4",
+		},
+		{
+			name:      "raid10 with chunk size",
+			devices:   []string{"/dev/loop3", "/dev/loop4", "/dev/loop5", "/dev/loop6"},
+			raidMode:  "10",
+			blockSize: 128,
+			wantCmd:   "--create /dev/md2 --run --metadata=1.2 --level=10 --raid-devices=4 --name=data --chunk=128 /dev/loop3 /dev/loop4 /dev/loop5 /dev/loop6",
+		},
+		{
+			name:     "no devices",
+			raidMode: "1",
+			wantErr:  ErrMdadmNoDevices,
+		},
+		{
+			name:     "invalid raid mode",
+			devices:  []string{"/dev/loop3", "/dev/loop4"},
+			raidMode: "50",
+			wantErr:  ErrInvalidRaidMode,
+		},
+		{
+			name:      "invalid chunk size",
+			devices:   []string{"/dev/loop3", "/dev/loop4"},
+			raidMode:  "0",
+			blockSize: 48,
+			wantErr:   ErrInvalidBlockSize,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			mdadm, e := NewFakeMdadm(mdadmFixturesDir)
+			mdadm.SetDevices(tc.devices...)
+
+			err := mdadm.CreateVirtualDisk(context.TODO(), tc.raidMode, nil, "data", tc.blockSize)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, []string{tc.wantCmd}, e.Commands)
+		})
+	}
+}
+
+func Test_MdadmDestroyVirtualDisk(t *testing.T) {
+	mdadm, e := NewFakeMdadm(mdadmFixturesDir)
+
+	err := mdadm.DestroyVirtualDisk(context.TODO(), -1)
+	assert.ErrorIs(t, err, ErrInvalidVirtualDiskID)
+
+	err = mdadm.DestroyVirtualDisk(context.TODO(), 1)
+	require.NoError(t, err)
+
+	expected := []string{
+		"--detail --export /dev/md1",
+		"--stop /dev/md1",
+		"--zero-superblock /dev/loop1 /dev/loop2",
+	}
+
+	assert.Equal(t, expected, e.Commands)
+}
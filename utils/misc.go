@@ -1,11 +1,15 @@
 package utils
 
 import (
+	"fmt"
 	"io"
 	"os"
+	"strconv"
+	"strings"
 
 	common "github.com/metal-toolbox/bmc-common"
 	"github.com/metal-toolbox/ironlib/errs"
+	"github.com/metal-toolbox/ironlib/model"
 	"github.com/pkg/errors"
 )
 
@@ -36,6 +40,44 @@ func copyFile(src, dst string) error {
 	return os.Chmod(dst, 0o600)
 }
 
+// updateDryRunError returns the errs.UpdateDryRunError for the executor command line set,
+// with the components the update command applies to when known.
+func updateDryRunError(e Executor, components ...*common.Common) error {
+	return errs.NewUpdateDryRunError(&model.UpdateCommand{Cmd: e.GetCmd(), Components: components})
+}
+
+// rebootRequiredError returns the ErrRebootRequired for the firmware installed on the component
+// and activated on the next host reboot, the update is verified as staged pending the reboot.
+func rebootRequiredError(component string) error {
+	return errors.Wrap(ErrRebootRequired, component+" firmware installed, activated on the next host reboot")
+}
+
+// pciBusDeviceFunction returns the PCI bus, device and function of the PCI address in the bus:device.function notation,
+// addresses are accepted as listed by lshw - pci@0000:3d:00.0, and by the LSI/Broadcom utilities - 00:3d:00:00.
+// An empty string is returned when the address is not identified.
+func pciBusDeviceFunction(address string) string {
+	parts := strings.Split(strings.ReplaceAll(strings.TrimPrefix(address, "pci@"), ".", ":"), ":")
+
+	// [domain, bus, device, function]
+	const pciAddressParts = 4
+	if len(parts) != pciAddressParts {
+		return ""
+	}
+
+	ids := make([]uint64, 0, len(parts)-1)
+
+	for _, part := range parts[1:] {
+		id, err := strconv.ParseUint(part, 16, 8)
+		if err != nil {
+			return ""
+		}
+
+		ids = append(ids, id)
+	}
+
+	return fmt.Sprintf("%02x:%02x.%x", ids[0], ids[1], ids[2])
+}
+
 // IdentifyVendorModel returns the device vendor, model, serial number attributes
 func IdentifyVendorModel(dmidecode *Dmidecode) (*DeviceIdentifiers, error) {
 	device := &DeviceIdentifiers{}
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	common "github.com/metal-toolbox/bmc-common"
+	"github.com/metal-toolbox/ironlib/errs"
 	"github.com/metal-toolbox/ironlib/model"
 	"github.com/pkg/errors"
 )
@@ -20,20 +21,23 @@ const (
 // Mlxup is a mlxup command executor object
 type Mlxup struct {
 	Executor Executor
+	// DryRun is set to return the update commands with errs.UpdateDryRunError instead of executing them.
+	DryRun bool
 }
 
 // MlxupDevice is a mellanox device object
 type MlxupDevice struct {
-	PartNumber    string
-	DeviceType    string
-	Description   string
-	PCIDeviceName string
-	PSID          string
-	BaseMAC       string
-	Firmware      []string // [version_current, version_available]
-	FirmwarePXE   []string
-	FirmwareUEFI  []string
-	Status        string
+	PartNumber      string
+	DeviceType      string
+	Description     string
+	PCIDeviceName   string
+	PSID            string
+	BaseMAC         string
+	Firmware        []string // [version_current, version_available]
+	FirmwareRunning string   // The firmware version running when it differs from the version current, pending a reset
+	FirmwarePXE     []string
+	FirmwareUEFI    []string
+	Status          string
 }
 
 // Return a new mellanox mlxup command executor
@@ -128,6 +132,11 @@ func setNICFirmware(d *MlxupDevice, firmware *common.Firmware) {
 		}
 	}
 
+	// the firmware installed is pending a reset
+	if d.FirmwareRunning != "" {
+		firmware.Metadata["firmware_running"] = d.FirmwareRunning
+	}
+
 	// [vInstalled, vAvailable]
 	if len(d.FirmwarePXE) > 0 {
 		firmware.Metadata["firmware_pxe_installed"] = d.FirmwarePXE[0]
@@ -149,6 +158,11 @@ func setNICFirmware(d *MlxupDevice, firmware *common.Firmware) {
 	}
 }
 
+// SetDryRun sets the updater to return the update commands instead of executing them
+func (m *Mlxup) SetDryRun(dryRun bool) {
+	m.DryRun = dryRun
+}
+
 // UpdateRequirements implements the actions/NICUpdater interface to return any pre/post firmware install requirements.
 func (m *Mlxup) UpdateRequirements(_ string) *model.UpdateRequirements {
 	return &model.UpdateRequirements{PostInstallHostPowercycle: true}
@@ -162,6 +176,9 @@ func (m *Mlxup) UpdateNIC(ctx context.Context, updateFile, modelNumber string, f
 		return err
 	}
 
+	// the update commands when DryRun is set
+	commands := []*model.UpdateCommand{}
+
 	// apply update
 	for _, nic := range nics {
 		if modelNumber != "" {
@@ -177,6 +194,22 @@ func (m *Mlxup) UpdateNIC(ctx context.Context, updateFile, modelNumber string, f
 		}
 
 		m.Executor.SetArgs(args...)
+
+		if m.DryRun {
+			component := &common.Common{
+				Model:    nic.PartNumber,
+				Vendor:   common.VendorFromString(nic.DeviceType),
+				Serial:   nic.BaseMAC,
+				Firmware: common.NewFirmwareObj(),
+			}
+
+			setNICFirmware(nic, component.Firmware)
+
+			commands = append(commands, &model.UpdateCommand{Cmd: m.Executor.GetCmd(), Components: []*common.Common{component}})
+
+			continue
+		}
+
 		result, err := m.Executor.Exec(ctx)
 		if err != nil {
 			if result != nil && result.ExitCode != 0 {
@@ -190,6 +223,10 @@ func (m *Mlxup) UpdateNIC(ctx context.Context, updateFile, modelNumber string, f
 		}
 	}
 
+	if m.DryRun {
+		return errs.NewUpdateDryRunError(commands...)
+	}
+
 	return nil
 }
 
@@ -267,6 +304,7 @@ func parseMlxDeviceAttributes(bSlice [][]byte) *MlxupDevice {
 		case "Versions":
 			versions := parseMlxVersions(bSlice[sidx:])
 			device.Firmware = versions["FW"]
+			device.FirmwareRunning = firmwareRunning(versions)
 			device.FirmwarePXE = versions["PXE"]
 			device.FirmwareUEFI = versions["UEFI"]
 
@@ -284,9 +322,10 @@ func parseMlxDeviceAttributes(bSlice [][]byte) *MlxupDevice {
 // version["FW"][1] indicates fw version available
 func parseMlxVersions(bSlice [][]byte) map[string][]string {
 	versions := map[string][]string{
-		"FW":   make([]string, 0),
-		"PXE":  make([]string, 0),
-		"UEFI": make([]string, 0),
+		"FW":         make([]string, 0),
+		"FW_RUNNING": make([]string, 0),
+		"PXE":        make([]string, 0),
+		"UEFI":       make([]string, 0),
 	}
 
 	for _, s := range bSlice {
@@ -308,6 +347,14 @@ func parseMlxVersions(bSlice [][]byte) map[string][]string {
 
 			// version installed, available
 			versions["FW"] = append(versions["FW"], fields[1:]...)
+		// line indicating the firmware running, "FW (Running)   14.27.1016     N/A"
+		case bytes.Contains(s, []byte(" FW (Running) ")):
+			fields := strings.Fields(string(s))
+			if len(fields) < 3 {
+				continue
+			}
+
+			versions["FW_RUNNING"] = append(versions["FW_RUNNING"], fields[2])
 		case bytes.Contains(s, []byte(" PXE ")):
 			fields := strings.Fields(string(s))
 			if len(fields) == 0 {
@@ -336,6 +383,20 @@ func parseMlxVersions(bSlice [][]byte) map[string][]string {
 	return versions
 }
 
+// firmwareRunning returns the firmware version running when it differs from the firmware version installed,
+// this indicates the NIC was updated and the firmware installed is activated on a reset.
+func firmwareRunning(versions map[string][]string) string {
+	if len(versions["FW"]) == 0 || len(versions["FW_RUNNING"]) == 0 {
+		return ""
+	}
+
+	if versions["FW_RUNNING"][0] == versions["FW"][0] {
+		return ""
+	}
+
+	return versions["FW_RUNNING"][0]
+}
+
 // formatBaseMacAddress accepts a mac address string in the form "ac1f6bdc19c2"
 // returns it in the delimited format "ac:1f:6b:dc:19:c2"
 //
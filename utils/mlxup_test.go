@@ -6,7 +6,9 @@ import (
 	"testing"
 
 	common "github.com/metal-toolbox/bmc-common"
+	"github.com/metal-toolbox/ironlib/errs"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func newFakeMlxup() *Mlxup {
@@ -117,8 +119,57 @@ func Test_MlxupParseQueryOutput(t *testing.T) {
 	assert.Equal(t, expected, mlxDevices)
 }
 
+func Test_MlxupParseQueryOutputPendingReset(t *testing.T) {
+	cli := newFakeMlxup()
+
+	b, err := os.ReadFile("../fixtures/utils/mlxup/query-pending")
+	if err != nil {
+		t.Error(err)
+	}
+
+	cli.Executor.SetStdout(b)
+
+	nics, err := cli.NICs(context.TODO())
+	if err != nil {
+		t.Error(err)
+	}
+
+	assert.Len(t, nics, 2)
+
+	// the firmware installed on the first NIC is activated on a reset
+	assert.Equal(t, "14.28.2006", nics[0].Firmware.Installed)
+	assert.Equal(t, "14.27.1016", nics[0].Firmware.Metadata["firmware_running"])
+
+	assert.Equal(t, "14.28.2006", nics[1].Firmware.Installed)
+	assert.NotContains(t, nics[1].Firmware.Metadata, "firmware_running")
+}
+
 func Test_FormatHWAddress(t *testing.T) {
 	assert.Equal(t, "b8:59:9f:de:86:fd", formatBaseMacAddress("b8599fde86fd"))
 	assert.Equal(t, "b8:59:9f:de:86:fd", formatBaseMacAddress("b8:59:9f:de:86:fd"))
 	assert.Equal(t, "", formatBaseMacAddress("foo"))
 }
+
+func Test_MlxupUpdateNICDryRun(t *testing.T) {
+	b, err := os.ReadFile("../fixtures/utils/mlxup/query")
+	require.NoError(t, err)
+
+	m := newFakeMlxup()
+	m.Executor.SetStdout(b)
+	m.SetDryRun(true)
+
+	err = m.UpdateNIC(context.TODO(), "/tmp/fw-ConnectX4Lx.bin", "MCX4121A-ACA_Ax", true)
+
+	dryRun := &errs.UpdateDryRunError{}
+	require.ErrorAs(t, err, &dryRun)
+	require.Len(t, dryRun.Commands, 2)
+
+	assert.Equal(t, "mlxup --yes --dev 0000:d8:00.0 --image-file /tmp/fw-ConnectX4Lx.bin --force", dryRun.Commands[0].Cmd)
+	assert.Equal(t, "mlxup --yes --dev 0000:d8:00.1 --image-file /tmp/fw-ConnectX4Lx.bin --force", dryRun.Commands[1].Cmd)
+
+	nic := dryRun.Commands[1].Components[0]
+	assert.Equal(t, "b8:59:9f:de:86:f8", nic.Serial)
+	assert.Equal(t, "MCX4121A-ACA_Ax", nic.Model)
+	assert.Equal(t, "14.27.1016", nic.Firmware.Installed)
+	assert.Equal(t, "14.28.2006", nic.Firmware.Available)
+}
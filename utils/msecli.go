@@ -21,6 +21,8 @@ const (
 // Msecli is an msecli executor
 type Msecli struct {
 	Executor Executor
+	// DryRun is set to return the update commands with errs.UpdateDryRunError instead of executing them.
+	DryRun bool
 }
 
 // MseclieDevice is a Micron disk device object
@@ -57,6 +59,12 @@ func (m *Msecli) Attributes() (utilName model.CollectorUtility, absolutePath str
 	return "msecli", m.Executor.CmdPath(), er
 }
 
+// SetDryRun sets the updater to return the update commands instead of executing them,
+// the update file is not renamed when set.
+func (m *Msecli) SetDryRun(dryRun bool) {
+	m.DryRun = dryRun
+}
+
 // Drives returns a slice of drive components identified
 func (m *Msecli) Drives(ctx context.Context) ([]*common.Drive, error) {
 	devices, err := m.Query(ctx)
@@ -98,7 +106,7 @@ func (m *Msecli) UpdateDrive(ctx context.Context, updateFile, modelNumber, seria
 	expectedFileName := "1.bin"
 
 	// rename update file
-	if filepath.Base(updateFile) != expectedFileName {
+	if filepath.Base(updateFile) != expectedFileName && !m.DryRun {
 		newName := filepath.Join(filepath.Dir(updateFile), expectedFileName)
 
 		err := os.Rename(updateFile, newName)
@@ -124,16 +132,16 @@ func (m *Msecli) UpdateDrive(ctx context.Context, updateFile, modelNumber, seria
 			}
 		}
 
-		return m.updateDrive(ctx, d.ModelNumber, updateFile)
+		return m.updateDrive(ctx, d, updateFile)
 	}
 
 	return ErrMseCliDriveNotIdentified
 }
 
 // updateDrive installs the given updatefile
-func (m *Msecli) updateDrive(ctx context.Context, modelNumber, updateFile string) error {
+func (m *Msecli) updateDrive(ctx context.Context, drive *MsecliDevice, updateFile string) error {
 	// get the product name from the model number - msecli expects the product name
-	modelNForMsecli, err := mseCLIModelType(modelNumber)
+	modelNForMsecli, err := mseCLIModelType(drive.ModelNumber)
 	if err != nil {
 		return err
 	}
@@ -148,6 +156,15 @@ func (m *Msecli) updateDrive(ctx context.Context, modelNumber, updateFile string
 		filepath.Dir(updateFile),
 	)
 
+	if m.DryRun {
+		return updateDryRunError(m.Executor, &common.Common{
+			Model:    drive.ModelNumber,
+			Vendor:   common.VendorFromString(drive.ModelNumber),
+			Serial:   drive.SerialNumber,
+			Firmware: &common.Firmware{Installed: drive.FirmwareRevision},
+		})
+	}
+
 	result, err := m.Executor.Exec(ctx)
 	if err != nil {
 		return newExecError(m.Executor.GetCmd(), result)
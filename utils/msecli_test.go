@@ -3,11 +3,14 @@ package utils
 import (
 	"context"
 	"os"
+	"path/filepath"
 	"testing"
 
 	common "github.com/metal-toolbox/bmc-common"
+	"github.com/metal-toolbox/ironlib/errs"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func newFakeMsecli() (*Msecli, error) {
@@ -132,3 +135,30 @@ func Test_QueryOutputEmpty(t *testing.T) {
 	_, err = m.Query(context.Background())
 	assert.Equal(t, ErrNoCommandOutput, errors.Cause(err))
 }
+
+func Test_MsecliUpdateDriveDryRun(t *testing.T) {
+	// the env vars set by the tests above fail the fake msecli commands
+	t.Setenv("FAIL_MICRON_UPDATE", "")
+	t.Setenv("FAIL_MICRON_QUERY", "")
+
+	m, err := newFakeMsecli()
+	require.NoError(t, err)
+
+	m.SetDryRun(true)
+
+	updateFile := filepath.Join(t.TempDir(), "1100_MU03.bin")
+	require.NoError(t, os.WriteFile(updateFile, []byte("firmware"), 0o600))
+
+	err = m.UpdateDrive(context.TODO(), updateFile, "", "193423711167")
+
+	dryRun := &errs.UpdateDryRunError{}
+	require.ErrorAs(t, err, &dryRun)
+	require.Len(t, dryRun.Commands, 1)
+
+	assert.Equal(t, "msecli -U -m 5200MAX -i "+filepath.Dir(updateFile), dryRun.Commands[0].Cmd)
+	assert.Equal(t, "193423711167", dryRun.Commands[0].Components[0].Serial)
+	assert.Equal(t, "D1MU020", dryRun.Commands[0].Components[0].Firmware.Installed)
+
+	// the update file is not renamed
+	assert.FileExists(t, updateFile)
+}
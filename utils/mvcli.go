@@ -42,6 +42,8 @@ var (
 // Mvcli is a mvcli command executor object
 type Mvcli struct {
 	Executor Executor
+	// DryRun is set to return the update commands with errs.UpdateDryRunError instead of executing them.
+	DryRun bool
 }
 
 // MvcliDevice is a marvell device object
@@ -74,6 +76,7 @@ var (
 	ErrInvalidVirtualDiskID = errors.New("invalid virtual disk id")
 	ErrDestroyVirtualDisk   = errors.New("failed to destroy virtual disk")
 	ErrCreateVirtualDisk    = errors.New("failed to create virtual disk")
+	ErrFirmwareUpdate       = errors.New("failed to update controller firmware")
 )
 
 func CreateVirtualDiskError(createStdout []byte) error {
@@ -84,6 +87,10 @@ func DestroyVirtualDiskError(destroyStdout []byte) error {
 	return wrapError(ErrDestroyVirtualDisk, "stdout", string(destroyStdout))
 }
 
+func FirmwareUpdateError(flashStdout []byte) error {
+	return wrapError(ErrFirmwareUpdate, "stdout", string(flashStdout))
+}
+
 func InvalidVirtualDiskIDError(virtualDiskID int) error {
 	return wrapError(ErrInvalidVirtualDiskID, "virtualDiskID", virtualDiskID)
 }
@@ -443,6 +450,39 @@ func (m *Mvcli) Destroy(ctx context.Context, virtualDiskID int) error {
 	return nil
 }
 
+// SetDryRun sets the updater to return the update commands instead of executing them
+func (m *Mvcli) SetDryRun(dryRun bool) {
+	m.DryRun = dryRun
+}
+
+// UpdateStorageController installs the controller firmware image - the BOSS controller raw flash image,
+// ErrRebootRequired is returned once installed since the firmware is activated on the next host reboot.
+//
+// This method implements the actions.StorageControllerUpdater interface.
+func (m *Mvcli) UpdateStorageController(ctx context.Context, updateFile, _ string) error {
+	// confirm the flash update prompt
+	m.Executor.SetStdin(bytes.NewReader([]byte("y\n")))
+
+	m.Executor.SetArgs("flash", "-a", "update", "-f", updateFile, "-t", "raw")
+
+	if m.DryRun {
+		return updateDryRunError(m.Executor)
+	}
+
+	result, err := m.Executor.Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	// mvcli exits with a zero status when the flash fails, the failure is described in the output
+	out := strings.ToLower(string(result.Stdout))
+	if strings.Contains(out, "error") || strings.Contains(out, "fail") {
+		return FirmwareUpdateError(result.Stdout)
+	}
+
+	return rebootRequiredError(common.SlugStorageController)
+}
+
 func (m *Mvcli) FindVdByName(ctx context.Context, name string) *MvcliDevice {
 	return m.FindVdBy(ctx, "Name", name)
 }
@@ -476,3 +516,25 @@ func (m *Mvcli) FindVdBy(ctx context.Context, k string, v interface{}) *MvcliDev
 func (m *Mvcli) VirtualDisks(ctx context.Context) ([]*MvcliDevice, error) {
 	return m.Info(ctx, "vd")
 }
+
+// ListVirtualDisks returns the virtual disks as common.VirtualDisk objects
+//
+// This method implements the actions.VirtualDiskManager interface.
+func (m *Mvcli) ListVirtualDisks(ctx context.Context) ([]*common.VirtualDisk, error) {
+	virtualDisks, err := m.VirtualDisks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cVirtualDisks := []*common.VirtualDisk{}
+
+	for _, vd := range virtualDisks {
+		cVirtualDisks = append(cVirtualDisks, &common.VirtualDisk{
+			ID:       fmt.Sprintf("%d", vd.ID),
+			Name:     vd.Name,
+			RaidType: vd.Type,
+		})
+	}
+
+	return cVirtualDisks, nil
+}
@@ -111,3 +111,33 @@ func Test_MvcliDrives(t *testing.T) {
 
 	assert.Equal(t, expected, inventory)
 }
+
+func Test_MvcliUpdateStorageController(t *testing.T) {
+	testcases := []struct {
+		name    string
+		stdout  string
+		wantErr error
+	}{
+		{
+			"flash update succeeded",
+			"SG driver version 3.5.36.\nUpdate flash successfully.\n",
+			ErrRebootRequired,
+		},
+		{
+			"flash update failed",
+			"SG driver version 3.5.36.\nUpdate flash failed: image is invalid.\n",
+			ErrFirmwareUpdate,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := &Mvcli{Executor: NewFakeExecutor("mvcli")}
+			cli.Executor.SetStdout([]byte(tc.stdout))
+
+			err := cli.UpdateStorageController(context.TODO(), "/tmp/boss.bin", "")
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, "mvcli flash -a update -f /tmp/boss.bin -t raw", cli.Executor.GetCmd())
+		})
+	}
+}
@@ -1,14 +1,52 @@
 package utils
 
-import "strings"
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	common "github.com/metal-toolbox/bmc-common"
+	"github.com/pkg/errors"
+)
 
 const (
 	enabledValue  = "Enabled"
 	disabledValue = "Disabled"
+
+	rawKeyPrefix = "raw:"
 )
 
+// biosVendorSettings maps the normalized BIOS setting keys to the vendor native setting name,
+// this is the inverse of NormalizeBIOSConfiguration.
+var biosVendorSettings = map[string]map[string]string{
+	common.VendorDell: {
+		"amd_sev":     "CpuMinSevAsid",
+		"boot_mode":   "BootMode",
+		"intel_txt":   "IntelTxt",
+		"secure_boot": "SecureBoot",
+		"smt":         "LogicalProc",
+		"sr_iov":      "SriovGlobalEnable",
+		"tpm":         "TpmSecurity",
+	},
+	common.VendorSupermicro: {
+		"boot_mode":   "Boot mode select",
+		"intel_sgx":   "Software Guard Extensions (SGX)",
+		"secure_boot": "Secure Boot",
+		// multi socket boards name this setting "Hyper-Threading [ALL]", which sum accepts as an alias
+		"smt": "Hyper-Threading",
+		"tpm": "Security Device Support",
+	},
+	common.VendorAsrockrack: {
+		"intel_sgx": "Software Guard Extensions (SGX)",
+		"tpm":       "Security Device Support",
+	},
+}
+
+// NormalizeBIOSConfiguration returns the given vendor BIOS configuration with known settings
+// renamed to their normalized key, settings that are not normalized are prefixed with "raw:".
+//
 // nolint:gocyclo // going through all bios values to standardize them is going to be high complexity
-func normalizeBIOSConfiguration(cfg map[string]string) map[string]string {
+func NormalizeBIOSConfiguration(cfg map[string]string) map[string]string {
 	normalizedCfg := make(map[string]string)
 
 	for k, v := range cfg {
@@ -48,7 +86,7 @@ func normalizeBIOSConfiguration(cfg map[string]string) map[string]string {
 		case "OldSysPassword":
 		default:
 			// When we don't normalize the value append "raw:" to the value
-			normalizedCfg["raw:"+k] = nV
+			normalizedCfg[rawKeyPrefix+k] = nV
 		}
 	}
 
@@ -82,3 +120,123 @@ func normalizeBootMode(v string) string {
 		return strings.ToUpper(v)
 	}
 }
+
+// biosValueMatches returns true when the vendor BIOS setting value is equivalent to the given value,
+// enable/disable and on/off variants are considered equivalent.
+func biosValueMatches(vendorValue, value string) bool {
+	return strings.EqualFold(
+		normalizeValue(strings.TrimSpace(vendorValue)),
+		normalizeValue(strings.TrimSpace(value)),
+	)
+}
+
+// DenormalizeBIOSConfiguration returns the given BIOS configuration with normalized settings
+// renamed to the vendor native setting name and value.
+//
+// Settings prefixed with "raw:" have the prefix removed, any other setting is considered vendor native
+// and returned as is. An error is returned listing the normalized settings not mapped for the vendor.
+func DenormalizeBIOSConfiguration(vendor string, cfg map[string]string) (map[string]string, error) {
+	vendorSettings := biosVendorSettings[common.FormatVendorName(vendor)]
+
+	vendorCfg := make(map[string]string, len(cfg))
+	notMapped := []string{}
+
+	for _, k := range slices.Sorted(maps.Keys(cfg)) {
+		var vKey, vValue string
+
+		switch {
+		case strings.HasPrefix(k, rawKeyPrefix):
+			vKey, vValue = strings.TrimPrefix(k, rawKeyPrefix), cfg[k]
+		case IsNormalizedBIOSKey(k):
+			name, exists := vendorSettings[k]
+			if !exists {
+				notMapped = append(notMapped, k)
+				continue
+			}
+
+			vKey, vValue = name, denormalizeValue(vendor, name, cfg[k])
+		default:
+			vKey, vValue = k, cfg[k]
+		}
+
+		if _, exists := vendorCfg[vKey]; exists {
+			return nil, errors.Wrap(ErrBIOSConfigKeyDuplicate, vKey)
+		}
+
+		vendorCfg[vKey] = vValue
+	}
+
+	if len(notMapped) > 0 {
+		return nil, errors.Wrap(ErrBIOSConfigKeyNotMapped, vendor+": "+strings.Join(notMapped, ", "))
+	}
+
+	return vendorCfg, nil
+}
+
+// NormalizeBIOSSetting returns the normalized key and value for a single BIOS setting,
+// the key may be a normalized key, a "raw:" prefixed key or a vendor native setting name.
+//
+// An empty key is returned for settings dropped by the normalizer.
+func NormalizeBIOSSetting(k, v string) (key, value string) {
+	if IsNormalizedBIOSKey(k) {
+		if k == "boot_mode" {
+			return k, normalizeBootMode(v)
+		}
+
+		return k, normalizeValue(v)
+	}
+
+	for nK, nV := range NormalizeBIOSConfiguration(map[string]string{strings.TrimPrefix(k, rawKeyPrefix): v}) {
+		return nK, nV
+	}
+
+	return "", ""
+}
+
+// IsNormalizedBIOSKey returns true if the key is a normalized BIOS setting key for any vendor
+func IsNormalizedBIOSKey(k string) bool {
+	for _, settings := range biosVendorSettings {
+		if _, exists := settings[k]; exists {
+			return true
+		}
+	}
+
+	return false
+}
+
+// denormalizeValue returns the vendor native value for a normalized BIOS setting value
+func denormalizeValue(vendor, name, v string) string {
+	nV := normalizeValue(v)
+
+	switch common.FormatVendorName(vendor) {
+	case common.VendorDell:
+		switch name {
+		case "BootMode":
+			switch strings.ToUpper(v) {
+			case "BIOS", "LEGACY":
+				return "Bios"
+			case "UEFI":
+				return "Uefi"
+			}
+		// these settings are On/Off
+		case "IntelTxt", "TpmSecurity":
+			switch nV {
+			case enabledValue:
+				return "On"
+			case disabledValue:
+				return "Off"
+			}
+		}
+	case common.VendorSupermicro:
+		if name == "Boot mode select" {
+			switch strings.ToUpper(v) {
+			case "BIOS", "LEGACY":
+				return "LEGACY"
+			default:
+				return strings.ToUpper(v)
+			}
+		}
+	}
+
+	return nV
+}
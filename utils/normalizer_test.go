@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"testing"
+
+	common "github.com/metal-toolbox/bmc-common"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_DenormalizeBIOSConfiguration(t *testing.T) {
+	testCases := []struct {
+		name        string
+		vendor      string
+		cfg         map[string]string
+		expected    map[string]string
+		expectedErr error
+	}{
+		{
+			"dell normalized, raw and native settings",
+			common.VendorDell,
+			map[string]string{
+				"smt":         "Disabled",
+				"secure_boot": "Enabled",
+				"tpm":         "Enabled",
+				"boot_mode":   "UEFI",
+				"raw:MemTest": "Disabled",
+				"ProcCStates": "Enabled",
+			},
+			map[string]string{
+				"LogicalProc": "Disabled",
+				"SecureBoot":  "Enabled",
+				"TpmSecurity": "On",
+				"BootMode":    "Uefi",
+				"MemTest":     "Disabled",
+				"ProcCStates": "Enabled",
+			},
+			nil,
+		},
+		{
+			"supermicro normalized settings",
+			"Supermicro",
+			map[string]string{"smt": "disable", "boot_mode": "BIOS", "tpm": "Enabled"},
+			map[string]string{"Hyper-Threading": "Disabled", "Boot mode select": "LEGACY", "Security Device Support": "Enabled"},
+			nil,
+		},
+		{
+			"asrockrack unmapped settings",
+			common.VendorAsrockrack,
+			map[string]string{"smt": "Disabled", "secure_boot": "Enabled", "tpm": "Enabled"},
+			nil,
+			ErrBIOSConfigKeyNotMapped,
+		},
+		{
+			"normalized and raw setting for the same vendor setting",
+			common.VendorDell,
+			map[string]string{"smt": "Disabled", "raw:LogicalProc": "Enabled"},
+			nil,
+			ErrBIOSConfigKeyDuplicate,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := DenormalizeBIOSConfiguration(tc.vendor, tc.cfg)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+
+	_, err := DenormalizeBIOSConfiguration(common.VendorAsrockrack, map[string]string{"smt": "Disabled", "secure_boot": "Enabled"})
+	assert.EqualError(t, err, "asrockrack: secure_boot, smt: "+ErrBIOSConfigKeyNotMapped.Error())
+}
@@ -7,6 +7,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log"
+	"maps"
 	"os"
 	"path"
 	"strconv"
@@ -27,8 +29,18 @@ var (
 	errInvalidCreateNSArgs    = errors.New("invalid ns-create args")
 )
 
+// dlfeat deallocated logical block read behavior values
+// https://github.com/linux-nvme/nvme-cli/blob/v2.8/nvme-print-stdout.c#L2531-L2541
+const (
+	dlfeatReadZeroes = 0b001
+	dlfeatReadOnes   = 0b010
+)
+
 type Nvme struct {
 	Executor Executor
+	// Readback when set, reads back the drive after a format or sanitize
+	// to verify the drive reads back the deallocated logical block pattern reported by the drive.
+	Readback *WipeReadback
 }
 
 type nvmeDeviceAttributes struct {
@@ -45,6 +57,20 @@ type nvmeList struct {
 	Devices []*nvmeDeviceAttributes `json:"Devices"`
 }
 
+// NvmeSmartLog is the nvme smart-log health information log,
+// the temperature is reported in kelvin.
+type NvmeSmartLog struct {
+	CriticalWarning int   `json:"critical_warning"`
+	Temperature     int   `json:"temperature"`
+	AvailableSpare  int   `json:"avail_spare"`
+	PercentUsed     int   `json:"percent_used"`
+	PowerOnHours    int64 `json:"power_on_hours"`
+	MediaErrors     int64 `json:"media_errors"`
+}
+
+// kelvin is 0 degrees celsius in kelvin
+const kelvin = 273
+
 // Return a new nvme executor
 func NewNvmeCmd(trace bool) *Nvme {
 	utility := "nvme"
@@ -109,6 +135,14 @@ func (n *Nvme) Drives(ctx context.Context) ([]*common.Drive, error) {
 			metadata[f.Description] = strconv.FormatBool(f.Enabled)
 		}
 
+		// Collect drive health metrics, the drive is listed without them when the smart-log is not read
+		smartLog, err := n.SmartLog(ctx, d.DevicePath)
+		if err != nil {
+			log.Printf("Warn: NVMe drive health metrics not collected, smart-log failed: %s: %s\n", d.DevicePath, err)
+		} else {
+			maps.Copy(metadata, smartLog.HealthMetadata())
+		}
+
 		drives[i] = &common.Drive{
 			Common: common.Common{
 				LogicalName:  d.DevicePath,
@@ -139,6 +173,37 @@ func (n *Nvme) list(ctx context.Context) ([]byte, error) {
 	return result.Stdout, nil
 }
 
+// SmartLog runs nvme smart-log and returns the drive health information log
+func (n *Nvme) SmartLog(ctx context.Context, logicalName string) (*NvmeSmartLog, error) {
+	// nvme smart-log --output-format=json devicepath
+	n.Executor.SetArgs("smart-log", "--output-format=json", logicalName)
+
+	result, err := n.Executor.Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	smartLog := &NvmeSmartLog{}
+	if err := json.Unmarshal(result.Stdout, smartLog); err != nil {
+		return nil, err
+	}
+
+	return smartLog, nil
+}
+
+// HealthMetadata returns the drive health metrics as common.Drive metadata,
+// the metadata keys are defined in model/drive_health.go
+func (l *NvmeSmartLog) HealthMetadata() map[string]string {
+	return map[string]string{
+		model.DriveHealthPercentageUsed:  strconv.Itoa(l.PercentUsed),
+		model.DriveHealthMediaErrors:     strconv.FormatInt(l.MediaErrors, 10),
+		model.DriveHealthCriticalWarning: strconv.Itoa(l.CriticalWarning),
+		model.DriveHealthAvailableSpare:  strconv.Itoa(l.AvailableSpare),
+		model.DriveHealthPowerOnHours:    strconv.FormatInt(l.PowerOnHours, 10),
+		model.DriveHealthTemperature:     strconv.Itoa(l.Temperature - kelvin),
+	}
+}
+
 func (n *Nvme) cmdListCapabilities(ctx context.Context, logicalName string) ([]byte, error) {
 	// nvme id-ctrl --output-format=json devicepath
 	n.Executor.SetArgs("id-ctrl", "--output-format=json", logicalName)
@@ -311,7 +376,7 @@ func (n *Nvme) WipeDrive(ctx context.Context, logger *logrus.Logger, drive *comm
 		// nolint:govet
 		l := l.WithField("method", "sanitize").WithField("action", CryptoErase)
 		l.Debug("wiping")
-		err := n.Sanitize(ctx, drive, CryptoErase)
+		err := attemptWipeMethod(ctx, "sanitize-crypto-erase", func() error { return n.sanitize(ctx, l, drive, CryptoErase) })
 		if err == nil {
 			return nil
 		}
@@ -321,7 +386,7 @@ func (n *Nvme) WipeDrive(ctx context.Context, logger *logrus.Logger, drive *comm
 		// nolint:govet
 		l := l.WithField("method", "sanitize").WithField("action", BlockErase)
 		l.Debug("wiping")
-		err := n.Sanitize(ctx, drive, BlockErase)
+		err := attemptWipeMethod(ctx, "sanitize-block-erase", func() error { return n.sanitize(ctx, l, drive, BlockErase) })
 		if err == nil {
 			return nil
 		}
@@ -331,7 +396,7 @@ func (n *Nvme) WipeDrive(ctx context.Context, logger *logrus.Logger, drive *comm
 		// nolint:govet
 		l := l.WithField("method", "format").WithField("setting", CryptographicErase)
 		l.Debug("wiping")
-		err := n.Format(ctx, drive, CryptographicErase)
+		err := attemptWipeMethod(ctx, "format-ses-crypto-erase", func() error { return n.format(ctx, l, drive, CryptographicErase) })
 		if err == nil {
 			return nil
 		}
@@ -340,7 +405,7 @@ func (n *Nvme) WipeDrive(ctx context.Context, logger *logrus.Logger, drive *comm
 
 	l = l.WithField("method", "format").WithField("setting", UserDataErase)
 	l.Debug("wiping")
-	err := n.Format(ctx, drive, UserDataErase)
+	err := attemptWipeMethod(ctx, "format-ses-user-data-erase", func() error { return n.format(ctx, l, drive, UserDataErase) })
 	if err == nil {
 		return nil
 	}
@@ -348,7 +413,13 @@ func (n *Nvme) WipeDrive(ctx context.Context, logger *logrus.Logger, drive *comm
 	return ErrIneffectiveWipe
 }
 
+// Sanitize runs the sanitize action on the drive and verifies the wipe,
+// the read back when enabled is logged through the standard logger.
 func (n *Nvme) Sanitize(ctx context.Context, drive *common.Drive, sanact SanitizeAction) error {
+	return n.sanitize(ctx, logrus.WithField("drive", drive.LogicalName), drive, sanact)
+}
+
+func (n *Nvme) sanitize(ctx context.Context, log *logrus.Entry, drive *common.Drive, sanact SanitizeAction) error {
 	switch sanact { // nolint:exhaustive
 	case BlockErase, CryptoErase:
 	default:
@@ -368,7 +439,7 @@ func (n *Nvme) Sanitize(ctx context.Context, drive *common.Drive, sanact Sanitiz
 
 	// now we loop until sanitize-log reports that sanitization is complete
 	dev := path.Base(drive.LogicalName)
-	var log map[string]struct {
+	var sanitizeLog map[string]struct {
 		Progress uint16 `json:"sprog"`
 	}
 	for {
@@ -377,12 +448,12 @@ func (n *Nvme) Sanitize(ctx context.Context, drive *common.Drive, sanact Sanitiz
 		if err != nil {
 			return err
 		}
-		err = json.Unmarshal(result.Stdout, &log)
+		err = json.Unmarshal(result.Stdout, &sanitizeLog)
 		if err != nil {
 			return err
 		}
 
-		l, ok := log[dev]
+		l, ok := sanitizeLog[dev]
 		if !ok {
 			return fmt.Errorf("%s: device not present in sanitize-log: %w: %s", dev, io.ErrUnexpectedEOF, result.Stdout)
 		}
@@ -393,10 +464,20 @@ func (n *Nvme) Sanitize(ctx context.Context, drive *common.Drive, sanact Sanitiz
 		time.Sleep(100 * time.Millisecond)
 	}
 
-	return verify()
+	if err := verifyWipe(ctx, wipeVerificationWatermarks, verify); err != nil {
+		return err
+	}
+
+	return n.readbackVerify(ctx, log, drive)
 }
 
+// Format formats the drive with the secure erase setting and verifies the wipe,
+// the read back when enabled is logged through the standard logger.
 func (n *Nvme) Format(ctx context.Context, drive *common.Drive, ses SecureEraseSetting) error {
+	return n.format(ctx, logrus.WithField("drive", drive.LogicalName), drive, ses)
+}
+
+func (n *Nvme) format(ctx context.Context, log *logrus.Entry, drive *common.Drive, ses SecureEraseSetting) error {
 	switch ses { // nolint:exhaustive
 	case UserDataErase, CryptographicErase:
 	default:
@@ -413,7 +494,56 @@ func (n *Nvme) Format(ctx context.Context, drive *common.Drive, ses SecureEraseS
 	if err != nil {
 		return err
 	}
-	return verify()
+
+	if err := verifyWipe(ctx, wipeVerificationWatermarks, verify); err != nil {
+		return err
+	}
+
+	return n.readbackVerify(ctx, log, drive)
+}
+
+// SetReadback enables reading back the given percentage of the drive after a format or sanitize,
+// 0 or 100 reads back the whole drive.
+func (n *Nvme) SetReadback(samplePercent int) {
+	n.Readback = &WipeReadback{SamplePercent: samplePercent}
+}
+
+// readbackVerify reads back the drive when enabled and the drive reports a deallocated logical block read pattern
+func (n *Nvme) readbackVerify(ctx context.Context, log *logrus.Entry, drive *common.Drive) error {
+	if n.Readback == nil {
+		return nil
+	}
+
+	log = log.WithField("pass", "verify")
+
+	n.Executor.SetArgs("id-ns", "--output-format=json", drive.LogicalName)
+	result, err := n.Executor.Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	ns := struct {
+		DLFEAT uint `json:"dlfeat"`
+	}{}
+
+	if err := json.Unmarshal(result.Stdout, &ns); err != nil {
+		return err
+	}
+
+	var pattern byte
+
+	// bits 2:0 report the values read from a deallocated logical block
+	switch ns.DLFEAT & 0b111 {
+	case dlfeatReadZeroes:
+		pattern = 0x00
+	case dlfeatReadOnes:
+		pattern = 0xff
+	default:
+		log.Debug("drive does not report a deallocated logical block pattern, skipping read back")
+		return nil
+	}
+
+	return readbackVerify(ctx, log, drive.LogicalName, repeatFiller([]byte{pattern}), n.Readback)
 }
 
 func (n *Nvme) listNS(ctx context.Context, logicalName string) ([]uint, error) {
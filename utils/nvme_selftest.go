@@ -0,0 +1,150 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/metal-toolbox/ironlib/model"
+)
+
+var errSelfTestInvalidType = errors.New("invalid self-test type")
+
+// device self-test codes - NVMe base specification, Device Self-test command
+const (
+	nvmeSelfTestShort    = 0x1
+	nvmeSelfTestExtended = 0x2
+	nvmeSelfTestAbort    = 0xf
+)
+
+// device self-test result values - NVMe base specification, Self-test Result Data Structure
+const (
+	nvmeSelfTestResultPassed = 0x0
+	nvmeSelfTestResultUnused = 0xf
+)
+
+// nvmeSelfTestResultDescriptions maps the self-test result values to their description
+var nvmeSelfTestResultDescriptions = map[int]string{
+	0x0: "Operation completed without error",
+	0x1: "Operation was aborted by a Device Self-test command",
+	0x2: "Operation was aborted by a Controller Level Reset",
+	0x3: "Operation was aborted due to a removal of a namespace from the namespace inventory",
+	0x4: "Operation was aborted due to the processing of a Format NVM command",
+	0x5: "A fatal error or unknown test error occurred while the controller was executing the device self-test operation",
+	0x6: "Operation completed with a segment that failed and the segment that failed is not known",
+	0x7: "Operation completed with one or more failed segments",
+	0x8: "Operation was aborted for unknown reason",
+	0x9: "Operation was aborted due to a sanitize operation",
+}
+
+// nvmeSelfTestCodeDescriptions maps the self-test codes to their description
+var nvmeSelfTestCodeDescriptions = map[int]string{
+	nvmeSelfTestShort:    "Short device self-test",
+	nvmeSelfTestExtended: "Extended device self-test",
+	0xe:                  "Vendor specific",
+}
+
+// the Valid Diagnostic Information bit indicating the Failing LBA field is valid
+const nvmeSelfTestFailingLBAValid = 0b10
+
+// nvmeSelfTestLog is the nvme self-test-log output
+type nvmeSelfTestLog struct {
+	CurrentOperation  int                     `json:"Current Device Self-Test Operation"`
+	CurrentCompletion int                     `json:"Current Device Self-Test Completion"`
+	Entries           []*nvmeSelfTestLogEntry `json:"List of DST Log Entry"`
+}
+
+type nvmeSelfTestLogEntry struct {
+	Result         int    `json:"Self test result"`
+	Code           int    `json:"Self test code"`
+	ValidDiagnosis int    `json:"Valid Diagnostic Information"`
+	PowerOnHours   int64  `json:"Power on hours"`
+	FailingLBA     uint64 `json:"Failing LBA"`
+}
+
+// StartSelfTest runs nvme device-self-test to start a self-test on the drive, the self-test runs in the background on the drive.
+func (n *Nvme) StartSelfTest(ctx context.Context, logicalName string, testType model.DriveSelfTestType) error {
+	var code int
+
+	switch testType {
+	case model.DriveSelfTestShort:
+		code = nvmeSelfTestShort
+	case model.DriveSelfTestExtended:
+		code = nvmeSelfTestExtended
+	default:
+		return fmt.Errorf("%w: %s", errSelfTestInvalidType, testType)
+	}
+
+	return n.deviceSelfTest(ctx, logicalName, code)
+}
+
+// AbortSelfTest runs nvme device-self-test to abort the self-test running on the drive.
+func (n *Nvme) AbortSelfTest(ctx context.Context, logicalName string) error {
+	return n.deviceSelfTest(ctx, logicalName, nvmeSelfTestAbort)
+}
+
+func (n *Nvme) deviceSelfTest(ctx context.Context, logicalName string, code int) error {
+	// nvme device-self-test --self-test-code=1 devicepath
+	n.Executor.SetArgs("device-self-test", "--self-test-code="+strconv.Itoa(code), logicalName)
+
+	_, err := n.Executor.Exec(ctx)
+
+	return err
+}
+
+// SelfTestStatus runs nvme self-test-log and returns the drive self-test status.
+func (n *Nvme) SelfTestStatus(ctx context.Context, logicalName string) (*model.DriveSelfTestStatus, error) {
+	// nvme self-test-log --output-format=json devicepath
+	n.Executor.SetArgs("self-test-log", "--output-format=json", logicalName)
+
+	result, err := n.Executor.Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	log := &nvmeSelfTestLog{}
+	if err := json.Unmarshal(result.Stdout, log); err != nil {
+		return nil, err
+	}
+
+	return log.status(), nil
+}
+
+// status returns the self-test log as a model.DriveSelfTestStatus
+func (l *nvmeSelfTestLog) status() *model.DriveSelfTestStatus {
+	status := &model.DriveSelfTestStatus{}
+
+	if l.CurrentOperation != 0 {
+		status.InProgress = true
+		status.RemainingPercent = 100 - l.CurrentCompletion
+	}
+
+	// the most recent entry is listed first, unused entries are skipped
+	for _, entry := range l.Entries {
+		if entry.Result == nvmeSelfTestResultUnused {
+			continue
+		}
+
+		status.LogEntries++
+
+		if status.Latest != nil {
+			continue
+		}
+
+		status.Latest = &model.DriveSelfTestLogEntry{
+			Type:         nvmeSelfTestCodeDescriptions[entry.Code],
+			Status:       nvmeSelfTestResultDescriptions[entry.Result],
+			Passed:       entry.Result == nvmeSelfTestResultPassed,
+			PowerOnHours: entry.PowerOnHours,
+		}
+
+		if entry.ValidDiagnosis&nvmeSelfTestFailingLBAValid != 0 {
+			lba := entry.FailingLBA
+			status.Latest.FailingLBA = &lba
+		}
+	}
+
+	return status
+}
@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metal-toolbox/ironlib/model"
+)
+
+func Test_NvmeSelfTestStatus(t *testing.T) {
+	n := NewFakeNvme()
+
+	status, err := n.SelfTestStatus(context.Background(), "/dev/nvme0")
+	require.NoError(t, err)
+
+	lba := uint64(1953525)
+	expected := &model.DriveSelfTestStatus{
+		Latest: &model.DriveSelfTestLogEntry{
+			Type:         "Extended device self-test",
+			Status:       "Operation completed with one or more failed segments",
+			PowerOnHours: 3415,
+			FailingLBA:   &lba,
+		},
+		LogEntries: 2,
+	}
+
+	assert.Equal(t, expected, status)
+}
+
+func Test_NvmeSelfTestLogStatus(t *testing.T) {
+	log := &nvmeSelfTestLog{
+		CurrentOperation:  nvmeSelfTestShort,
+		CurrentCompletion: 30,
+		Entries: []*nvmeSelfTestLogEntry{
+			{Result: nvmeSelfTestResultUnused},
+			{Result: nvmeSelfTestResultPassed, Code: nvmeSelfTestShort, PowerOnHours: 10},
+		},
+	}
+
+	expected := &model.DriveSelfTestStatus{
+		InProgress:       true,
+		RemainingPercent: 70,
+		Latest: &model.DriveSelfTestLogEntry{
+			Type:         "Short device self-test",
+			Status:       "Operation completed without error",
+			Passed:       true,
+			PowerOnHours: 10,
+		},
+		LogEntries: 1,
+	}
+
+	assert.Equal(t, expected, log.status())
+}
+
+func Test_NvmeStartSelfTest(t *testing.T) {
+	n := NewFakeNvme()
+
+	require.NoError(t, n.StartSelfTest(context.Background(), "/dev/nvme0", model.DriveSelfTestShort))
+	assert.Equal(t, []string{"device-self-test", "--self-test-code=1", "/dev/nvme0"}, n.Executor.(*FakeExecute).Args)
+
+	require.NoError(t, n.AbortSelfTest(context.Background(), "/dev/nvme0"))
+	assert.Equal(t, []string{"device-self-test", "--self-test-code=15", "/dev/nvme0"}, n.Executor.(*FakeExecute).Args)
+
+	assert.ErrorIs(t, n.StartSelfTest(context.Background(), "/dev/nvme0", "conveyance"), errSelfTestInvalidType)
+}
@@ -8,9 +8,12 @@ import (
 	"testing"
 
 	common "github.com/metal-toolbox/bmc-common"
+	"github.com/sirupsen/logrus"
 	tlogrus "github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/metal-toolbox/ironlib/model"
 )
 
 func Test_NvmeComponents(t *testing.T) {
@@ -35,6 +38,12 @@ func Test_NvmeComponents(t *testing.T) {
 				"Format Applies to All/Single Namespace(s) (t:All, f:Single)":       "false",
 				"No-Deallocate After Sanitize bit in Sanitize command Supported":    "false",
 				"Overwrite Sanitize Operation Supported":                            "false",
+				model.DriveHealthPercentageUsed:                                     "3",
+				model.DriveHealthMediaErrors:                                        "0",
+				model.DriveHealthCriticalWarning:                                    "0",
+				model.DriveHealthAvailableSpare:                                     "100",
+				model.DriveHealthPowerOnHours:                                       "3415",
+				model.DriveHealthTemperature:                                        "55",
 			},
 		}},
 		{Common: common.Common{
@@ -56,6 +65,12 @@ func Test_NvmeComponents(t *testing.T) {
 				"Format Applies to All/Single Namespace(s) (t:All, f:Single)":       "false",
 				"No-Deallocate After Sanitize bit in Sanitize command Supported":    "false",
 				"Overwrite Sanitize Operation Supported":                            "false",
+				model.DriveHealthPercentageUsed:                                     "3",
+				model.DriveHealthMediaErrors:                                        "0",
+				model.DriveHealthCriticalWarning:                                    "0",
+				model.DriveHealthAvailableSpare:                                     "100",
+				model.DriveHealthPowerOnHours:                                       "3415",
+				model.DriveHealthTemperature:                                        "55",
 			},
 		}},
 	}
@@ -70,6 +85,23 @@ func Test_NvmeComponents(t *testing.T) {
 	assert.Equal(t, expected, drives)
 }
 
+func Test_NvmeDrivesSmartLogFailure(t *testing.T) {
+	t.Setenv("FAIL_NVME_SMART_LOG", "1")
+
+	n := NewFakeNvme()
+
+	drives, err := n.Drives(context.TODO())
+	require.NoError(t, err)
+	require.Len(t, drives, 2)
+
+	// the drives are listed without the health metrics
+	for _, drive := range drives {
+		assert.Equal(t, "Z9DF70I", drive.Serial[:7])
+		assert.NotContains(t, drive.Metadata, model.DriveHealthPercentageUsed)
+		assert.Equal(t, "true", drive.Metadata["Crypto Erase Supported as part of Secure Erase"])
+	}
+}
+
 func Test_NvmeDriveCapabilities(t *testing.T) {
 	n := NewFakeNvme()
 
@@ -301,3 +333,36 @@ func Test_NvmeResetNS(t *testing.T) {
 	err := NewFakeNvme().ResetNS(context.Background(), "/dev/nvme0")
 	assert.NoError(t, err)
 }
+
+func Test_NvmeFormatReadback(t *testing.T) {
+	n := NewFakeNvme()
+	n.SetReadback(0)
+
+	dev := fakeNVMEDrive(t)
+	require.NoError(t, n.Format(context.Background(), dev, UserDataErase))
+
+	e, ok := n.Executor.(*FakeExecute)
+	require.True(t, ok)
+	require.Equal(t, []string{"id-ns", "--output-format=json", dev.LogicalName}, e.Args)
+}
+
+func Test_NvmeWipeDriveReadbackLogger(t *testing.T) {
+	n := NewFakeNvme()
+	n.SetReadback(0)
+
+	dev := fakeNVMEDrive(t)
+	logger, hook := tlogrus.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+	defer hook.Reset()
+
+	require.NoError(t, n.WipeDrive(context.Background(), logger, dev))
+
+	var verifyEntries int
+	for _, entry := range hook.AllEntries() {
+		if entry.Data["pass"] == "verify" {
+			require.Equal(t, dev.LogicalName, entry.Data["drive"])
+			verifyEntries++
+		}
+	}
+	require.NotZero(t, verifyEntries)
+}
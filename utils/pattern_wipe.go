@@ -0,0 +1,238 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	common "github.com/metal-toolbox/bmc-common"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const patternWipeBufferSize = 1024 * 1024
+
+var (
+	ErrPatternWipeNoPasses     = errors.New("expected one or more overwrite passes")
+	ErrPatternWipeFixedPattern = errors.New("fixed pattern pass requires a non empty pattern")
+	ErrPatternWipeUnknown      = errors.New("unknown overwrite pattern")
+)
+
+//go:generate stringer -type WipePattern -trimprefix WipePattern
+type WipePattern uint8
+
+const (
+	WipePatternZeros WipePattern = iota
+	WipePatternOnes
+	WipePatternRandom
+	WipePatternFixed
+)
+
+// WipePass is a single overwrite pass of the drive
+type WipePass struct {
+	Pattern WipePattern
+	// Fixed is the byte sequence repeated across the drive by a WipePatternFixed pass
+	Fixed []byte
+}
+
+// PatternWipe overwrites the drive with a sequence of patterns
+type PatternWipe struct {
+	Quiet  bool
+	Passes []WipePass
+	// Readback when set, reads back the drive after the final pass and compares it with the pattern written
+	Readback *WipeReadback
+}
+
+// NewPatternWipeCmd returns a pattern wipe executor for the given passes,
+// a single zeros pass is performed when no passes are given.
+func NewPatternWipeCmd(trace bool, passes ...WipePass) *PatternWipe {
+	if len(passes) == 0 {
+		passes = []WipePass{{Pattern: WipePatternZeros}}
+	}
+
+	p := &PatternWipe{Passes: passes}
+	if !trace {
+		p.SetQuiet()
+	}
+
+	return p
+}
+
+// SetQuiet lowers the verbosity
+func (p *PatternWipe) SetQuiet() {
+	p.Quiet = true
+}
+
+// SetReadback enables the verify pass after the final overwrite pass,
+// reading back the given percentage of the drive, 0 or 100 reads back the whole drive.
+func (p *PatternWipe) SetReadback(samplePercent int) {
+	p.Readback = &WipeReadback{SamplePercent: samplePercent}
+}
+
+// WipeDrive implements DriveWiper by overwriting the drive with each of the passes in order
+func (p *PatternWipe) WipeDrive(ctx context.Context, logger *logrus.Logger, drive *common.Drive) error {
+	return attemptWipeMethod(ctx, "pattern-overwrite", func() error { return p.wipe(ctx, logger, drive) })
+}
+
+func (p *PatternWipe) wipe(ctx context.Context, logger *logrus.Logger, drive *common.Drive) error {
+	log := logger.WithField("drive", drive.LogicalName).WithField("method", "pattern-overwrite")
+	log.Debug("wiping")
+
+	if len(p.Passes) == 0 {
+		return ErrPatternWipeNoPasses
+	}
+
+	fillers := make([]patternFiller, 0, len(p.Passes))
+	for _, pass := range p.Passes {
+		filler, err := newPatternFiller(pass)
+		if err != nil {
+			return err
+		}
+
+		fillers = append(fillers, filler)
+	}
+
+	verify, err := ApplyWatermarks(drive)
+	if err != nil {
+		return err
+	}
+
+	for i, filler := range fillers {
+		passLog := log.WithFields(logrus.Fields{
+			"pass":    fmt.Sprintf("%d/%d", i+1, len(fillers)),
+			"pattern": p.Passes[i].Pattern.String(),
+		})
+
+		if err := overwritePass(ctx, passLog, drive.LogicalName, filler); err != nil {
+			return errors.Wrap(err, fmt.Sprintf("overwrite pass %d", i+1))
+		}
+	}
+
+	if p.Readback != nil {
+		verifyLog := log.WithFields(logrus.Fields{
+			"pass":    "verify",
+			"pattern": p.Passes[len(p.Passes)-1].Pattern.String(),
+		})
+
+		if err := readbackVerify(ctx, verifyLog, drive.LogicalName, fillers[len(fillers)-1], p.Readback); err != nil {
+			return err
+		}
+	}
+
+	return verifyWipe(ctx, wipeVerificationWatermarks, verify)
+}
+
+// patternFiller fills the buffer with the pattern data for the given drive offset
+type patternFiller func(buf []byte, offset int64)
+
+// newPatternFiller returns the filler for the wipe pass
+//
+// Random passes write an AES-CTR keystream keyed from crypto/rand,
+// so the data written can be generated again to be verified.
+func newPatternFiller(pass WipePass) (patternFiller, error) {
+	switch pass.Pattern {
+	case WipePatternZeros:
+		return repeatFiller([]byte{0x00}), nil
+	case WipePatternOnes:
+		return repeatFiller([]byte{0xff}), nil
+	case WipePatternFixed:
+		if len(pass.Fixed) == 0 {
+			return nil, ErrPatternWipeFixedPattern
+		}
+
+		return repeatFiller(bytes.Clone(pass.Fixed)), nil
+	case WipePatternRandom:
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(buf []byte, offset int64) {
+			// the counter block is derived from the offset, the buffers are aligned to the AES block size
+			iv := make([]byte, aes.BlockSize)
+			for i, counter := 0, uint64(offset/aes.BlockSize); i < 8; i++ {
+				iv[aes.BlockSize-1-i] = byte(counter >> (8 * i))
+			}
+
+			clear(buf)
+			cipher.NewCTR(block, iv).XORKeyStream(buf, buf)
+		}, nil
+	default:
+		return nil, errors.Wrap(ErrPatternWipeUnknown, pass.Pattern.String())
+	}
+}
+
+// repeatFiller returns a filler repeating the pattern continuously across the drive
+func repeatFiller(pattern []byte) patternFiller {
+	return func(buf []byte, offset int64) {
+		start := int(offset % int64(len(pattern)))
+		for i := range buf {
+			buf[i] = pattern[(start+i)%len(pattern)]
+		}
+	}
+}
+
+// overwritePass writes the pattern across the whole drive
+func overwritePass(ctx context.Context, log *logrus.Entry, logicalName string, fill patternFiller) error {
+	// Write open
+	file, err := os.OpenFile(logicalName, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// Get disk or partition size
+	partitionSize, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	var bytesSinceLastPrint int64
+	var totalBytesWritten int64
+	buffer := make([]byte, patternWipeBufferSize)
+	start := time.Now()
+	for bytesRemaining := partitionSize; bytesRemaining > 0; {
+		// Check if the context has been canceled
+		select {
+		case <-ctx.Done():
+			log.Debug("stopping")
+			return ctx.Err()
+		default:
+			l := min(int64(len(buffer)), bytesRemaining)
+			fill(buffer[:l], totalBytesWritten)
+
+			bytesWritten, writeError := file.Write(buffer[:l])
+			if writeError != nil {
+				return writeError
+			}
+
+			totalBytesWritten += int64(bytesWritten)
+			bytesSinceLastPrint += int64(bytesWritten)
+			bytesRemaining -= int64(bytesWritten)
+			// Print progress report every 10 seconds and when done
+			if bytesRemaining == 0 || time.Since(start) >= 10*time.Second {
+				printProgress(log, totalBytesWritten, partitionSize, start, bytesSinceLastPrint)
+				start = time.Now()
+				bytesSinceLastPrint = 0
+			}
+		}
+	}
+
+	return file.Sync()
+}
@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"context"
+	"crypto/rand"
+	"os"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewPatternWipeCmd(t *testing.T) {
+	p := NewPatternWipeCmd(false)
+	require.Equal(t, []WipePass{{Pattern: WipePatternZeros}}, p.Passes)
+	require.True(t, p.Quiet)
+}
+
+func Test_PatternWipeWipeDrive(t *testing.T) {
+	// larger than the buffer and not aligned to it
+	size := int64(2*patternWipeBufferSize + 4099)
+
+	testCases := []struct {
+		name     string
+		passes   []WipePass
+		expected func([]byte)
+	}{
+		{
+			"zeros",
+			[]WipePass{{Pattern: WipePatternZeros}},
+			func(b []byte) { repeatFiller([]byte{0x00})(b, 0) },
+		},
+		{
+			"random, ones",
+			[]WipePass{{Pattern: WipePatternRandom}, {Pattern: WipePatternOnes}},
+			func(b []byte) { repeatFiller([]byte{0xff})(b, 0) },
+		},
+		{
+			"ones, fixed pattern",
+			[]WipePass{{Pattern: WipePatternOnes}, {Pattern: WipePatternFixed, Fixed: []byte{0xde, 0xad, 0xbe}}},
+			func(b []byte) { repeatFiller([]byte{0xde, 0xad, 0xbe})(b, 0) },
+		},
+		{
+			"zeros, random",
+			[]WipePass{{Pattern: WipePatternZeros}, {Pattern: WipePatternRandom}},
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			drive := createTestDrive(t)
+
+			data := make([]byte, size)
+			_, err := rand.Read(data)
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(drive.LogicalName, data, 0o600))
+
+			p := NewPatternWipeCmd(false, tc.passes...)
+			p.SetReadback(0)
+
+			logger, hook := test.NewNullLogger()
+			defer hook.Reset()
+
+			require.NoError(t, p.WipeDrive(context.Background(), logger, drive))
+
+			b, err := os.ReadFile(drive.LogicalName)
+			require.NoError(t, err)
+			require.Len(t, b, int(size))
+
+			if tc.expected != nil {
+				expected := make([]byte, size)
+				tc.expected(expected)
+				require.Equal(t, expected, b)
+			}
+		})
+	}
+}
+
+func Test_PatternWipeInvalidPass(t *testing.T) {
+	drive := createTestDrive(t)
+	logger, _ := test.NewNullLogger()
+
+	p := NewPatternWipeCmd(false, WipePass{Pattern: WipePatternFixed})
+	require.ErrorIs(t, p.WipeDrive(context.Background(), logger, drive), ErrPatternWipeFixedPattern)
+
+	p = NewPatternWipeCmd(false, WipePass{Pattern: WipePattern(9)})
+	require.ErrorIs(t, p.WipeDrive(context.Background(), logger, drive), ErrPatternWipeUnknown)
+}
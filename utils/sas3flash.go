@@ -0,0 +1,122 @@
+package utils
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"os"
+	"regexp"
+
+	common "github.com/metal-toolbox/bmc-common"
+	"github.com/pkg/errors"
+
+	"github.com/metal-toolbox/ironlib/model"
+)
+
+const EnvSas3flashUtility = "IRONLIB_UTIL_SAS3FLASH"
+
+var (
+	ErrSas3flashUpdate             = errors.New("sas3flash firmware update error")
+	ErrSas3flashControllerUnset    = errors.New("sas3flash controller not set")
+	ErrSas3flashControllerNotFound = errors.New("sas3flash controller not found")
+)
+
+// matches the controllers listed by sas3flash -listall - the controller index, and the PCI address as the last column
+// 0  SAS3008(C0)  16.00.01.00    0e.01.00.07    08.37.00.00     00:3d:00:00
+var sas3flashListAllController = regexp.MustCompile(`(?m)^\s*(\d+)\s+\S+.*\s([0-9a-fA-F]{2}:[0-9a-fA-F]{2}:[0-9a-fA-F]{2}:[0-9a-fA-F]{2})\s*$`)
+
+// Sas3flash wraps sas3flash to update the firmware of Broadcom/LSI SAS3 Fusion-MPT HBAs - SAS3008 IT mode controllers,
+// these controllers are not managed through storecli.
+type Sas3flash struct {
+	Executor Executor
+	// Controller is the sas3flash controller index the firmware is installed on.
+	Controller string
+	// DryRun is set to return the update commands with errs.UpdateDryRunError instead of executing them.
+	DryRun bool
+}
+
+// Return a new sas3flash executor
+func NewSas3flashCmd(trace bool) *Sas3flash {
+	// lookup env var for util
+	e := NewExecutor(cmp.Or(os.Getenv(EnvSas3flashUtility), "sas3flash"))
+	e.SetEnv([]string{"LC_ALL=C.UTF-8"})
+
+	if !trace {
+		e.SetQuiet()
+	}
+
+	return &Sas3flash{Executor: e}
+}
+
+// Attributes implements the actions.UtilAttributeGetter interface
+func (s *Sas3flash) Attributes() (utilName model.CollectorUtility, absolutePath string, err error) {
+	// Call CheckExecutable first so that the Executable CmdPath is resolved.
+	er := s.Executor.CheckExecutable()
+
+	return "sas3flash", s.Executor.CmdPath(), er
+}
+
+// SetController sets the controller index the firmware is installed on
+func (s *Sas3flash) SetController(controller string) {
+	s.Controller = controller
+}
+
+// ResolveController sets the index of the controller identified by its PCI bus address as listed by sas3flash -listall,
+// the controller serial is not listed by sas3flash. ErrSas3flashControllerNotFound is returned when none of the controllers match.
+func (s *Sas3flash) ResolveController(ctx context.Context, _, busInfo string) error {
+	busDeviceFunction := pciBusDeviceFunction(busInfo)
+	if busDeviceFunction == "" {
+		return errors.Wrap(ErrSas3flashControllerNotFound, "PCI bus address unknown: "+busInfo)
+	}
+
+	// sas3flash -listall
+	s.Executor.SetArgs("-listall")
+
+	result, err := s.Executor.Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, match := range sas3flashListAllController.FindAllSubmatch(result.Stdout, -1) {
+		if pciBusDeviceFunction(string(match[2])) == busDeviceFunction {
+			s.Controller = string(match[1])
+			return nil
+		}
+	}
+
+	return errors.Wrap(ErrSas3flashControllerNotFound, "bus: "+busInfo)
+}
+
+// SetDryRun sets the updater to return the update commands instead of executing them
+func (s *Sas3flash) SetDryRun(dryRun bool) {
+	s.DryRun = dryRun
+}
+
+// UpdateStorageController installs the controller firmware image on the controller set - see ResolveController,
+// ErrRebootRequired is returned once installed since the firmware is activated on the next host reboot,
+// ErrSas3flashControllerUnset is returned when the controller is not set.
+//
+// This method implements the actions.StorageControllerUpdater interface.
+func (s *Sas3flash) UpdateStorageController(ctx context.Context, updateFile, _ string) error {
+	// sas3flash -c 0 -f SAS9300_8i_IT.bin
+	if s.Controller == "" {
+		return ErrSas3flashControllerUnset
+	}
+
+	s.Executor.SetArgs("-c", s.Controller, "-f", updateFile)
+
+	if s.DryRun {
+		return updateDryRunError(s.Executor)
+	}
+
+	result, err := s.Executor.Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Contains(result.Stdout, []byte("Finished Processing Commands Successfully")) {
+		return errors.Wrap(ErrSas3flashUpdate, s.Executor.GetCmd()+": "+string(bytes.TrimSpace(result.Stdout)))
+	}
+
+	return rebootRequiredError(common.SlugStorageController)
+}
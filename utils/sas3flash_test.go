@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Sas3flashUpdateStorageController(t *testing.T) {
+	testcases := []struct {
+		name       string
+		controller string
+		stdout     string
+		wantCmd    string
+		wantErr    error
+	}{
+		{
+			"controller unset",
+			"",
+			"",
+			"sas3flash",
+			ErrSas3flashControllerUnset,
+		},
+		{
+			"controller set",
+			"1",
+			"\tFinished Processing Commands Successfully.\n\tExiting SAS3Flash.\n",
+			"sas3flash -c 1 -f /tmp/SAS9300_8i_IT.bin",
+			ErrRebootRequired,
+		},
+		{
+			"image invalid",
+			"0",
+			"\t\tERROR: Image Type is not valid for this controller!\n\n\tDue to error remaining commands will not be executed.\n\tUnable to Process Commands.\n\tExiting SAS3Flash.\n",
+			"sas3flash -c 0 -f /tmp/SAS9300_8i_IT.bin",
+			ErrSas3flashUpdate,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			sas3flash := &Sas3flash{Executor: NewFakeExecutor("sas3flash")}
+			sas3flash.SetController(tc.controller)
+			sas3flash.Executor.SetStdout([]byte(tc.stdout))
+
+			err := sas3flash.UpdateStorageController(context.TODO(), "/tmp/SAS9300_8i_IT.bin", "")
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.wantCmd, sas3flash.Executor.GetCmd())
+		})
+	}
+}
+
+func Test_Sas3flashResolveController(t *testing.T) {
+	b, err := os.ReadFile("../fixtures/utils/sas3flash/listall")
+	require.NoError(t, err)
+
+	testcases := []struct {
+		name    string
+		busInfo string
+		want    string
+		wantErr error
+	}{
+		{"lshw bus info", "pci@0000:3d:00.0", "1", nil},
+		{"storecli PCI address", "00:3b:00:00", "0", nil},
+		{"controller not listed", "pci@0000:5e:00.0", "", ErrSas3flashControllerNotFound},
+		{"bus info unknown", "", "", ErrSas3flashControllerNotFound},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			sas3flash := &Sas3flash{Executor: NewFakeExecutor("sas3flash")}
+			sas3flash.Executor.SetStdout(b)
+
+			err := sas3flash.ResolveController(context.TODO(), "500304801c71e8d0", tc.busInfo)
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.want, sas3flash.Controller)
+		})
+	}
+}
+
+func Test_pciBusDeviceFunction(t *testing.T) {
+	assert.Equal(t, "3d:00.0", pciBusDeviceFunction("pci@0000:3d:00.0"))
+	assert.Equal(t, "3d:00.0", pciBusDeviceFunction("00:3d:00:00"))
+	assert.Equal(t, "65:00.1", pciBusDeviceFunction("00:65:00:01"))
+	assert.Equal(t, "", pciBusDeviceFunction("1000:0097"))
+	assert.Equal(t, "", pciBusDeviceFunction(""))
+}
@@ -0,0 +1,337 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	common "github.com/metal-toolbox/bmc-common"
+	"github.com/metal-toolbox/ironlib/model"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	EnvSgInqUtility      = "IRONLIB_UTIL_SG_INQ"
+	EnvSgOpcodesUtility  = "IRONLIB_UTIL_SG_OPCODES"
+	EnvSgSanitizeUtility = "IRONLIB_UTIL_SG_SANITIZE"
+	EnvSgFormatUtility   = "IRONLIB_UTIL_SG_FORMAT"
+	EnvSgRequestsUtility = "IRONLIB_UTIL_SG_REQUESTS"
+)
+
+// SCSI operation codes - SPC/SBC
+const (
+	scsiOpcodeFormatUnit = "04"
+	scsiOpcodeSanitize   = "48"
+)
+
+// SCSI SANITIZE service actions - SBC-4
+const (
+	scsiSanitizeOverwrite   = "1"
+	scsiSanitizeBlockErase  = "2"
+	scsiSanitizeCryptoErase = "3"
+)
+
+// FastFormat is the SCSI FORMAT UNIT FFMT field value - SBC-4
+//
+//go:generate stringer -type FastFormat
+type FastFormat uint8
+
+const (
+	// FastFormatNone formats the medium, the logical blocks are initialized as part of the format.
+	FastFormatNone FastFormat = iota
+	// FastFormatNoOverwrite formats without overwriting the medium, reading a logical block returns the initialization pattern.
+	FastFormatNoOverwrite
+	// FastFormatReadError formats without overwriting the medium, reading a logical block returns an error until it is written.
+	FastFormatReadError
+)
+
+// sgRequestsPollInterval is the interval the sanitize and format progress is polled at
+var sgRequestsPollInterval = 5 * time.Second
+
+// Sg3Utils wraps the sg3_utils utilities to collect SAS/SCSI drive capabilities and wipe SAS/SCSI drives,
+// each of the utilities is a separate binary and so has its own executor.
+type Sg3Utils struct {
+	InqExecutor      Executor
+	OpcodesExecutor  Executor
+	SanitizeExecutor Executor
+	FormatExecutor   Executor
+	RequestsExecutor Executor
+}
+
+// Return a new sg3_utils executor
+func NewSg3UtilsCmd(trace bool) *Sg3Utils {
+	return &Sg3Utils{
+		InqExecutor:      newSgExecutor(EnvSgInqUtility, "sg_inq", trace),
+		OpcodesExecutor:  newSgExecutor(EnvSgOpcodesUtility, "sg_opcodes", trace),
+		SanitizeExecutor: newSgExecutor(EnvSgSanitizeUtility, "sg_sanitize", trace),
+		FormatExecutor:   newSgExecutor(EnvSgFormatUtility, "sg_format", trace),
+		RequestsExecutor: newSgExecutor(EnvSgRequestsUtility, "sg_requests", trace),
+	}
+}
+
+func newSgExecutor(envVar, utility string, trace bool) Executor {
+	// lookup env var for util
+	e := NewExecutor(cmp.Or(os.Getenv(envVar), utility))
+	e.SetEnv([]string{"LC_ALL=C.UTF-8"})
+
+	if !trace {
+		e.SetQuiet()
+	}
+
+	return e
+}
+
+// Attributes implements the actions.UtilAttributeGetter interface
+//
+// The path returned is the path to sg_inq since it is the first of the utilities executed.
+func (s *Sg3Utils) Attributes() (utilName model.CollectorUtility, absolutePath string, err error) {
+	// Call CheckExecutable first so that the Executable CmdPath is resolved.
+	er := s.InqExecutor.CheckExecutable()
+
+	return "sg3_utils", s.InqExecutor.CmdPath(), er
+}
+
+// DriveCapabilities returns the drive capability attributes obtained through sg_inq and sg_opcodes
+//
+// The logicalName is the kernel/OS assigned drive name - /dev/sdX
+//
+// This method implements the actions.DriveCapabilityCollector interface.
+func (s *Sg3Utils) DriveCapabilities(ctx context.Context, logicalName string) ([]*common.Capability, error) {
+	// sg_inq devicepath
+	s.InqExecutor.SetArgs(logicalName)
+
+	result, err := s.InqExecutor.Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	capabilities := parseSgInqCapabilities(result.Stdout)
+
+	// sg_opcodes devicepath
+	s.OpcodesExecutor.SetArgs(logicalName)
+
+	result, err = s.OpcodesExecutor.Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(capabilities, parseSgOpcodesCapabilities(result.Stdout)...), nil
+}
+
+// parseSgInqCapabilities returns the capabilities reported in the sg_inq standard INQUIRY flags
+func parseSgInqCapabilities(out []byte) []*common.Capability {
+	flags := map[string]string{}
+
+	// the flags are listed as Name=value separated by spaces, optional flags are enclosed in brackets
+	for _, field := range strings.Fields(string(out)) {
+		name, value, found := strings.Cut(strings.Trim(field, "[]"), "=")
+		if found {
+			flags[name] = value
+		}
+	}
+
+	return []*common.Capability{
+		{Name: "protect", Description: "Protection Information Supported", Enabled: flags["Protect"] == "1"},
+		{Name: "3pc", Description: "Third Party Copy Supported", Enabled: flags["3PC"] == "1"},
+		{Name: "cmdque", Description: "Command Queuing Supported", Enabled: flags["CmdQue"] == "1"},
+	}
+}
+
+// parseSgOpcodesCapabilities returns the sanitize and format capabilities based on the commands listed by sg_opcodes
+func parseSgOpcodesCapabilities(out []byte) []*common.Capability {
+	var owr, ber, cer, fmtu bool
+
+	// Opcode  Service    CDB    Name
+	// (hex)   action(h)  size
+	// -----------------------------------------------
+	//  04                 6    Format Unit
+	//  48        1       10    Sanitize, overwrite
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 {
+			continue
+		}
+
+		switch strings.ToLower(fields[0]) {
+		case scsiOpcodeFormatUnit:
+			fmtu = true
+		case scsiOpcodeSanitize:
+			switch fields[1] {
+			case scsiSanitizeOverwrite:
+				owr = true
+			case scsiSanitizeBlockErase:
+				ber = true
+			case scsiSanitizeCryptoErase:
+				cer = true
+			}
+		}
+	}
+
+	return []*common.Capability{
+		{Name: "owr", Description: "Overwrite Sanitize Operation Supported", Enabled: owr},
+		{Name: "ber", Description: "Block Erase Sanitize Operation Supported", Enabled: ber},
+		{Name: "cer", Description: "Crypto Erase Sanitize Operation Supported", Enabled: cer},
+		{Name: "fmtu", Description: "Format Unit Supported", Enabled: fmtu},
+	}
+}
+
+// WipeDrive implements DriveWiper by running sg_sanitize or sg_format as appropriate.
+// Sanitize(CryptoErase) is preferred over Sanitize(BlockErase) which is preferred over Sanitize(Overwrite),
+// Format is used when the drive does not support any of the sanitize operations.
+func (s *Sg3Utils) WipeDrive(ctx context.Context, logger *logrus.Logger, drive *common.Drive) error {
+	var owr, ber, cer, fmtu bool
+	for _, cap := range drive.Capabilities {
+		switch cap.Name {
+		case "owr":
+			owr = cap.Enabled
+		case "ber":
+			ber = cap.Enabled
+		case "cer":
+			cer = cap.Enabled
+		case "fmtu":
+			fmtu = cap.Enabled
+		}
+	}
+
+	l := logger.WithField("drive", drive.LogicalName)
+
+	sanitizeActions := []struct {
+		supported bool
+		action    SanitizeAction
+		method    string
+	}{
+		{cer, CryptoErase, "sanitize-crypto-erase"},
+		{ber, BlockErase, "sanitize-block-erase"},
+		{owr, Overwrite, "sanitize-overwrite"},
+	}
+
+	for _, sanitize := range sanitizeActions {
+		if !sanitize.supported {
+			continue
+		}
+
+		// nolint:govet
+		l := l.WithField("method", "sanitize").WithField("action", sanitize.action)
+		l.Debug("wiping")
+		err := attemptWipeMethod(ctx, sanitize.method, func() error { return s.Sanitize(ctx, drive, sanitize.action) })
+		if err == nil {
+			return nil
+		}
+		l.WithError(err).Error("failed")
+	}
+
+	if fmtu {
+		l = l.WithField("method", "format").WithField("ffmt", FastFormatNone)
+		l.Debug("wiping")
+		err := attemptWipeMethod(ctx, "format-unit", func() error { return s.Format(ctx, drive, FastFormatNone) })
+		if err == nil {
+			return nil
+		}
+		l.WithError(err).Error("failed")
+	}
+
+	return ErrIneffectiveWipe
+}
+
+// Sanitize wipes the drive using SCSI SANITIZE via sg_sanitize and polls the progress through sg_requests
+func (s *Sg3Utils) Sanitize(ctx context.Context, drive *common.Drive, sanact SanitizeAction) error {
+	var action []string
+	switch sanact { // nolint:exhaustive
+	case CryptoErase:
+		action = []string{"--crypto"}
+	case BlockErase:
+		action = []string{"--block"}
+	case Overwrite:
+		action = []string{"--overwrite", "--zero"}
+	default:
+		return fmt.Errorf("%w: %v", errSanitizeInvalidAction, sanact)
+	}
+
+	verify, err := ApplyWatermarks(drive)
+	if err != nil {
+		return err
+	}
+
+	// sg_sanitize --quick --early --crypto devicepath
+	args := append([]string{"--quick", "--early"}, action...)
+	s.SanitizeExecutor.SetArgs(append(args, drive.LogicalName)...)
+
+	if _, err := s.SanitizeExecutor.Exec(ctx); err != nil {
+		return err
+	}
+
+	if err := s.waitProgress(ctx, drive.LogicalName); err != nil {
+		return err
+	}
+
+	return verifyWipe(ctx, wipeVerificationWatermarks, verify)
+}
+
+// Format wipes the drive using SCSI FORMAT UNIT via sg_format with the given FFMT value
+// and polls the progress through sg_requests
+func (s *Sg3Utils) Format(ctx context.Context, drive *common.Drive, ffmt FastFormat) error {
+	switch ffmt {
+	case FastFormatNone, FastFormatNoOverwrite, FastFormatReadError:
+	default:
+		return fmt.Errorf("%w: %v", errFormatInvalidSetting, ffmt)
+	}
+
+	verify, err := ApplyWatermarks(drive)
+	if err != nil {
+		return err
+	}
+
+	// sg_format --format --quick --early --ffmt=0 devicepath
+	s.FormatExecutor.SetArgs("--format", "--quick", "--early", "--ffmt="+strconv.Itoa(int(ffmt)), drive.LogicalName)
+
+	if _, err := s.FormatExecutor.Exec(ctx); err != nil {
+		return err
+	}
+
+	if err := s.waitProgress(ctx, drive.LogicalName); err != nil {
+		return err
+	}
+
+	return verifyWipe(ctx, wipeVerificationWatermarks, verify)
+}
+
+// waitProgress polls sg_requests until the drive no longer reports a progress indication
+func (s *Sg3Utils) waitProgress(ctx context.Context, logicalName string) error {
+	for {
+		// sg_requests --progress devicepath
+		s.RequestsExecutor.SetArgs("--progress", logicalName)
+
+		result, err := s.RequestsExecutor.Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		if !bytes.Contains(result.Stdout, []byte("Progress indication:")) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(sgRequestsPollInterval):
+		}
+	}
+}
+
+// NewFakeSg3Utils returns a mock sg3_utils collector that returns mock data for use in tests.
+func NewFakeSg3Utils() *Sg3Utils {
+	return &Sg3Utils{
+		InqExecutor:      NewFakeExecutor("sg_inq"),
+		OpcodesExecutor:  NewFakeExecutor("sg_opcodes"),
+		SanitizeExecutor: NewFakeExecutor("sg_sanitize"),
+		FormatExecutor:   NewFakeExecutor("sg_format"),
+		RequestsExecutor: NewFakeExecutor("sg_requests"),
+	}
+}
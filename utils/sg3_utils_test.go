@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	common "github.com/metal-toolbox/bmc-common"
+	tlogrus "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fakeSASDrive(t *testing.T) *common.Drive {
+	dir := t.TempDir()
+	f, err := os.Create(dir + "/sdd")
+	require.NoError(t, err)
+	require.NoError(t, f.Truncate(20*1024))
+	require.NoError(t, f.Close())
+
+	return &common.Drive{Common: common.Common{LogicalName: f.Name()}, Protocol: "sas"}
+}
+
+func Test_Sg3UtilsDriveCapabilities(t *testing.T) {
+	s := NewFakeSg3Utils()
+
+	capabilities, err := s.DriveCapabilities(context.Background(), "/dev/sdd")
+	require.NoError(t, err)
+
+	expected := []*common.Capability{
+		{Name: "protect", Description: "Protection Information Supported", Enabled: true},
+		{Name: "3pc", Description: "Third Party Copy Supported", Enabled: true},
+		{Name: "cmdque", Description: "Command Queuing Supported", Enabled: true},
+		{Name: "owr", Description: "Overwrite Sanitize Operation Supported", Enabled: true},
+		{Name: "ber", Description: "Block Erase Sanitize Operation Supported", Enabled: true},
+		{Name: "cer", Description: "Crypto Erase Sanitize Operation Supported", Enabled: false},
+		{Name: "fmtu", Description: "Format Unit Supported", Enabled: true},
+	}
+
+	assert.Equal(t, expected, capabilities)
+}
+
+func Test_Sg3UtilsSanitize(t *testing.T) {
+	for action := range CryptoErase + 1 {
+		t.Run(action.String(), func(t *testing.T) {
+			s := NewFakeSg3Utils()
+			dev := fakeSASDrive(t)
+			err := s.Sanitize(context.Background(), dev, action)
+
+			switch action { // nolint:exhaustive
+			case CryptoErase:
+				require.NoError(t, err)
+				require.Equal(t, []string{"--quick", "--early", "--crypto", dev.LogicalName}, s.SanitizeExecutor.(*FakeExecute).Args)
+			case BlockErase:
+				require.NoError(t, err)
+				require.Equal(t, []string{"--quick", "--early", "--block", dev.LogicalName}, s.SanitizeExecutor.(*FakeExecute).Args)
+			case Overwrite:
+				require.NoError(t, err)
+				require.Equal(t, []string{"--quick", "--early", "--overwrite", "--zero", dev.LogicalName}, s.SanitizeExecutor.(*FakeExecute).Args)
+			default:
+				require.ErrorIs(t, err, errSanitizeInvalidAction)
+			}
+		})
+	}
+}
+
+func Test_Sg3UtilsFormat(t *testing.T) {
+	s := NewFakeSg3Utils()
+	dev := fakeSASDrive(t)
+
+	require.NoError(t, s.Format(context.Background(), dev, FastFormatNoOverwrite))
+	require.Equal(t, []string{"--format", "--quick", "--early", "--ffmt=1", dev.LogicalName}, s.FormatExecutor.(*FakeExecute).Args)
+	require.Equal(t, []string{"--progress", dev.LogicalName}, s.RequestsExecutor.(*FakeExecute).Args)
+
+	require.ErrorIs(t, s.Format(context.Background(), dev, FastFormat(3)), errFormatInvalidSetting)
+}
+
+func Test_Sg3UtilsWipeDrive(t *testing.T) {
+	tests := []struct {
+		name     string
+		caps     map[string]bool
+		sanitize []string
+		format   []string
+	}{
+		{"crypto erase preferred", map[string]bool{"cer": true, "ber": true, "owr": true, "fmtu": true}, []string{"--quick", "--early", "--crypto"}, nil},
+		{"block erase", map[string]bool{"ber": true, "owr": true, "fmtu": true}, []string{"--quick", "--early", "--block"}, nil},
+		{"overwrite", map[string]bool{"owr": true, "fmtu": true}, []string{"--quick", "--early", "--overwrite", "--zero"}, nil},
+		{"format", map[string]bool{"fmtu": true}, nil, []string{"--format", "--quick", "--early", "--ffmt=0"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewFakeSg3Utils()
+			dev := fakeSASDrive(t)
+
+			for name, enabled := range test.caps {
+				dev.Capabilities = append(dev.Capabilities, &common.Capability{Name: name, Enabled: enabled})
+			}
+
+			logger, hook := tlogrus.NewNullLogger()
+			defer hook.Reset()
+
+			require.NoError(t, s.WipeDrive(context.Background(), logger, dev))
+
+			if test.sanitize != nil {
+				assert.Equal(t, append(test.sanitize, dev.LogicalName), s.SanitizeExecutor.(*FakeExecute).Args)
+			}
+
+			if test.format != nil {
+				assert.Nil(t, s.SanitizeExecutor.(*FakeExecute).Args)
+				assert.Equal(t, append(test.format, dev.LogicalName), s.FormatExecutor.(*FakeExecute).Args)
+			}
+		})
+	}
+
+	t.Run("no supported method", func(t *testing.T) {
+		logger, _ := tlogrus.NewNullLogger()
+		require.ErrorIs(t, NewFakeSg3Utils().WipeDrive(context.Background(), logger, fakeSASDrive(t)), ErrIneffectiveWipe)
+	})
+}
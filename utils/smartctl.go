@@ -8,6 +8,8 @@ import (
 	"io"
 	"os"
 	"path"
+	"slices"
+	"strconv"
 	"strings"
 
 	common "github.com/metal-toolbox/bmc-common"
@@ -41,6 +43,8 @@ type SmartctlDriveAttributes struct {
 	FirmwareVersion string          `json:"firmware_version"`
 	Status          *SmartctlStatus `json:"smart_status"`
 	Errors          []string        `json:"-"`
+	// Health is the drive health metrics keyed by the metadata keys defined in model/drive_health.go
+	Health map[string]string `json:"-"`
 }
 
 type SmartctlScan struct {
@@ -57,6 +61,51 @@ type SmartctlStatus struct {
 	Passed bool `json:"passed"`
 }
 
+// smartctlHealth is the drive health information included in the smartctl -a output
+type smartctlHealth struct {
+	Temperature *struct {
+		Current int `json:"current"`
+	} `json:"temperature"`
+	PowerOnTime *struct {
+		Hours int64 `json:"hours"`
+	} `json:"power_on_time"`
+	ATAAttributes *struct {
+		Table []*smartctlATAAttribute `json:"table"`
+	} `json:"ata_smart_attributes"`
+	NvmeHealthLog *struct {
+		CriticalWarning int   `json:"critical_warning"`
+		AvailableSpare  int   `json:"available_spare"`
+		PercentageUsed  int   `json:"percentage_used"`
+		MediaErrors     int64 `json:"media_errors"`
+	} `json:"nvme_smart_health_information_log"`
+	GrownDefects *int64 `json:"scsi_grown_defect_list"`
+}
+
+type smartctlATAAttribute struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Value int    `json:"value"`
+	Raw   struct {
+		Value int64 `json:"value"`
+	} `json:"raw"`
+}
+
+// ATA attribute identifiers for the drive health metrics
+const (
+	ataAttributeReallocatedSectors   = 5
+	ataAttributePendingSectors       = 197
+	ataAttributeUncorrectableSectors = 198
+)
+
+// ATA attribute names reporting the SSD wear, these are vendor specific and so identified by name.
+var ataWearAttributes = []string{
+	"Wear_Leveling_Count",
+	"SSD_Life_Left",
+	"Media_Wearout_Indicator",
+	"Percent_Lifetime_Remain",
+	"Percent_Life_Remaining",
+}
+
 // Return a new smartctl executor
 func NewSmartctlCmd(trace bool) *Smartctl {
 	utility := "smartctl"
@@ -140,6 +189,10 @@ func (s *Smartctl) Drives(ctx context.Context) ([]*common.Drive, error) {
 			item.SmartErrors = smartctlAll.Errors
 		}
 
+		if len(smartctlAll.Health) > 0 {
+			item.Metadata = smartctlAll.Health
+		}
+
 		drives = append(drives, item)
 	}
 
@@ -195,9 +248,62 @@ func (s *Smartctl) All(ctx context.Context, device string) (*SmartctlDriveAttrib
 		deviceAttributes.Errors = smartCtlErrs
 	}
 
+	health := &smartctlHealth{}
+
+	err = json.Unmarshal(result.Stdout, health)
+	if err != nil {
+		return nil, err
+	}
+
+	if metadata := health.metadata(); len(metadata) > 0 {
+		deviceAttributes.Health = metadata
+	}
+
 	return deviceAttributes, nil
 }
 
+// metadata returns the drive health metrics as common.Drive metadata,
+// the metadata keys are defined in model/drive_health.go
+func (a *smartctlHealth) metadata() map[string]string {
+	metadata := map[string]string{}
+
+	if a.Temperature != nil {
+		metadata[model.DriveHealthTemperature] = strconv.Itoa(a.Temperature.Current)
+	}
+
+	if a.PowerOnTime != nil {
+		metadata[model.DriveHealthPowerOnHours] = strconv.FormatInt(a.PowerOnTime.Hours, 10)
+	}
+
+	if a.ATAAttributes != nil {
+		for _, attr := range a.ATAAttributes.Table {
+			switch {
+			case attr.ID == ataAttributeReallocatedSectors:
+				metadata[model.DriveHealthReallocatedSectors] = strconv.FormatInt(attr.Raw.Value, 10)
+			case attr.ID == ataAttributePendingSectors:
+				metadata[model.DriveHealthPendingSectors] = strconv.FormatInt(attr.Raw.Value, 10)
+			case attr.ID == ataAttributeUncorrectableSectors:
+				metadata[model.DriveHealthUncorrectableSectors] = strconv.FormatInt(attr.Raw.Value, 10)
+			case slices.Contains(ataWearAttributes, attr.Name):
+				metadata[model.DriveHealthWearLeveling] = strconv.Itoa(attr.Value)
+			}
+		}
+	}
+
+	if a.NvmeHealthLog != nil {
+		metadata[model.DriveHealthPercentageUsed] = strconv.Itoa(a.NvmeHealthLog.PercentageUsed)
+		metadata[model.DriveHealthMediaErrors] = strconv.FormatInt(a.NvmeHealthLog.MediaErrors, 10)
+		metadata[model.DriveHealthCriticalWarning] = strconv.Itoa(a.NvmeHealthLog.CriticalWarning)
+		metadata[model.DriveHealthAvailableSpare] = strconv.Itoa(a.NvmeHealthLog.AvailableSpare)
+	}
+
+	if a.GrownDefects != nil {
+		metadata[model.DriveHealthGrownDefects] = strconv.FormatInt(*a.GrownDefects, 10)
+	}
+
+	return metadata
+}
+
 // smartCtlExitStatus identifies the error bits in the smartctl exitcode
 // and returns a slice of strings containing one or more errors identified - if any.
 // an empty slice is returned if there are no errors
@@ -310,6 +416,24 @@ func (e *FakeSmartctlExecute) Exec(context.Context) (*Result, error) {
 		}
 
 		e.Stdout = b
+	case "-c":
+		// -c -l selftest /dev/sdg -j
+		argLength := 5
+		if len(e.Args) < argLength {
+			return nil, ErrFakeExecutorInvalidArgs
+		}
+
+		driveName := path.Base(e.Args[3])
+		f := fmt.Sprintf("%s/%s-selftest.json", e.JSONFilesDir, driveName)
+
+		b, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+
+		e.Stdout = b
+	case "-t", "-X":
+		e.Stdout = []byte("{}")
 	}
 
 	return &Result{Stdout: e.Stdout, Stderr: e.Stderr, ExitCode: e.ExitCode}, nil
@@ -337,3 +461,10 @@ func (e *FakeSmartctlExecute) SetExitCode(i int) {
 func (e *FakeSmartctlExecute) CmdPath() string {
 	return e.Cmd
 }
+
+func (e *FakeSmartctlExecute) GetCmd() string {
+	cmd := []string{e.Cmd}
+	cmd = append(cmd, e.Args...)
+
+	return strings.Join(cmd, " ")
+}
@@ -0,0 +1,190 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/metal-toolbox/ironlib/model"
+	"github.com/pkg/errors"
+)
+
+var ErrSmartctlSelfTest = errors.New("smartctl self-test error")
+
+// smartctl exit code bits indicating the command itself failed - man 8 smartctl
+const smartctlCommandFailedBits = 0b111
+
+// ATA self-test execution status values, the upper nibble of the status value - ATA/ATAPI Command Set
+const (
+	ataSelfTestStatusPassed     = 0x0
+	ataSelfTestStatusInProgress = 0xf
+)
+
+// SCSI self-test result values - SPC-4 self-test results log parameter
+const (
+	scsiSelfTestResultPassed     = 0x0
+	scsiSelfTestResultInProgress = 0xf
+)
+
+// smartctlSelfTest is the self-test information included in the smartctl -c -l selftest output
+type smartctlSelfTest struct {
+	ATASmartData *struct {
+		SelfTest *struct {
+			Status smartctlSelfTestStatus `json:"status"`
+		} `json:"self_test"`
+	} `json:"ata_smart_data"`
+	ATASelfTestLog *struct {
+		Standard *struct {
+			Table []*smartctlATASelfTestEntry `json:"table"`
+			Count int                         `json:"count"`
+		} `json:"standard"`
+	} `json:"ata_smart_self_test_log"`
+	// SCSI drives report the most recent self-test as scsi_self_test_0
+	SCSISelfTest *smartctlSCSISelfTestEntry `json:"scsi_self_test_0"`
+}
+
+type smartctlSelfTestStatus struct {
+	Value            int    `json:"value"`
+	String           string `json:"string"`
+	RemainingPercent int    `json:"remaining_percent"`
+}
+
+type smartctlATASelfTestEntry struct {
+	Type struct {
+		String string `json:"string"`
+	} `json:"type"`
+	Status        smartctlSelfTestStatus `json:"status"`
+	LifetimeHours int64                  `json:"lifetime_hours"`
+	LBA           *uint64                `json:"lba"`
+}
+
+type smartctlSCSISelfTestEntry struct {
+	Code struct {
+		String string `json:"string"`
+	} `json:"code"`
+	Result struct {
+		Value  int    `json:"value"`
+		String string `json:"string"`
+	} `json:"result"`
+	PowerOnTime struct {
+		Hours int64 `json:"hours"`
+	} `json:"power_on_time"`
+	LBAFirstFailure *struct {
+		Value uint64 `json:"value"`
+	} `json:"lba_first_failure"`
+}
+
+// StartSelfTest runs smartctl -t to start a self-test on the drive, the self-test runs in the background on the drive.
+func (s *Smartctl) StartSelfTest(ctx context.Context, logicalName string, testType model.DriveSelfTestType) error {
+	var test string
+
+	switch testType {
+	case model.DriveSelfTestShort:
+		test = "short"
+	case model.DriveSelfTestExtended:
+		test = "long"
+	default:
+		return errors.Wrap(ErrSmartctlSelfTest, "unsupported self-test type: "+string(testType))
+	}
+
+	// smartctl -t short /dev/sda -j
+	s.Executor.SetArgs("-t", test, logicalName, "-j")
+
+	return s.execSelfTestCmd(ctx)
+}
+
+// AbortSelfTest runs smartctl -X to abort the self-test running on the drive.
+func (s *Smartctl) AbortSelfTest(ctx context.Context, logicalName string) error {
+	// smartctl -X /dev/sda -j
+	s.Executor.SetArgs("-X", logicalName, "-j")
+
+	return s.execSelfTestCmd(ctx)
+}
+
+// execSelfTestCmd executes the smartctl command set on the executor,
+// smartctl exits with a non-zero status based on the drive smart data, only the bits indicating the command failed are checked.
+func (s *Smartctl) execSelfTestCmd(ctx context.Context) error {
+	result, err := s.Executor.Exec(ctx)
+	if result == nil {
+		return errors.Wrap(ErrSmartctlSelfTest, err.Error())
+	}
+
+	if result.ExitCode&smartctlCommandFailedBits != 0 {
+		msg := strings.Join(smartCtlExitStatus(result.ExitCode&smartctlCommandFailedBits), ", ")
+		return errors.Wrap(ErrSmartctlSelfTest, s.Executor.GetCmd()+": "+msg)
+	}
+
+	return nil
+}
+
+// SelfTestStatus runs smartctl -c -l selftest and returns the drive self-test status.
+func (s *Smartctl) SelfTestStatus(ctx context.Context, logicalName string) (*model.DriveSelfTestStatus, error) {
+	// smartctl -c -l selftest /dev/sda -j
+	s.Executor.SetArgs("-c", "-l", "selftest", logicalName, "-j")
+
+	// smartctl can exit with a non-zero status based on drive smart data
+	result, err := s.Executor.Exec(ctx)
+	if result == nil {
+		return nil, errors.Wrap(ErrSmartctlSelfTest, err.Error())
+	}
+
+	if len(result.Stdout) == 0 {
+		return nil, errors.Wrap(ErrNoCommandOutput, s.Executor.GetCmd())
+	}
+
+	selfTest := &smartctlSelfTest{}
+	if err := json.Unmarshal(result.Stdout, selfTest); err != nil {
+		return nil, err
+	}
+
+	return selfTest.status(), nil
+}
+
+// status returns the ATA or SCSI self-test information as a model.DriveSelfTestStatus
+func (t *smartctlSelfTest) status() *model.DriveSelfTestStatus {
+	status := &model.DriveSelfTestStatus{}
+
+	if t.ATASmartData != nil && t.ATASmartData.SelfTest != nil &&
+		t.ATASmartData.SelfTest.Status.Value>>4 == ataSelfTestStatusInProgress {
+		status.InProgress = true
+		status.RemainingPercent = t.ATASmartData.SelfTest.Status.RemainingPercent
+	}
+
+	if t.ATASelfTestLog != nil && t.ATASelfTestLog.Standard != nil && len(t.ATASelfTestLog.Standard.Table) > 0 {
+		// the most recent entry is listed first
+		entry := t.ATASelfTestLog.Standard.Table[0]
+
+		status.LogEntries = max(t.ATASelfTestLog.Standard.Count, len(t.ATASelfTestLog.Standard.Table))
+
+		status.Latest = &model.DriveSelfTestLogEntry{
+			Type:         entry.Type.String,
+			Status:       entry.Status.String,
+			Passed:       entry.Status.Value>>4 == ataSelfTestStatusPassed,
+			PowerOnHours: entry.LifetimeHours,
+			FailingLBA:   entry.LBA,
+		}
+	}
+
+	if t.SCSISelfTest != nil {
+		entry := t.SCSISelfTest
+
+		if entry.Result.Value == scsiSelfTestResultInProgress {
+			status.InProgress = true
+			return status
+		}
+
+		status.Latest = &model.DriveSelfTestLogEntry{
+			Type:         entry.Code.String,
+			Status:       entry.Result.String,
+			Passed:       entry.Result.Value == scsiSelfTestResultPassed,
+			PowerOnHours: entry.PowerOnTime.Hours,
+		}
+
+		if entry.LBAFirstFailure != nil {
+			lba := entry.LBAFirstFailure.Value
+			status.Latest.FailingLBA = &lba
+		}
+	}
+
+	return status
+}
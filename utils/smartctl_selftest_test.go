@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metal-toolbox/ironlib/model"
+)
+
+func Test_SmartctlSelfTestStatus(t *testing.T) {
+	lba := uint64(45613232)
+
+	testcases := []struct {
+		name     string
+		drive    string
+		expected *model.DriveSelfTestStatus
+	}{
+		{
+			"ata in progress with failed entry",
+			"/dev/sda",
+			&model.DriveSelfTestStatus{
+				InProgress:       true,
+				RemainingPercent: 90,
+				Latest: &model.DriveSelfTestLogEntry{
+					Type:         "Extended offline",
+					Status:       "Completed: read failure",
+					PowerOnHours: 3320,
+					FailingLBA:   &lba,
+				},
+				LogEntries: 2,
+			},
+		},
+		{
+			"scsi completed",
+			"/dev/sdd",
+			&model.DriveSelfTestStatus{
+				Latest: &model.DriveSelfTestLogEntry{
+					Type:         "Background short",
+					Status:       "Completed",
+					Passed:       true,
+					PowerOnHours: 26410,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newFakeSmartctl()
+
+			status, err := s.SelfTestStatus(context.Background(), tc.drive)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, status)
+		})
+	}
+}
+
+func Test_SmartctlStartSelfTest(t *testing.T) {
+	s := newFakeSmartctl()
+
+	err := s.StartSelfTest(context.Background(), "/dev/sda", model.DriveSelfTestExtended)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"-t", "long", "/dev/sda", "-j"}, s.Executor.(*FakeSmartctlExecute).Args)
+
+	// smartctl exit status bits for the drive health are not command errors
+	s.Executor.SetExitCode(64)
+	require.NoError(t, s.StartSelfTest(context.Background(), "/dev/sda", model.DriveSelfTestShort))
+
+	s.Executor.SetExitCode(4)
+	assert.ErrorIs(t, s.StartSelfTest(context.Background(), "/dev/sda", model.DriveSelfTestShort), ErrSmartctlSelfTest)
+
+	assert.ErrorIs(t, s.StartSelfTest(context.Background(), "/dev/sda", "conveyance"), ErrSmartctlSelfTest)
+}
@@ -6,6 +6,9 @@ import (
 
 	common "github.com/metal-toolbox/bmc-common"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metal-toolbox/ironlib/model"
 )
 
 func newFakeSmartctl() *Smartctl {
@@ -14,6 +17,35 @@ func newFakeSmartctl() *Smartctl {
 	}
 }
 
+// ataHealth returns the health metadata expected for the ATA drive fixtures with no sector errors
+func ataHealth(powerOnHours, temperature, wearLeveling string) map[string]string {
+	health := map[string]string{
+		model.DriveHealthReallocatedSectors:   "0",
+		model.DriveHealthPendingSectors:       "0",
+		model.DriveHealthUncorrectableSectors: "0",
+		model.DriveHealthPowerOnHours:         powerOnHours,
+		model.DriveHealthTemperature:          temperature,
+	}
+
+	if wearLeveling != "" {
+		health[model.DriveHealthWearLeveling] = wearLeveling
+	}
+
+	return health
+}
+
+// nvmeHealth returns the health metadata expected for the NVMe drive fixtures
+func nvmeHealth(powerOnHours, temperature string) map[string]string {
+	return map[string]string{
+		model.DriveHealthPercentageUsed:  "0",
+		model.DriveHealthMediaErrors:     "0",
+		model.DriveHealthCriticalWarning: "0",
+		model.DriveHealthAvailableSpare:  "100",
+		model.DriveHealthPowerOnHours:    powerOnHours,
+		model.DriveHealthTemperature:     temperature,
+	}
+}
+
 func Test_SmartctlScan(t *testing.T) {
 	expected := &SmartctlScan{
 		Drives: []*SmartctlDrive{
@@ -36,7 +68,7 @@ func Test_SmartctlScan(t *testing.T) {
 }
 
 func Test_SmartctlAllSCSI(t *testing.T) {
-	expected := &SmartctlDriveAttributes{ModelName: "Micron_5200_MTFDDAK960TDN", ModelFamily: "Micron 5100 Pro / 5200 SSDs", SerialNumber: "2013273A99BD", FirmwareVersion: "D1MU020", Status: &SmartctlStatus{Passed: true}}
+	expected := &SmartctlDriveAttributes{ModelName: "Micron_5200_MTFDDAK960TDN", ModelFamily: "Micron 5100 Pro / 5200 SSDs", SerialNumber: "2013273A99BD", FirmwareVersion: "D1MU020", Status: &SmartctlStatus{Passed: true}, Health: ataHealth("3410", "36", "100")}
 	s := newFakeSmartctl()
 
 	results, err := s.All(context.Background(), "/dev/sda")
@@ -48,7 +80,7 @@ func Test_SmartctlAllSCSI(t *testing.T) {
 }
 
 func Test_SmartctlAllNVME(t *testing.T) {
-	expected := &SmartctlDriveAttributes{ModelName: "KXG60ZNV256G TOSHIBA", SerialNumber: "Z9DF70I8FY3L", FirmwareVersion: "AGGA4104", Status: &SmartctlStatus{Passed: true}}
+	expected := &SmartctlDriveAttributes{ModelName: "KXG60ZNV256G TOSHIBA", SerialNumber: "Z9DF70I8FY3L", FirmwareVersion: "AGGA4104", Status: &SmartctlStatus{Passed: true}, Health: nvmeHealth("3415", "55")}
 	s := newFakeSmartctl()
 
 	results, err := s.All(context.Background(), "/dev/nvme0")
@@ -61,11 +93,11 @@ func Test_SmartctlAllNVME(t *testing.T) {
 
 func Test_SmartctlDeviceAttributes(t *testing.T) {
 	expected := []*common.Drive{
-		{Common: common.Common{LogicalName: "/dev/sda", Serial: "2013273A99BD", Vendor: common.VendorMicron, Model: "Micron_5200_MTFDDAK960TDN", ProductName: "Micron_5200_MTFDDAK960TDN", Firmware: &common.Firmware{Installed: "D1MU020"}}, Type: common.SlugDriveTypeSATASSD, SmartStatus: "ok", StorageControllerDriveID: -1},
-		{Common: common.Common{LogicalName: "/dev/sdb", Serial: "VDJ6SU9K", Vendor: common.VendorHGST, Model: "HGST HUS728T8TALE6L4", ProductName: "HGST HUS728T8TALE6L4", Firmware: &common.Firmware{Installed: "V8GNW460"}}, Type: common.SlugDriveTypeSATAHDD, SmartStatus: "ok", StorageControllerDriveID: -1},
-		{Common: common.Common{LogicalName: "/dev/sdc", Serial: "PHYH1016001D240J", Vendor: common.VendorDell, Model: "SSDSCKKB240G8R", ProductName: "SSDSCKKB240G8R", Firmware: &common.Firmware{Installed: "XC31DL6R"}}, Type: "Unknown", SmartStatus: "ok", OemID: "DELL(tm)", StorageControllerDriveID: -1},
-		{Common: common.Common{LogicalName: "/dev/nvme0", Serial: "Z9DF70I8FY3L", Vendor: common.VendorToshiba, Model: "KXG60ZNV256G TOSHIBA", ProductName: "KXG60ZNV256G TOSHIBA", Firmware: &common.Firmware{Installed: "AGGA4104"}}, Type: common.SlugDriveTypePCIeNVMEeSSD, SmartStatus: "ok", StorageControllerDriveID: -1},
-		{Common: common.Common{LogicalName: "/dev/nvme1", Serial: "Z9DF70I9FY3L", Vendor: common.VendorToshiba, Model: "KXG60ZNV256G TOSHIBA", ProductName: "KXG60ZNV256G TOSHIBA", Firmware: &common.Firmware{Installed: "AGGA4104"}}, Type: common.SlugDriveTypePCIeNVMEeSSD, SmartStatus: "ok", StorageControllerDriveID: -1},
+		{Common: common.Common{LogicalName: "/dev/sda", Serial: "2013273A99BD", Vendor: common.VendorMicron, Model: "Micron_5200_MTFDDAK960TDN", ProductName: "Micron_5200_MTFDDAK960TDN", Firmware: &common.Firmware{Installed: "D1MU020"}, Metadata: ataHealth("3410", "36", "100")}, Type: common.SlugDriveTypeSATASSD, SmartStatus: "ok", StorageControllerDriveID: -1},
+		{Common: common.Common{LogicalName: "/dev/sdb", Serial: "VDJ6SU9K", Vendor: common.VendorHGST, Model: "HGST HUS728T8TALE6L4", ProductName: "HGST HUS728T8TALE6L4", Firmware: &common.Firmware{Installed: "V8GNW460"}, Metadata: ataHealth("3416", "30", "")}, Type: common.SlugDriveTypeSATAHDD, SmartStatus: "ok", StorageControllerDriveID: -1},
+		{Common: common.Common{LogicalName: "/dev/sdc", Serial: "PHYH1016001D240J", Vendor: common.VendorDell, Model: "SSDSCKKB240G8R", ProductName: "SSDSCKKB240G8R", Firmware: &common.Firmware{Installed: "XC31DL6R"}, Metadata: ataHealth("2038", "37", "100")}, Type: "Unknown", SmartStatus: "ok", OemID: "DELL(tm)", StorageControllerDriveID: -1},
+		{Common: common.Common{LogicalName: "/dev/nvme0", Serial: "Z9DF70I8FY3L", Vendor: common.VendorToshiba, Model: "KXG60ZNV256G TOSHIBA", ProductName: "KXG60ZNV256G TOSHIBA", Firmware: &common.Firmware{Installed: "AGGA4104"}, Metadata: nvmeHealth("3415", "55")}, Type: common.SlugDriveTypePCIeNVMEeSSD, SmartStatus: "ok", StorageControllerDriveID: -1},
+		{Common: common.Common{LogicalName: "/dev/nvme1", Serial: "Z9DF70I9FY3L", Vendor: common.VendorToshiba, Model: "KXG60ZNV256G TOSHIBA", ProductName: "KXG60ZNV256G TOSHIBA", Firmware: &common.Firmware{Installed: "AGGA4104"}, Metadata: nvmeHealth("3462", "56")}, Type: common.SlugDriveTypePCIeNVMEeSSD, SmartStatus: "ok", StorageControllerDriveID: -1},
 	}
 	s := newFakeSmartctl()
 
@@ -90,6 +122,7 @@ func Test_SmartctlNonZeroExit(t *testing.T) {
 		Errors: []string{
 			"Some SMART or other ATA command to the disk failed, or there was a checksum error in a SMART data structure",
 		},
+		Health: ataHealth("2038", "37", "100"),
 	}
 
 	s := newFakeSmartctl()
@@ -103,6 +136,21 @@ func Test_SmartctlNonZeroExit(t *testing.T) {
 	assert.Equal(t, expected, results)
 }
 
+func Test_SmartctlAllSAS(t *testing.T) {
+	s := newFakeSmartctl()
+
+	results, err := s.All(context.Background(), "/dev/sdd")
+	require.NoError(t, err)
+
+	expected := map[string]string{
+		model.DriveHealthGrownDefects: "12",
+		model.DriveHealthPowerOnHours: "26417",
+		model.DriveHealthTemperature:  "31",
+	}
+
+	assert.Equal(t, expected, results.Health)
+}
+
 func Test_exponentInt(t *testing.T) {
 	m := map[int]int{
 		0: 1,
@@ -0,0 +1,649 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dselans/dmidecode"
+	"github.com/pkg/errors"
+)
+
+const (
+	// SysfsSMBIOSEntryPoint is the SMBIOS entry point exposed by the kernel
+	SysfsSMBIOSEntryPoint = "/sys/firmware/dmi/tables/smbios_entry_point"
+	// SysfsDMITable is the SMBIOS structure table exposed by the kernel
+	SysfsDMITable = "/sys/firmware/dmi/tables/DMI"
+
+	smbiosTypeEndOfTable = 127
+	// dmidecode prints this value for string fields that are not set
+	smbiosNotSpecified = "Not Specified"
+)
+
+var (
+	ErrSMBIOSEntryPoint    = errors.New("invalid SMBIOS entry point")
+	ErrSMBIOSTableTruncate = errors.New("SMBIOS table is truncated")
+	ErrSMBIOSTableEmpty    = errors.New("SMBIOS table has no structures")
+)
+
+// SMBIOSEntryPoint is the SMBIOS entry point structure, it describes the SMBIOS version and structure table
+type SMBIOSEntryPoint struct {
+	Major        int
+	Minor        int
+	Revision     int
+	TableLength  int
+	TableAddress uint64
+}
+
+// Version returns the SMBIOS version
+func (e *SMBIOSEntryPoint) Version() string {
+	return fmt.Sprintf("%d.%d.%d", e.Major, e.Minor, e.Revision)
+}
+
+// SMBIOSStructure is a structure from the SMBIOS table
+type SMBIOSStructure struct {
+	Type   uint8
+	Handle uint16
+	// Formatted is the formatted area of the structure, including the header.
+	Formatted []byte
+	// Strings are the strings referenced by index from the formatted area.
+	Strings []string
+}
+
+// ParseSMBIOSEntryPoint parses the 64-bit (_SM3_), 32-bit (_SM_) or legacy (_DMI_) SMBIOS entry point
+func ParseSMBIOSEntryPoint(b []byte) (*SMBIOSEntryPoint, error) {
+	switch {
+	case bytes.HasPrefix(b, []byte("_SM3_")):
+		if len(b) < 0x18 || int(b[6]) > len(b) || !smbiosChecksum(b[:b[6]]) {
+			return nil, errors.Wrap(ErrSMBIOSEntryPoint, "64-bit entry point length or checksum")
+		}
+
+		return &SMBIOSEntryPoint{
+			Major:        int(b[7]),
+			Minor:        int(b[8]),
+			Revision:     int(b[9]),
+			TableLength:  int(binary.LittleEndian.Uint32(b[0x0c:])),
+			TableAddress: binary.LittleEndian.Uint64(b[0x10:]),
+		}, nil
+	case bytes.HasPrefix(b, []byte("_SM_")):
+		if len(b) < 0x1f || int(b[5]) > len(b) || !smbiosChecksum(b[:b[5]]) || !bytes.Equal(b[0x10:0x15], []byte("_DMI_")) {
+			return nil, errors.Wrap(ErrSMBIOSEntryPoint, "32-bit entry point length or checksum")
+		}
+
+		return &SMBIOSEntryPoint{
+			Major:        int(b[6]),
+			Minor:        int(b[7]),
+			TableLength:  int(binary.LittleEndian.Uint16(b[0x16:])),
+			TableAddress: uint64(binary.LittleEndian.Uint32(b[0x18:])),
+		}, nil
+	case bytes.HasPrefix(b, []byte("_DMI_")):
+		if len(b) < 0x0f || !smbiosChecksum(b[:0x0f]) {
+			return nil, errors.Wrap(ErrSMBIOSEntryPoint, "legacy entry point length or checksum")
+		}
+
+		return &SMBIOSEntryPoint{
+			Major:        int(b[0x0e] >> 4),
+			Minor:        int(b[0x0e] & 0x0f),
+			TableLength:  int(binary.LittleEndian.Uint16(b[0x06:])),
+			TableAddress: uint64(binary.LittleEndian.Uint32(b[0x08:])),
+		}, nil
+	}
+
+	return nil, errors.Wrap(ErrSMBIOSEntryPoint, "unknown anchor string")
+}
+
+func smbiosChecksum(b []byte) bool {
+	var sum byte
+	for _, v := range b {
+		sum += v
+	}
+
+	return sum == 0
+}
+
+// ParseSMBIOSTable parses the SMBIOS structure table up to the end of table structure
+func ParseSMBIOSTable(b []byte) ([]*SMBIOSStructure, error) {
+	structures := []*SMBIOSStructure{}
+
+	for i := 0; i+4 <= len(b); {
+		length := int(b[i+1])
+		if length < 4 || i+length > len(b) {
+			return nil, errors.Wrap(ErrSMBIOSTableTruncate, fmt.Sprintf("structure at offset %d", i))
+		}
+
+		s := &SMBIOSStructure{
+			Type:      b[i],
+			Handle:    binary.LittleEndian.Uint16(b[i+2:]),
+			Formatted: b[i : i+length],
+		}
+
+		// the strings area is terminated by a double null
+		end := bytes.Index(b[i+length:], []byte{0, 0})
+		if end < 0 {
+			return nil, errors.Wrap(ErrSMBIOSTableTruncate, fmt.Sprintf("structure strings at offset %d", i))
+		}
+
+		if end > 0 {
+			s.Strings = strings.Split(string(b[i+length:i+length+end]), "\x00")
+		}
+
+		structures = append(structures, s)
+		if s.Type == smbiosTypeEndOfTable {
+			break
+		}
+
+		i += length + end + 2
+	}
+
+	if len(structures) == 0 {
+		return nil, ErrSMBIOSTableEmpty
+	}
+
+	return structures, nil
+}
+
+// byteAt returns the byte at the offset in the formatted area, ok is false when the structure is too short
+func (s *SMBIOSStructure) byteAt(offset int) (v uint8, ok bool) {
+	if offset >= len(s.Formatted) {
+		return 0, false
+	}
+
+	return s.Formatted[offset], true
+}
+
+func (s *SMBIOSStructure) wordAt(offset int) (v uint16, ok bool) {
+	if offset+2 > len(s.Formatted) {
+		return 0, false
+	}
+
+	return binary.LittleEndian.Uint16(s.Formatted[offset:]), true
+}
+
+func (s *SMBIOSStructure) dwordAt(offset int) (v uint32, ok bool) {
+	if offset+4 > len(s.Formatted) {
+		return 0, false
+	}
+
+	return binary.LittleEndian.Uint32(s.Formatted[offset:]), true
+}
+
+func (s *SMBIOSStructure) qwordAt(offset int) (v uint64, ok bool) {
+	if offset+8 > len(s.Formatted) {
+		return 0, false
+	}
+
+	return binary.LittleEndian.Uint64(s.Formatted[offset:]), true
+}
+
+// stringAt returns the string referenced by the index at the offset in the formatted area
+func (s *SMBIOSStructure) stringAt(offset int) (v string, ok bool) {
+	idx, ok := s.byteAt(offset)
+	if !ok {
+		return "", false
+	}
+
+	if idx == 0 {
+		return smbiosNotSpecified, true
+	}
+
+	if int(idx) > len(s.Strings) {
+		return "<BAD INDEX>", true
+	}
+
+	return s.Strings[idx-1], true
+}
+
+// smbiosRecord is a helper to build a dmidecode.Record from a SMBIOS structure,
+// the record fields are named as printed by dmidecode so they can be queried in the same way.
+type smbiosRecord struct {
+	s *SMBIOSStructure
+	r dmidecode.Record
+}
+
+func (r *smbiosRecord) str(key string, offset int) {
+	if v, ok := r.s.stringAt(offset); ok {
+		r.r[key] = v
+	}
+}
+
+func (r *smbiosRecord) enum(key string, offset int, mask uint8, names map[uint8]string) {
+	if v, ok := r.s.byteAt(offset); ok {
+		r.r[key] = smbiosEnumName(v&mask, names)
+	}
+}
+
+func smbiosEnumName(v uint8, names map[uint8]string) string {
+	if name, exists := names[v]; exists {
+		return name
+	}
+
+	return "<OUT OF SPEC>"
+}
+
+// smbiosSize formats the size in the largest unit the size is a multiple of, as dmidecode does
+func smbiosSize(b uint64) string {
+	units := []string{"bytes", "kB", "MB", "GB", "TB", "PB", "EB"}
+
+	i := 0
+	for b != 0 && b%1024 == 0 && i < len(units)-1 {
+		b /= 1024
+		i++
+	}
+
+	return fmt.Sprintf("%d %s", b, units[i])
+}
+
+// SMBIOSRecords returns the SMBIOS structures of the types supported as dmidecode records keyed by handle,
+// structures of other types are skipped.
+func SMBIOSRecords(structures []*SMBIOSStructure) map[string][]dmidecode.Record {
+	records := map[string][]dmidecode.Record{}
+
+	for _, s := range structures {
+		decode, exists := smbiosDecoders[s.Type]
+		if !exists {
+			continue
+		}
+
+		r := &smbiosRecord{
+			s: s,
+			r: dmidecode.Record{
+				"DMIType": strconv.Itoa(int(s.Type)),
+				"DMISize": strconv.Itoa(len(s.Formatted)),
+				"DMIName": decode.name,
+			},
+		}
+
+		decode.fn(r)
+
+		handle := fmt.Sprintf("0x%04X", s.Handle)
+		records[handle] = append(records[handle], r.r)
+	}
+
+	return records
+}
+
+type smbiosDecoder struct {
+	name string
+	fn   func(*smbiosRecord)
+}
+
+// smbiosDecoders are the SMBIOS structure types decoded, the names match the dmidecode output
+var smbiosDecoders = map[uint8]smbiosDecoder{
+	0:  {"BIOS Information", decodeSMBIOSBIOS},
+	1:  {"System Information", decodeSMBIOSSystem},
+	2:  {"Base Board Information", decodeSMBIOSBaseBoard},
+	3:  {"Chassis Information", decodeSMBIOSChassis},
+	4:  {"Processor Information", decodeSMBIOSProcessor},
+	11: {"OEM Strings", decodeSMBIOSOEMStrings},
+	16: {"Physical Memory Array", decodeSMBIOSMemoryArray},
+	17: {"Memory Device", decodeSMBIOSMemoryDevice},
+	39: {"System Power Supply", decodeSMBIOSPowerSupply},
+	43: {"TPM Device", decodeSMBIOSTPM},
+}
+
+// type 0
+func decodeSMBIOSBIOS(r *smbiosRecord) {
+	r.str("Vendor", 0x04)
+	r.str("Version", 0x05)
+	r.str("Release Date", 0x08)
+
+	if major, ok := r.s.byteAt(0x14); ok && major != 0xff {
+		minor, _ := r.s.byteAt(0x15)
+		r.r["BIOS Revision"] = fmt.Sprintf("%d.%d", major, minor)
+	}
+
+	if major, ok := r.s.byteAt(0x16); ok && major != 0xff {
+		minor, _ := r.s.byteAt(0x17)
+		r.r["Firmware Revision"] = fmt.Sprintf("%d.%d", major, minor)
+	}
+}
+
+// type 1
+func decodeSMBIOSSystem(r *smbiosRecord) {
+	r.str("Manufacturer", 0x04)
+	r.str("Product Name", 0x05)
+	r.str("Version", 0x06)
+	r.str("Serial Number", 0x07)
+
+	if len(r.s.Formatted) >= 0x18 {
+		r.r["UUID"] = smbiosUUID(r.s.Formatted[0x08:0x18])
+	}
+
+	r.str("SKU Number", 0x19)
+	r.str("Family", 0x1a)
+}
+
+// smbiosUUID formats the UUID, the first three fields are little endian as of SMBIOS 2.6
+func smbiosUUID(b []byte) string {
+	return fmt.Sprintf("%02x%02x%02x%02x-%02x%02x-%02x%02x-%02x%02x-%02x%02x%02x%02x%02x%02x",
+		b[3], b[2], b[1], b[0], b[5], b[4], b[7], b[6], b[8], b[9], b[10], b[11], b[12], b[13], b[14], b[15])
+}
+
+// type 2
+func decodeSMBIOSBaseBoard(r *smbiosRecord) {
+	r.str("Manufacturer", 0x04)
+	r.str("Product Name", 0x05)
+	r.str("Version", 0x06)
+	r.str("Serial Number", 0x07)
+	r.str("Asset Tag", 0x08)
+}
+
+var smbiosChassisTypes = map[uint8]string{
+	0x01: "Other", 0x02: "Unknown", 0x03: "Desktop", 0x04: "Low Profile Desktop", 0x05: "Pizza Box",
+	0x06: "Mini Tower", 0x07: "Tower", 0x08: "Portable", 0x09: "Laptop", 0x0a: "Notebook",
+	0x0b: "Hand Held", 0x0c: "Docking Station", 0x0d: "All In One", 0x0e: "Sub Notebook", 0x0f: "Space-saving",
+	0x10: "Lunch Box", 0x11: "Main Server Chassis", 0x12: "Expansion Chassis", 0x13: "Sub Chassis",
+	0x14: "Bus Expansion Chassis", 0x15: "Peripheral Chassis", 0x16: "RAID Chassis", 0x17: "Rack Mount Chassis",
+	0x18: "Sealed-case PC", 0x19: "Multi-system", 0x1a: "CompactPCI", 0x1b: "AdvancedTCA", 0x1c: "Blade",
+	0x1d: "Blade Enclosure", 0x1e: "Tablet", 0x1f: "Convertible", 0x20: "Detachable", 0x21: "IoT Gateway",
+	0x22: "Embedded PC", 0x23: "Mini PC", 0x24: "Stick PC",
+}
+
+// type 3
+func decodeSMBIOSChassis(r *smbiosRecord) {
+	r.str("Manufacturer", 0x04)
+	r.enum("Type", 0x05, 0x7f, smbiosChassisTypes)
+	r.str("Version", 0x06)
+	r.str("Serial Number", 0x07)
+	r.str("Asset Tag", 0x08)
+}
+
+var smbiosProcessorTypes = map[uint8]string{
+	0x01: "Other", 0x02: "Unknown", 0x03: "Central Processor", 0x04: "Math Processor", 0x05: "DSP Processor", 0x06: "Video Processor",
+}
+
+var smbiosProcessorStatus = map[uint8]string{
+	0x00: "Unknown", 0x01: "Enabled", 0x02: "Disabled By User", 0x03: "Disabled By BIOS", 0x04: "Idle", 0x07: "Other",
+}
+
+// type 4
+func decodeSMBIOSProcessor(r *smbiosRecord) {
+	r.str("Socket Designation", 0x04)
+	r.enum("Type", 0x05, 0xff, smbiosProcessorTypes)
+	r.str("Manufacturer", 0x07)
+
+	if id, ok := r.s.qwordAt(0x08); ok {
+		b := make([]byte, 8)
+		binary.LittleEndian.PutUint64(b, id)
+		r.r["ID"] = strings.ToUpper(strings.TrimSpace(fmt.Sprintf("% x", b)))
+	}
+
+	r.str("Version", 0x10)
+
+	for key, offset := range map[string]int{"External Clock": 0x12, "Max Speed": 0x14, "Current Speed": 0x16} {
+		if v, ok := r.s.wordAt(offset); ok {
+			r.r[key] = smbiosSpeed(v, "MHz")
+		}
+	}
+
+	if status, ok := r.s.byteAt(0x18); ok {
+		if status&0x40 == 0 {
+			r.r["Status"] = "Unpopulated"
+		} else {
+			r.r["Status"] = "Populated, " + smbiosEnumName(status&0x07, smbiosProcessorStatus)
+		}
+	}
+
+	r.str("Serial Number", 0x20)
+	r.str("Asset Tag", 0x21)
+	r.str("Part Number", 0x22)
+
+	// the 2 byte counts at 0x2a and later are used when the 1 byte counts are 0xff
+	counts := []struct {
+		key     string
+		offset  int
+		offset2 int
+	}{
+		{"Core Count", 0x23, 0x2a},
+		{"Core Enabled", 0x24, 0x2c},
+		{"Thread Count", 0x25, 0x2e},
+	}
+
+	for _, c := range counts {
+		v, ok := r.s.byteAt(c.offset)
+		if !ok || v == 0 {
+			continue
+		}
+
+		count := uint16(v)
+		if v2, ok := r.s.wordAt(c.offset2); ok && v == 0xff {
+			count = v2
+		}
+
+		r.r[c.key] = strconv.Itoa(int(count))
+	}
+}
+
+func smbiosSpeed(v uint16, unit string) string {
+	if v == 0 {
+		return "Unknown"
+	}
+
+	return fmt.Sprintf("%d %s", v, unit)
+}
+
+// type 11
+func decodeSMBIOSOEMStrings(r *smbiosRecord) {
+	count, ok := r.s.byteAt(0x04)
+	if !ok {
+		return
+	}
+
+	for i := 1; i <= int(count) && i <= len(r.s.Strings); i++ {
+		r.r[fmt.Sprintf("String %d", i)] = r.s.Strings[i-1]
+	}
+}
+
+var smbiosMemoryArrayLocations = map[uint8]string{
+	0x01: "Other", 0x02: "Unknown", 0x03: "System Board Or Motherboard", 0x04: "ISA Add-on Card",
+	0x05: "EISA Add-on Card", 0x06: "PCI Add-on Card", 0x07: "MCA Add-on Card", 0x08: "PCMCIA Add-on Card",
+	0x09: "Proprietary Add-on Card", 0x0a: "NuBus",
+}
+
+var smbiosMemoryArrayUses = map[uint8]string{
+	0x01: "Other", 0x02: "Unknown", 0x03: "System Memory", 0x04: "Video Memory", 0x05: "Flash Memory",
+	0x06: "Non-volatile RAM", 0x07: "Cache Memory",
+}
+
+var smbiosMemoryArrayECC = map[uint8]string{
+	0x01: "Other", 0x02: "Unknown", 0x03: "None", 0x04: "Parity", 0x05: "Single-bit ECC", 0x06: "Multi-bit ECC", 0x07: "CRC",
+}
+
+// type 16
+func decodeSMBIOSMemoryArray(r *smbiosRecord) {
+	r.enum("Location", 0x04, 0xff, smbiosMemoryArrayLocations)
+	r.enum("Use", 0x05, 0xff, smbiosMemoryArrayUses)
+	r.enum("Error Correction Type", 0x06, 0xff, smbiosMemoryArrayECC)
+
+	if capacity, ok := r.s.dwordAt(0x07); ok {
+		// the extended maximum capacity in bytes is used when the capacity in kB is 0x80000000
+		if extended, ok := r.s.qwordAt(0x0f); ok && capacity == 0x80000000 {
+			r.r["Maximum Capacity"] = smbiosSize(extended)
+		} else {
+			r.r["Maximum Capacity"] = smbiosSize(uint64(capacity) * 1024)
+		}
+	}
+
+	if devices, ok := r.s.wordAt(0x0d); ok {
+		r.r["Number Of Devices"] = strconv.Itoa(int(devices))
+	}
+}
+
+var smbiosMemoryFormFactors = map[uint8]string{
+	0x01: "Other", 0x02: "Unknown", 0x03: "SIMM", 0x04: "SIP", 0x05: "Chip", 0x06: "DIP", 0x07: "ZIP",
+	0x08: "Proprietary Card", 0x09: "DIMM", 0x0a: "TSOP", 0x0b: "Row Of Chips", 0x0c: "RIMM", 0x0d: "SODIMM",
+	0x0e: "SRIMM", 0x0f: "FB-DIMM", 0x10: "Die",
+}
+
+var smbiosMemoryTypes = map[uint8]string{
+	0x01: "Other", 0x02: "Unknown", 0x03: "DRAM", 0x04: "EDRAM", 0x05: "VRAM", 0x06: "SRAM", 0x07: "RAM",
+	0x08: "ROM", 0x09: "Flash", 0x0a: "EEPROM", 0x0b: "FEPROM", 0x0c: "EPROM", 0x0d: "CDRAM", 0x0e: "3DRAM",
+	0x0f: "SDRAM", 0x10: "SGRAM", 0x11: "RDRAM", 0x12: "DDR", 0x13: "DDR2", 0x14: "DDR2 FB-DIMM",
+	0x18: "DDR3", 0x19: "FBD2", 0x1a: "DDR4", 0x1b: "LPDDR", 0x1c: "LPDDR2", 0x1d: "LPDDR3", 0x1e: "LPDDR4",
+	0x1f: "Logical non-volatile device", 0x20: "HBM", 0x21: "HBM2", 0x22: "DDR5", 0x23: "LPDDR5",
+}
+
+// type 17
+func decodeSMBIOSMemoryDevice(r *smbiosRecord) {
+	if size, ok := r.s.wordAt(0x0c); ok {
+		switch {
+		case size == 0:
+			r.r["Size"] = "No Module Installed"
+		case size == 0xffff:
+			r.r["Size"] = "Unknown"
+		case size == 0x7fff:
+			// the extended size in MB is used for sizes of 32GB and larger
+			if extended, ok := r.s.dwordAt(0x1c); ok {
+				r.r["Size"] = smbiosSize(uint64(extended&0x7fffffff) * 1024 * 1024)
+			}
+		case size&0x8000 != 0:
+			r.r["Size"] = smbiosSize(uint64(size&0x7fff) * 1024)
+		default:
+			r.r["Size"] = smbiosSize(uint64(size) * 1024 * 1024)
+		}
+	}
+
+	r.enum("Form Factor", 0x0e, 0xff, smbiosMemoryFormFactors)
+	r.str("Locator", 0x10)
+	r.str("Bank Locator", 0x11)
+	r.enum("Type", 0x12, 0xff, smbiosMemoryTypes)
+
+	if speed, ok := r.s.wordAt(0x15); ok {
+		r.r["Speed"] = smbiosSpeed(speed, "MT/s")
+	}
+
+	r.str("Manufacturer", 0x17)
+	r.str("Serial Number", 0x18)
+	r.str("Asset Tag", 0x19)
+	r.str("Part Number", 0x1a)
+
+	if speed, ok := r.s.wordAt(0x20); ok {
+		r.r["Configured Memory Speed"] = smbiosSpeed(speed, "MT/s")
+	}
+}
+
+var smbiosPowerSupplyStatus = map[uint8]string{
+	0x01: "Other", 0x02: "Unknown", 0x03: "OK", 0x04: "Non-critical", 0x05: "Critical",
+}
+
+// type 39
+func decodeSMBIOSPowerSupply(r *smbiosRecord) {
+	if group, ok := r.s.byteAt(0x04); ok {
+		r.r["Power Unit Group"] = strconv.Itoa(int(group))
+	}
+
+	r.str("Location", 0x05)
+	r.str("Name", 0x06)
+	r.str("Manufacturer", 0x07)
+	r.str("Serial Number", 0x08)
+	r.str("Asset Tag", 0x09)
+	r.str("Model Part Number", 0x0a)
+	r.str("Revision", 0x0b)
+
+	if capacity, ok := r.s.wordAt(0x0c); ok {
+		if capacity == 0x8000 {
+			r.r["Max Power Capacity"] = "Unknown"
+		} else {
+			r.r["Max Power Capacity"] = fmt.Sprintf("%d W", capacity)
+		}
+	}
+
+	if c, ok := r.s.wordAt(0x0e); ok {
+		if c&0x0002 == 0 {
+			r.r["Status"] = "Not Present"
+		} else {
+			r.r["Status"] = "Present, " + smbiosEnumName(uint8((c>>7)&0x07), smbiosPowerSupplyStatus)
+		}
+
+		r.r["Plugged"] = smbiosYesNo(c&0x0004 == 0)
+		r.r["Hot Replaceable"] = smbiosYesNo(c&0x0001 != 0)
+	}
+}
+
+func smbiosYesNo(v bool) string {
+	if v {
+		return "Yes"
+	}
+
+	return "No"
+}
+
+// type 43
+func decodeSMBIOSTPM(r *smbiosRecord) {
+	if len(r.s.Formatted) < 0x1b {
+		return
+	}
+
+	// the vendor ID is up to 4 ASCII characters
+	vendor := []byte{}
+	for _, c := range r.s.Formatted[0x04:0x08] {
+		if c < 0x20 || c > 0x7e {
+			break
+		}
+
+		vendor = append(vendor, c)
+	}
+
+	r.r["Vendor ID"] = string(vendor)
+
+	major, minor := r.s.Formatted[0x08], r.s.Formatted[0x09]
+	r.r["Specification Version"] = fmt.Sprintf("%d.%d", major, minor)
+
+	switch major {
+	case 0x01:
+		// TPM 1.2 firmware revision is the TPM_VERSION revision major, minor
+		r.r["Firmware Revision"] = fmt.Sprintf("%d.%d", r.s.Formatted[0x0c], r.s.Formatted[0x0d])
+	case 0x02:
+		fw, _ := r.s.dwordAt(0x0a)
+		r.r["Firmware Revision"] = fmt.Sprintf("%d.%d", fw>>16, fw&0xffff)
+	}
+
+	r.str("Description", 0x12)
+}
+
+// NewSMBIOSDmidecode returns a Dmidecode instance loaded from the SMBIOS tables exposed in sysfs,
+// without depending on the dmidecode utility.
+func NewSMBIOSDmidecode() (*Dmidecode, error) {
+	return NewDmidecodeFromSMBIOSFiles(SysfsSMBIOSEntryPoint, SysfsDMITable)
+}
+
+// NewDmidecodeFromSMBIOSFiles returns a Dmidecode instance loaded from the SMBIOS entry point and table files
+func NewDmidecodeFromSMBIOSFiles(entryPointFile, tableFile string) (*Dmidecode, error) {
+	entryPoint, err := os.ReadFile(entryPointFile)
+	if err != nil {
+		return nil, err
+	}
+
+	table, err := os.ReadFile(tableFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewDmidecodeFromSMBIOS(entryPoint, table)
+}
+
+// NewDmidecodeFromSMBIOS returns a Dmidecode instance loaded from the SMBIOS entry point and table data
+func NewDmidecodeFromSMBIOS(entryPoint, table []byte) (*Dmidecode, error) {
+	ep, err := ParseSMBIOSEntryPoint(entryPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	// the table length in the entry point is the maximum length for SMBIOS 3.x
+	if ep.TableLength > 0 && ep.TableLength < len(table) {
+		table = table[:ep.TableLength]
+	}
+
+	structures, err := ParseSMBIOSTable(table)
+	if err != nil {
+		return nil, err
+	}
+
+	dmi := dmidecode.New()
+	dmi.Data = SMBIOSRecords(structures)
+
+	return &Dmidecode{dmi: dmi, smbios: ep}, nil
+}
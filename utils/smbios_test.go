@@ -0,0 +1,228 @@
+package utils
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	smbiosTestEntryPoint = "../fixtures/asrr/e3c246d4i-nl/smbios/smbios_entry_point"
+	smbiosTestTable      = "../fixtures/asrr/e3c246d4i-nl/smbios/DMI"
+)
+
+// withChecksum sets the byte at offset so the bytes add up to zero
+func withChecksum(b []byte, offset int) []byte {
+	var sum byte
+	for _, v := range b {
+		sum += v
+	}
+
+	b[offset] -= sum
+
+	return b
+}
+
+func Test_ParseSMBIOSEntryPoint(t *testing.T) {
+	testcases := []struct {
+		name     string
+		data     []byte
+		expected *SMBIOSEntryPoint
+		err      error
+	}{
+		{
+			"64-bit",
+			withChecksum([]byte{
+				'_', 'S', 'M', '3', '_', 0x00, 0x18, 0x03, 0x02, 0x01, 0x01, 0x00,
+				0x00, 0x10, 0x00, 0x00, 0x20, 0x9b, 0x0e, 0x00, 0x00, 0x00, 0x00, 0x00,
+			}, 5),
+			&SMBIOSEntryPoint{Major: 3, Minor: 2, Revision: 1, TableLength: 4096, TableAddress: 0xe9b20},
+			nil,
+		},
+		{
+			"32-bit",
+			withChecksum([]byte{
+				'_', 'S', 'M', '_', 0x00, 0x1f, 0x02, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				'_', 'D', 'M', 'I', '_', 0x00, 0x50, 0x0a, 0x00, 0x90, 0x0e, 0x00, 0x30, 0x00, 0x28,
+			}, 4),
+			&SMBIOSEntryPoint{Major: 2, Minor: 8, TableLength: 2640, TableAddress: 0xe9000},
+			nil,
+		},
+		{
+			"bad checksum",
+			[]byte{
+				'_', 'S', 'M', '3', '_', 0x00, 0x18, 0x03, 0x02, 0x01, 0x01, 0x00,
+				0x00, 0x10, 0x00, 0x00, 0x20, 0x9b, 0x0e, 0x00, 0x00, 0x00, 0x00, 0x00,
+			},
+			nil,
+			ErrSMBIOSEntryPoint,
+		},
+		{
+			"unknown anchor",
+			[]byte("_XYZ_"),
+			nil,
+			ErrSMBIOSEntryPoint,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseSMBIOSEntryPoint(tc.data)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func Test_ParseSMBIOSTableTruncated(t *testing.T) {
+	// type 1 structure with the strings area not terminated
+	_, err := ParseSMBIOSTable([]byte{0x01, 0x08, 0x01, 0x00, 0x01, 0x02, 0x03, 0x04, 'a', 0x00})
+	assert.ErrorIs(t, err, ErrSMBIOSTableTruncate)
+
+	_, err = ParseSMBIOSTable([]byte{})
+	assert.ErrorIs(t, err, ErrSMBIOSTableEmpty)
+}
+
+func Test_SMBIOSDmidecode(t *testing.T) {
+	dmi, err := NewDmidecodeFromSMBIOSFiles(smbiosTestEntryPoint, smbiosTestTable)
+	require.NoError(t, err)
+	require.Equal(t, "3.2.1", dmi.smbios.Version())
+
+	// compare with the values read from the dmidecode output for the same system
+	expected, err := InitFakeDmidecode("../fixtures/asrr/e3c246d4i-nl/dmidecode")
+	require.NoError(t, err)
+
+	for _, fn := range []func(*Dmidecode) (string, error){
+		(*Dmidecode).Manufacturer,
+		(*Dmidecode).ProductName,
+		(*Dmidecode).SerialNumber,
+		(*Dmidecode).BaseBoardManufacturer,
+		(*Dmidecode).BaseBoardProductName,
+		(*Dmidecode).BaseBoardSerialNumber,
+		(*Dmidecode).ChassisSerialNumber,
+		(*Dmidecode).BIOSVersion,
+	} {
+		want, err := fn(expected)
+		require.NoError(t, err)
+
+		got, err := fn(dmi)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+
+	wantTPMs, err := expected.TPMs(context.TODO())
+	require.NoError(t, err)
+
+	gotTPMs, err := dmi.TPMs(context.TODO())
+	require.NoError(t, err)
+	assert.Equal(t, wantTPMs, gotTPMs)
+
+	oemStrings, err := dmi.OEMStrings()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"To Be Filled By O.E.M.", "sku=c3.small.x86"}, oemStrings)
+}
+
+func Test_SMBIOSRecords(t *testing.T) {
+	dmi, err := NewDmidecodeFromSMBIOSFiles(smbiosTestEntryPoint, smbiosTestTable)
+	require.NoError(t, err)
+
+	testcases := []struct {
+		typeID   int
+		expected map[string]string
+	}{
+		{
+			0,
+			map[string]string{"Vendor": "American Megatrends Inc.", "Version": "L2.07B", "Release Date": "04/08/2020", "BIOS Revision": "5.13"},
+		},
+		{
+			1,
+			map[string]string{"UUID": "00000000-0000-0000-0000-d05099f030c4", "SKU Number": "To Be Filled By O.E.M."},
+		},
+		{
+			3,
+			map[string]string{"Type": "Main Server Chassis", "Serial Number": "K61203057200139"},
+		},
+		{
+			4,
+			map[string]string{
+				"Socket Designation": "CPU1",
+				"Type":               "Central Processor",
+				"Manufacturer":       "Intel(R) Corporation",
+				"ID":                 "ED 06 09 00 FF FB EB BF",
+				"Version":            "Intel(R) Xeon(R) E-2278G CPU @ 3.40GHz",
+				"Max Speed":          "4700 MHz",
+				"Current Speed":      "3700 MHz",
+				"Status":             "Populated, Enabled",
+				"Core Count":         "6",
+				"Thread Count":       "12",
+			},
+		},
+		{
+			16,
+			map[string]string{
+				"Location":              "System Board Or Motherboard",
+				"Use":                   "System Memory",
+				"Error Correction Type": "Single-bit ECC",
+				"Maximum Capacity":      "128 GB",
+				"Number Of Devices":     "4",
+			},
+		},
+		{
+			39,
+			map[string]string{
+				"Location":           "PSU1",
+				"Name":               "PWS-1K23A-1R",
+				"Manufacturer":       "SUPERMICRO",
+				"Serial Number":      "P1K2ACK07HB0670",
+				"Max Power Capacity": "1200 W",
+				"Status":             "Present, OK",
+				"Hot Replaceable":    "Yes",
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		records, err := dmi.queryType(tc.typeID)
+		require.NoError(t, err)
+		require.Len(t, records, 1, "type %d", tc.typeID)
+
+		for k, v := range tc.expected {
+			assert.Equal(t, v, records[0][k], "type %d: %s", tc.typeID, k)
+		}
+	}
+
+	dimms, err := dmi.queryType(17)
+	require.NoError(t, err)
+	require.Len(t, dimms, 2)
+
+	sizes := map[string]string{}
+	for _, d := range dimms {
+		sizes[d["Locator"]] = d["Size"] + ", " + d["Type"] + ", " + d["Speed"] + ", " + d["Form Factor"]
+	}
+
+	assert.Equal(t, map[string]string{
+		"ChannelA-DIMM0": "16 GB, DDR4, 2666 MT/s, SODIMM",
+		"ChannelA-DIMM1": "No Module Installed, Unknown, Unknown, DIMM",
+	}, sizes)
+}
+
+func Test_SMBIOSTPM12(t *testing.T) {
+	s := &SMBIOSStructure{
+		Type:      43,
+		Formatted: []byte{43, 31, 0, 0, 'I', 'F', 'X', 0, 1, 2, 1, 2, 3, 17, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+		Strings:   []string{"TPM 1.2"},
+	}
+
+	records := SMBIOSRecords([]*SMBIOSStructure{s})
+	assert.Equal(t, "IFX", records["0x0000"][0]["Vendor ID"])
+	assert.Equal(t, "1.2", records["0x0000"][0]["Specification Version"])
+	assert.Equal(t, "3.17", records["0x0000"][0]["Firmware Revision"])
+	assert.Equal(t, "TPM 1.2", records["0x0000"][0]["Description"])
+}
@@ -5,15 +5,24 @@ import (
 	"context"
 	"encoding/xml"
 	"io"
+	"maps"
 	"os"
+	"slices"
 	"strings"
 
+	"github.com/beevik/etree"
 	common "github.com/metal-toolbox/bmc-common"
 	"github.com/metal-toolbox/ironlib/model"
+	"github.com/pkg/errors"
 	"golang.org/x/net/html/charset"
+	"golang.org/x/text/encoding/charmap"
 )
 
-const EnvVarSumPath = "IRONLIB_UTIL_SUM"
+const (
+	EnvVarSumPath = "IRONLIB_UTIL_SUM"
+
+	sumTmpBIOSConfigXML = "/tmp/biosconfig-sum.xml"
+)
 
 type biosCfg struct {
 	XMLName xml.Name `xml:"BiosCfg"`
@@ -36,6 +45,9 @@ type setting struct {
 
 type SupermicroSUM struct {
 	Executor Executor
+	// DryRun is set to return the update commands with errs.UpdateDryRunError instead of executing them.
+	DryRun     bool
+	tmpXMLFile string
 }
 
 // Return a new Supermicro sum command executor
@@ -55,7 +67,7 @@ func NewSupermicroSUM(trace bool) *SupermicroSUM {
 		e.SetQuiet()
 	}
 
-	return &SupermicroSUM{Executor: e}
+	return &SupermicroSUM{Executor: e, tmpXMLFile: sumTmpBIOSConfigXML}
 }
 
 // Attributes implements the actions.UtilAttributeGetter interface
@@ -76,7 +88,13 @@ func (s *SupermicroSUM) Collect(_ *common.Device) error {
 	return nil
 }
 
-// UpdateBIOS installs the SMC BIOS update
+// SetDryRun sets the updater to return the update commands instead of executing them
+func (s *SupermicroSUM) SetDryRun(dryRun bool) {
+	s.DryRun = dryRun
+}
+
+// UpdateBIOS installs the SMC BIOS update,
+// ErrRebootRequired is returned once installed since the BIOS firmware is activated on the next host reboot.
 func (s *SupermicroSUM) UpdateBIOS(ctx context.Context, updateFile, modelNumber string) error {
 	s.Executor.SetArgs("-c", "UpdateBios", "--preserve_setting", "--file", updateFile)
 
@@ -85,6 +103,10 @@ func (s *SupermicroSUM) UpdateBIOS(ctx context.Context, updateFile, modelNumber
 		s.Executor.SetArgs("-c", "UpdateBios", "--file", updateFile)
 	}
 
+	if s.DryRun {
+		return updateDryRunError(s.Executor)
+	}
+
 	result, err := s.Executor.Exec(ctx)
 	if err != nil {
 		return err
@@ -94,13 +116,17 @@ func (s *SupermicroSUM) UpdateBIOS(ctx context.Context, updateFile, modelNumber
 		return newExecError(s.Executor.GetCmd(), result)
 	}
 
-	return nil
+	return rebootRequiredError(common.SlugBIOS)
 }
 
 // UpdateBMC installs the SMC BMC update
 func (s *SupermicroSUM) UpdateBMC(ctx context.Context, updateFile, _ string) error {
 	s.Executor.SetArgs("-c", "UpdateBmc", "--file", updateFile)
 
+	if s.DryRun {
+		return updateDryRunError(s.Executor)
+	}
+
 	result, err := s.Executor.Exec(ctx)
 	if err != nil {
 		return err
@@ -113,6 +139,27 @@ func (s *SupermicroSUM) UpdateBMC(ctx context.Context, updateFile, _ string) err
 	return nil
 }
 
+// UpdateCPLD installs the SMC CPLD update,
+// ErrRebootRequired is returned once installed since the CPLD firmware is activated on the next host reboot.
+func (s *SupermicroSUM) UpdateCPLD(ctx context.Context, updateFile, _ string) error {
+	s.Executor.SetArgs("-c", "UpdateCpld", "--file", updateFile)
+
+	if s.DryRun {
+		return updateDryRunError(s.Executor)
+	}
+
+	result, err := s.Executor.Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	if result.ExitCode != 0 {
+		return newExecError(s.Executor.GetCmd(), result)
+	}
+
+	return rebootRequiredError(common.SlugCPLD)
+}
+
 // ApplyUpdate installs the SMC update based on the component
 func (s *SupermicroSUM) ApplyUpdate(ctx context.Context, updateFile, componentSlug string) error {
 	switch componentSlug {
@@ -120,6 +167,12 @@ func (s *SupermicroSUM) ApplyUpdate(ctx context.Context, updateFile, componentSl
 		s.Executor.SetArgs("-c", "UpdateBios", "--preserve_setting", "--file", updateFile)
 	case common.SlugBMC:
 		s.Executor.SetArgs("-c", "UpdateBmc", "--file", updateFile)
+	case common.SlugCPLD:
+		s.Executor.SetArgs("-c", "UpdateCpld", "--file", updateFile)
+	}
+
+	if s.DryRun {
+		return updateDryRunError(s.Executor)
 	}
 
 	result, err := s.Executor.Exec(ctx)
@@ -131,6 +184,10 @@ func (s *SupermicroSUM) ApplyUpdate(ctx context.Context, updateFile, componentSl
 		return newExecError(s.Executor.GetCmd(), result)
 	}
 
+	if componentSlug == common.SlugBIOS || componentSlug == common.SlugCPLD {
+		return rebootRequiredError(componentSlug)
+	}
+
 	return nil
 }
 
@@ -139,8 +196,106 @@ func (s *SupermicroSUM) GetBIOSConfiguration(ctx context.Context, _ string) (map
 	return s.parseBIOSConfig(ctx)
 }
 
-// parseBIOSConfig parses the SMC sum command output BIOS config and returns a model.BIOSConfiguration object
-func (s *SupermicroSUM) parseBIOSConfig(ctx context.Context) (map[string]string, error) {
+// SetBIOSConfiguration applies the given BIOS settings with sum -c ChangeBiosCfg,
+// the settings take effect on the next host reboot.
+func (s *SupermicroSUM) SetBIOSConfiguration(ctx context.Context, _ string, cfg map[string]string) error {
+	current, err := s.currentBIOSConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	// sum expects the complete BIOS config, so the current config is updated with the given settings
+	b, err := sumBIOSConfigChange(current, cfg)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(s.tmpXMLFile, b, 0o600); err != nil {
+		return err
+	}
+
+	defer os.Remove(s.tmpXMLFile)
+
+	s.Executor.SetArgs("-c", "ChangeBiosCfg", "--file", s.tmpXMLFile)
+
+	result, err := s.Executor.Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	if result.ExitCode != 0 {
+		return newExecError(s.Executor.GetCmd(), result)
+	}
+
+	return nil
+}
+
+// sumBIOSConfigChange sets the given settings in the sum BIOS config XML and returns the updated XML
+func sumBIOSConfigChange(current []byte, cfg map[string]string) ([]byte, error) {
+	// skip the sum banner printed before the XML
+	idx := bytes.Index(current, []byte("<?xml"))
+	if idx < 0 {
+		return nil, errors.Wrap(ErrNoCommandOutput, "BIOS config XML not found in sum output")
+	}
+
+	doc := etree.NewDocument()
+	// the xml exported by sum is ISO-8859-1 encoded
+	doc.ReadSettings.CharsetReader = charset.NewReaderLabel
+
+	if err := doc.ReadFromBytes(current[idx:]); err != nil {
+		return nil, err
+	}
+
+	notFound := maps.Clone(cfg)
+
+	// only option settings are included in the BIOS configuration returned by parseBIOSConfig
+	for _, setting := range doc.FindElements("//Setting[@type='Option']") {
+		name := strings.TrimSpace(setting.SelectAttrValue("name", ""))
+
+		value, exists := cfg[name]
+		if !exists {
+			// multi socket boards suffix settings applied to all sockets with [ALL],
+			// these are accepted without the suffix
+			name = strings.TrimSuffix(name, " [ALL]")
+			if value, exists = cfg[name]; !exists {
+				continue
+			}
+		}
+
+		if err := sumSetSettingValue(setting, value); err != nil {
+			return nil, errors.Wrap(err, name)
+		}
+
+		delete(notFound, name)
+	}
+
+	if len(notFound) > 0 {
+		return nil, errors.Wrap(ErrBIOSConfigKeyUnknown, strings.Join(slices.Sorted(maps.Keys(notFound)), ", "))
+	}
+
+	b, err := doc.WriteToBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	// the document was decoded into UTF-8, encode it back into ISO-8859-1 as declared in its header
+	return charmap.ISO8859_1.NewEncoder().Bytes(b)
+}
+
+// sumSetSettingValue sets the selected option on the sum BIOS config setting
+func sumSetSettingValue(setting *etree.Element, value string) error {
+	for _, option := range setting.FindElements("./Information/AvailableOptions/Option") {
+		if biosValueMatches(option.Text(), value) {
+			setting.CreateAttr("selectedOption", option.Text())
+			return nil
+		}
+	}
+
+	return errors.Wrap(ErrBIOSConfigValueInvalid, value)
+}
+
+// currentBIOSConfig returns the sum -c GetCurrentBiosCfg output
+func (s *SupermicroSUM) currentBIOSConfig(ctx context.Context) ([]byte, error) {
 	s.Executor.SetArgs("-c", "GetCurrentBiosCfg")
 
 	result, err := s.Executor.Exec(ctx)
@@ -152,22 +307,31 @@ func (s *SupermicroSUM) parseBIOSConfig(ctx context.Context) (map[string]string,
 		return nil, newExecError(s.Executor.GetCmd(), result)
 	}
 
+	return result.Stdout, nil
+}
+
+// parseBIOSConfig parses the SMC sum command output BIOS config and returns a model.BIOSConfiguration object
+func (s *SupermicroSUM) parseBIOSConfig(ctx context.Context) (map[string]string, error) {
+	current, err := s.currentBIOSConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := &biosCfg{}
 
 	// the xml exported by sum is ISO-8859-1 encoded
-	decoder := xml.NewDecoder(bytes.NewReader(result.Stdout))
+	decoder := xml.NewDecoder(bytes.NewReader(current))
 	// convert characters from non-UTF-8 to UTF-8
 	decoder.CharsetReader = charset.NewReaderLabel
 
-	err = decoder.Decode(cfg)
-	if err != nil {
+	if err := decoder.Decode(cfg); err != nil {
 		return nil, err
 	}
 
 	settings := map[string]string{}
 	s.recurseMenus(cfg.Menu, settings)
 
-	return normalizeBIOSConfiguration(settings), nil
+	return NormalizeBIOSConfiguration(settings), nil
 }
 
 // recurseMenus recurses through SMC BIOS menu options and gathers all settings with a selected option
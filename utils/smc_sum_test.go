@@ -6,7 +6,9 @@ import (
 	"os"
 	"testing"
 
+	"github.com/metal-toolbox/ironlib/errs"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_SMCUpdateBios(t *testing.T) {
@@ -31,17 +33,49 @@ func Test_SMCUpdateBios(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.testName, func(t *testing.T) {
+			// the BIOS firmware is activated on the next host reboot
 			err := sum.UpdateBIOS(context.TODO(), "/tmp/foo", tc.model)
-			if err != nil {
-				t.Error(err)
-				return
-			}
+			require.ErrorIs(t, err, ErrRebootRequired)
 
 			assert.Equal(t, tc.expectedCmd, sum.Executor.GetCmd())
 		})
 	}
 }
 
+func Test_SMCUpdateCPLD(t *testing.T) {
+	sum := NewFakeSMCSum(nil)
+
+	err := sum.UpdateCPLD(context.TODO(), "/tmp/cpld.jed", "X11DPH-T")
+	require.ErrorIs(t, err, ErrRebootRequired)
+
+	assert.Equal(t, "-c UpdateCpld --file /tmp/cpld.jed", sum.Executor.GetCmd())
+}
+
+func Test_SMCUpdateDryRun(t *testing.T) {
+	sum := NewFakeSMCSum(nil)
+	sum.SetDryRun(true)
+
+	updates := map[string]func() error{
+		"-c UpdateBios --preserve_setting --file /tmp/bios.bin": func() error {
+			return sum.UpdateBIOS(context.TODO(), "/tmp/bios.bin", "X11DPH-T")
+		},
+		"-c UpdateBmc --file /tmp/bmc.bin": func() error {
+			return sum.UpdateBMC(context.TODO(), "/tmp/bmc.bin", "X11DPH-T")
+		},
+		"-c UpdateCpld --file /tmp/cpld.jed": func() error {
+			return sum.UpdateCPLD(context.TODO(), "/tmp/cpld.jed", "X11DPH-T")
+		},
+	}
+
+	for expectedCmd, update := range updates {
+		dryRun := &errs.UpdateDryRunError{}
+		require.ErrorAs(t, update(), &dryRun)
+		require.Len(t, dryRun.Commands, 1)
+
+		assert.Equal(t, expectedCmd, dryRun.Commands[0].Cmd)
+	}
+}
+
 func Test_parseSMCBIOSConfig_X11SCHFF(t *testing.T) {
 	expected := map[string]string{
 		"boot_mode":                                 "BIOS",
@@ -467,3 +501,72 @@ func Test_parseSMCBIOSConfig_X11DPHT(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, expected, c)
 }
+
+func Test_sumBIOSConfigChange(t *testing.T) {
+	b, err := os.ReadFile("../fixtures/supermicro/x11schf-f/bios.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name        string
+		cfg         map[string]string
+		expected    map[string]string
+		expectedErr error
+	}{
+		{
+			"options set",
+			map[string]string{"Hyper-Threading": "Disabled", "BIST": "enable"},
+			map[string]string{"smt": "Disabled", "raw:BIST": "Enabled"},
+			nil,
+		},
+		{
+			"unknown setting",
+			map[string]string{"Hyper-Threading": "Disabled", "Quiet Boot": "Unchecked", "foo": "bar"},
+			nil,
+			ErrBIOSConfigKeyUnknown,
+		},
+		{
+			"invalid value",
+			map[string]string{"Hyper-Threading": "Maybe"},
+			nil,
+			ErrBIOSConfigValueInvalid,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := sumBIOSConfigChange(b, tc.cfg)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.True(t, bytes.HasPrefix(got, []byte(`<?xml version="1.0" encoding="ISO-8859-1"`)))
+
+			s := NewFakeSMCSum(bytes.NewReader(got))
+			c, err := s.parseBIOSConfig(context.TODO())
+			assert.NoError(t, err)
+
+			for k, v := range tc.expected {
+				assert.Equal(t, v, c[k], k)
+			}
+		})
+	}
+}
+
+func Test_sumBIOSConfigChange_AllSocketsAlias(t *testing.T) {
+	b, err := os.ReadFile("../fixtures/supermicro/x11dph-t/bios.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := sumBIOSConfigChange(b, map[string]string{"Hyper-Threading": "Disabled"})
+	assert.NoError(t, err)
+
+	s := NewFakeSMCSum(bytes.NewReader(got))
+	c, err := s.parseBIOSConfig(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, "Disabled", c["smt"])
+}
@@ -2,21 +2,51 @@ package utils
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
+	"maps"
 	"os"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
 	common "github.com/metal-toolbox/bmc-common"
 	"github.com/metal-toolbox/ironlib/model"
+	"github.com/pkg/errors"
 )
 
 const EnvStorecliUtility = "IRONLIB_UTIL_STORECLI"
 
+var (
+	ErrStoreCLICommandFailed      = errors.New("storecli command failed")
+	ErrStoreCLIControllerUnset    = errors.New("storecli controller not set")
+	ErrStoreCLIDriveNotFound      = errors.New("storecli physical drive not found")
+	ErrStoreCLIControllerNotFound = errors.New("storecli controller not found")
+)
+
+var (
+	storeCLIRaidModes  = []string{"0", "1", "5", "6", "00", "10", "50", "60"}
+	storeCLIStripSizes = []uint{8, 16, 32, 64, 128, 256, 512, 1024}
+
+	// matches the physical drive keys in the storecli show all output - Drive /c0/e252/s0 or Drive /c0/s0
+	storeCLIDriveKey = regexp.MustCompile(`^Drive (/c(\d+)(?:/e\d+)?/s\d+)$`)
+	// matches the virtual drive keys in the storecli show all output - /c0/v0
+	storeCLIVirtualDriveKey = regexp.MustCompile(`^/c(\d+)/v(\d+)$`)
+	// matches the sector count in the storecli drive size attributes - 1.745 TB [0xdf800000 Sectors]
+	storeCLISectors = regexp.MustCompile(`\[0x([0-9a-fA-F]+) Sectors\]`)
+)
+
 type StoreCLI struct {
 	Executor Executor
+	// Controller is the controller number the virtual disks are managed on,
+	// when not set virtual disks are listed on all controllers and cannot be created or destroyed.
+	Controller string
+	// DryRun is set to return the update commands with errs.UpdateDryRunError instead of executing them.
+	DryRun bool
 }
 
 type ShowController struct {
@@ -29,16 +59,67 @@ type Controller struct {
 }
 
 type CommandStatus struct {
+	Controller  int    `json:"Controller"`
 	Status      string `json:"Status"`
 	Description string `json:"Description"`
 }
 
 type ResponseData struct {
-	ProductName     string `json:"Product Name"`
-	SerialNumber    string `json:"Serial Number"`
-	FirmwareVersion string `json:"FW Version"`
-	BIOSVersion     string `json:"BIOS Version"`
-	PhysicalDrives  int    `json:"Physical Drives"`
+	ProductName     string                   `json:"Product Name"`
+	SerialNumber    string                   `json:"Serial Number"`
+	PCIAddress      string                   `json:"PCI Address"`
+	FirmwareVersion string                   `json:"FW Version"`
+	BIOSVersion     string                   `json:"BIOS Version"`
+	PhysicalDrives  int                      `json:"Physical Drives"`
+	PDList          []*StoreCLIPhysicalDrive `json:"PD LIST"`
+	VDList          []*StoreCLIVirtualDrive  `json:"VD LIST"`
+}
+
+// StoreCLIPhysicalDrive is a physical drive as listed by storecli
+type StoreCLIPhysicalDrive struct {
+	EnclosureSlot string `json:"EID:Slt"`
+	DID           int    `json:"DID"`
+	State         string `json:"State"`
+	Size          string `json:"Size"`
+	Interface     string `json:"Intf"`
+	Medium        string `json:"Med"`
+	SectorSize    string `json:"SeSz"`
+	Model         string `json:"Model"`
+}
+
+// StoreCLIVirtualDrive is a virtual drive as listed by storecli
+type StoreCLIVirtualDrive struct {
+	DriveGroupVD string `json:"DG/VD"`
+	Type         string `json:"TYPE"`
+	State        string `json:"State"`
+	Size         string `json:"Size"`
+	Name         string `json:"Name"`
+}
+
+// storeCLIResponse is the storecli JSON output where the response data keys depend on the object shown
+type storeCLIResponse struct {
+	Controllers []*struct {
+		CommandStatus *CommandStatus             `json:"Command Status"`
+		ResponseData  map[string]json.RawMessage `json:"Response Data"`
+	} `json:"Controllers"`
+}
+
+// storeCLIDriveAttributes is the physical drive attributes in the storecli show all output
+type storeCLIDriveAttributes struct {
+	SerialNumber      string `json:"SN"`
+	ManufacturerID    string `json:"Manufacturer Id"`
+	ModelNumber       string `json:"Model Number"`
+	WWN               string `json:"WWN"`
+	FirmwareRevision  string `json:"Firmware Revision"`
+	CoercedSize       string `json:"Coerced size"`
+	DeviceSpeed       string `json:"Device Speed"`
+	LinkSpeed         string `json:"Link Speed"`
+	LogicalSectorSize string `json:"Logical Sector Size"`
+}
+
+// storeCLIVirtualDriveProperties is the virtual drive properties in the storecli show all output
+type storeCLIVirtualDriveProperties struct {
+	NumberOfBlocks int64 `json:"Number of Blocks"`
 }
 
 // Return a new storecli executor
@@ -85,11 +166,129 @@ func NewFakeStoreCLI(r io.Reader) (*StoreCLI, error) {
 	}, nil
 }
 
-// StorageControllers returns a slice of model.StorageControllers from the output of nvme list
+// FakeStoreCLIExecute implements the utils.Executor interface for testing,
+// the storecli output is read from the JSON files in JSONFilesDir based on the command args.
+type FakeStoreCLIExecute struct {
+	Cmd          string
+	Args         []string
+	Stdout       []byte
+	JSONFilesDir string
+	// Commands records the args of each command executed
+	Commands []string
+	// Executor embedded in here to skip having to implement all the utils.Executor methods
+	Executor
+}
+
+// NewFakeStoreCLIExecutor returns a fake storecli executor for tests
+func NewFakeStoreCLIExecutor(cmd, dir string) *FakeStoreCLIExecute {
+	return &FakeStoreCLIExecute{Cmd: cmd, JSONFilesDir: dir}
+}
+
+// Exec implements the utils.Executor interface
+func (e *FakeStoreCLIExecute) Exec(context.Context) (*Result, error) {
+	if len(e.Args) < 2 {
+		return nil, ErrFakeExecutorInvalidArgs
+	}
+
+	e.Commands = append(e.Commands, strings.Join(e.Args, " "))
+
+	var f string
+
+	switch {
+	case e.Args[0] == "/call" && e.Args[1] == "show":
+		f = "show_all.json"
+	case e.Args[0] == "/call/eall/sall":
+		f = "drives.json"
+	case strings.HasSuffix(e.Args[0], "/vall"):
+		f = "vds.json"
+	case e.Args[1] == "show":
+		// /c0 show J
+		f = "show_" + strings.TrimPrefix(e.Args[0], "/") + ".json"
+	case e.Args[1] == "add":
+		f = "add_vd.json"
+	case e.Args[1] == "del":
+		f = "del_vd.json"
+	case e.Args[1] == "download":
+		f = "download.json"
+	default:
+		return nil, ErrFakeExecutorInvalidArgs
+	}
+
+	b, err := os.ReadFile(e.JSONFilesDir + "/" + f)
+	if err != nil {
+		return nil, err
+	}
+
+	e.Stdout = b
+
+	return &Result{Stdout: e.Stdout}, nil
+}
+
+// CheckExecutable implements the Executor interface
+func (e *FakeStoreCLIExecute) CheckExecutable() error {
+	return nil
+}
+
+// SetArgs is to set cmd args to the fake execute method
+func (e *FakeStoreCLIExecute) SetArgs(a ...string) {
+	e.Args = a
+}
+
+func (e *FakeStoreCLIExecute) CmdPath() string {
+	return e.Cmd
+}
+
+func (e *FakeStoreCLIExecute) GetCmd() string {
+	return strings.Join(append([]string{e.Cmd}, e.Args...), " ")
+}
+
+// SetController sets the controller number the virtual disks are managed on
+func (s *StoreCLI) SetController(controller string) {
+	s.Controller = controller
+}
+
+// SetDryRun sets the updater to return the update commands instead of executing them
+func (s *StoreCLI) SetDryRun(dryRun bool) {
+	s.DryRun = dryRun
+}
+
+// ResolveController sets the controller number of the controller identified by its serial or PCI bus address,
+// ErrStoreCLIControllerNotFound is returned when none of the controllers listed by storecli match.
+func (s *StoreCLI) ResolveController(ctx context.Context, serial, busInfo string) error {
+	out, err := s.ShowControllers(ctx)
+	if err != nil {
+		return err
+	}
+
+	list := &ShowController{}
+	if err := json.Unmarshal(out, list); err != nil {
+		return err
+	}
+
+	busDeviceFunction := pciBusDeviceFunction(busInfo)
+
+	for _, c := range list.Controllers {
+		if c.CommandStatus == nil || c.ResponseData == nil {
+			continue
+		}
+
+		serialMatch := serial != "" && strings.EqualFold(serial, c.ResponseData.SerialNumber)
+		busMatch := busDeviceFunction != "" && busDeviceFunction == pciBusDeviceFunction(c.ResponseData.PCIAddress)
+
+		if serialMatch || busMatch {
+			s.Controller = strconv.Itoa(c.CommandStatus.Controller)
+			return nil
+		}
+	}
+
+	return errors.Wrap(ErrStoreCLIControllerNotFound, "serial: "+serial+" bus: "+busInfo)
+}
+
+// StorageControllers returns a slice of model.StorageControllers from the output of storecli /call show
 func (s *StoreCLI) StorageControllers(ctx context.Context) ([]*common.StorageController, error) {
 	controllers := make([]*common.StorageController, 0)
 
-	out, err := s.ShowController0(ctx)
+	out, err := s.ShowControllers(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -118,6 +317,7 @@ func (s *StoreCLI) StorageControllers(ctx context.Context) ([]*common.StorageCon
 					Metadata:  map[string]string{"bios_version": c.ResponseData.BIOSVersion},
 				},
 			},
+			ID: strconv.Itoa(c.CommandStatus.Controller),
 		}
 		controllers = append(controllers, item)
 	}
@@ -137,3 +337,413 @@ func (s *StoreCLI) ShowController0(ctx context.Context) ([]byte, error) {
 
 	return result.Stdout, nil
 }
+
+// ShowControllers runs storecli to list all controllers
+func (s *StoreCLI) ShowControllers(ctx context.Context) ([]byte, error) {
+	// /opt/MegaRAID/storcli/storcli64 /call show J
+	s.Executor.SetArgs("/call", "show", "J")
+
+	result, err := s.Executor.Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Stdout, nil
+}
+
+// Drives returns the physical drives attached to all controllers,
+// the drive enclosure:slot is included in the drive metadata.
+//
+// This method implements the actions.DriveCollector interface.
+func (s *StoreCLI) Drives(ctx context.Context) ([]*common.Drive, error) {
+	// /opt/MegaRAID/storcli/storcli64 /call/eall/sall show all J
+	response, err := s.exec(ctx, "/call/eall/sall", "show", "all", "J")
+	if err != nil {
+		return nil, err
+	}
+
+	drives := []*common.Drive{}
+
+	for _, c := range response.Controllers {
+		if c.CommandStatus == nil || c.CommandStatus.Status != "Success" {
+			continue
+		}
+
+		for _, key := range slices.Sorted(maps.Keys(c.ResponseData)) {
+			match := storeCLIDriveKey.FindStringSubmatch(key)
+			if match == nil {
+				continue
+			}
+
+			pds := []*StoreCLIPhysicalDrive{}
+			if err := json.Unmarshal(c.ResponseData[key], &pds); err != nil {
+				return nil, err
+			}
+
+			if len(pds) == 0 {
+				continue
+			}
+
+			attributes, err := storeCLIDriveDetails(c.ResponseData, match[1])
+			if err != nil {
+				return nil, err
+			}
+
+			drives = append(drives, storeCLIDrive(match[2], pds[0], attributes))
+		}
+	}
+
+	// order the drives by controller and device ID
+	slices.SortStableFunc(drives, func(a, b *common.Drive) int {
+		return cmp.Or(cmp.Compare(a.StorageController, b.StorageController), cmp.Compare(a.StorageControllerDriveID, b.StorageControllerDriveID))
+	})
+
+	return drives, nil
+}
+
+// storeCLIDriveDetails returns the device attributes from the detailed information of the drive
+func storeCLIDriveDetails(responseData map[string]json.RawMessage, drivePath string) (*storeCLIDriveAttributes, error) {
+	attributes := &storeCLIDriveAttributes{}
+
+	raw, exists := responseData["Drive "+drivePath+" - Detailed Information"]
+	if !exists {
+		return attributes, nil
+	}
+
+	details := map[string]json.RawMessage{}
+	if err := json.Unmarshal(raw, &details); err != nil {
+		return nil, err
+	}
+
+	if raw, exists := details["Drive "+drivePath+" Device attributes"]; exists {
+		if err := json.Unmarshal(raw, attributes); err != nil {
+			return nil, err
+		}
+	}
+
+	return attributes, nil
+}
+
+func storeCLIDrive(controller string, pd *StoreCLIPhysicalDrive, attributes *storeCLIDriveAttributes) *common.Drive {
+	modelName := cmp.Or(strings.TrimSpace(attributes.ModelNumber), strings.TrimSpace(pd.Model))
+	protocol := strings.ToLower(pd.Interface)
+
+	drive := &common.Drive{
+		Common: common.Common{
+			Model:       modelName,
+			Vendor:      common.VendorFromString(modelName),
+			Description: modelName,
+			Serial:      strings.TrimSpace(attributes.SerialNumber),
+			Firmware:    &common.Firmware{Installed: strings.TrimSpace(attributes.FirmwareRevision)},
+			Metadata:    map[string]string{"enclosure_slot": pd.EnclosureSlot},
+		},
+
+		Type:                     storeCLIDriveType(protocol, pd.Medium),
+		Protocol:                 protocol,
+		WWN:                      strings.TrimSpace(attributes.WWN),
+		StorageController:        controller,
+		StorageControllerDriveID: pd.DID,
+		BlockSizeBytes:           storeCLIBytes(cmp.Or(attributes.LogicalSectorSize, pd.SectorSize)),
+		CapableSpeedGbps:         storeCLISpeed(attributes.DeviceSpeed),
+		NegotiatedSpeedGbps:      storeCLISpeed(attributes.LinkSpeed),
+	}
+
+	if match := storeCLISectors.FindStringSubmatch(attributes.CoercedSize); match != nil {
+		if sectors, err := strconv.ParseInt(match[1], 16, 64); err == nil {
+			drive.CapacityBytes = sectors * drive.BlockSizeBytes
+		}
+	}
+
+	if drive.Vendor == "" {
+		drive.Vendor = common.VendorFromString(attributes.ManufacturerID)
+	}
+
+	return drive
+}
+
+// ListVirtualDisks returns the virtual disks on the controller set, or on all controllers when the controller is not set.
+//
+// The virtual disk IDs are the virtual drive numbers, these are unique on a controller.
+//
+// This method implements the actions.VirtualDiskManager interface.
+func (s *StoreCLI) ListVirtualDisks(ctx context.Context) ([]*common.VirtualDisk, error) {
+	controller := "/call"
+	if s.Controller != "" {
+		controller = "/c" + s.Controller
+	}
+
+	// /opt/MegaRAID/storcli/storcli64 /c0/vall show all J
+	response, err := s.exec(ctx, controller+"/vall", "show", "all", "J")
+	if err != nil {
+		return nil, err
+	}
+
+	virtualDisks := []*common.VirtualDisk{}
+
+	for _, c := range response.Controllers {
+		// controllers without virtual drives report a failure status
+		if c.CommandStatus == nil || c.CommandStatus.Status != "Success" {
+			continue
+		}
+
+		for _, key := range slices.Sorted(maps.Keys(c.ResponseData)) {
+			match := storeCLIVirtualDriveKey.FindStringSubmatch(key)
+			if match == nil {
+				continue
+			}
+
+			vds := []*StoreCLIVirtualDrive{}
+			if err := json.Unmarshal(c.ResponseData[key], &vds); err != nil {
+				return nil, err
+			}
+
+			if len(vds) == 0 {
+				continue
+			}
+
+			vd, err := storeCLIVirtualDisk(c.ResponseData, match[1], match[2], vds[0])
+			if err != nil {
+				return nil, err
+			}
+
+			virtualDisks = append(virtualDisks, vd)
+		}
+	}
+
+	return virtualDisks, nil
+}
+
+// storeCLIVirtualDisk returns the virtual drive as a common.VirtualDisk including its physical drives and size
+func storeCLIVirtualDisk(responseData map[string]json.RawMessage, controller, id string, vdrive *StoreCLIVirtualDrive) (*common.VirtualDisk, error) {
+	pds := []*StoreCLIPhysicalDrive{}
+	if raw, exists := responseData["PDs for VD "+id]; exists {
+		if err := json.Unmarshal(raw, &pds); err != nil {
+			return nil, err
+		}
+	}
+
+	properties := &storeCLIVirtualDriveProperties{}
+	if raw, exists := responseData["VD"+id+" Properties"]; exists {
+		if err := json.Unmarshal(raw, properties); err != nil {
+			return nil, err
+		}
+	}
+
+	vd := &common.VirtualDisk{
+		ID:             id,
+		Name:           vdrive.Name,
+		RaidType:       vdrive.Type,
+		Status:         vdrive.State,
+		PhysicalDrives: []*common.Drive{},
+	}
+
+	for _, pd := range pds {
+		vd.PhysicalDrives = append(vd.PhysicalDrives, storeCLIDrive(controller, pd, &storeCLIDriveAttributes{}))
+	}
+
+	if len(pds) > 0 {
+		vd.SizeBytes = properties.NumberOfBlocks * storeCLIBytes(pds[0].SectorSize)
+	}
+
+	return vd, nil
+}
+
+// CreateVirtualDisk creates a virtual disk on the controller set with the given physical drives,
+//
+// the physicalDisks are the storecli device IDs (DID) of the drives, the raidMode is the RAID level - 0, 1, 5, 6, 00, 10, 50, 60
+// and the blockSize is the strip size in KB, the controller default strip size is used when 0.
+//
+// This method implements the actions.VirtualDiskManager interface.
+func (s *StoreCLI) CreateVirtualDisk(ctx context.Context, raidMode string, physicalDisks []uint, name string, blockSize uint) error {
+	if s.Controller == "" {
+		return ErrStoreCLIControllerUnset
+	}
+
+	raidMode = strings.TrimPrefix(strings.ToLower(raidMode), "raid")
+	if !slices.Contains(storeCLIRaidModes, raidMode) {
+		return InvalidRaidModeError(raidMode)
+	}
+
+	if blockSize != 0 && !slices.Contains(storeCLIStripSizes, blockSize) {
+		return InvalidBlockSizeError(blockSize)
+	}
+
+	drives, err := s.enclosureSlots(ctx, physicalDisks)
+	if err != nil {
+		return err
+	}
+
+	// /opt/MegaRAID/storcli/storcli64 /c0 add vd type=raid1 drives=252:0,252:1 name=os strip=64 J
+	args := []string{"/c" + s.Controller, "add", "vd", "type=raid" + raidMode, "drives=" + strings.Join(drives, ",")}
+
+	if name != "" {
+		args = append(args, "name="+name)
+	}
+
+	if blockSize != 0 {
+		args = append(args, "strip="+strconv.FormatUint(uint64(blockSize), 10))
+	}
+
+	if _, err := s.exec(ctx, append(args, "J")...); err != nil {
+		return errors.Wrap(ErrCreateVirtualDisk, err.Error())
+	}
+
+	return nil
+}
+
+// DestroyVirtualDisk deletes the virtual disk from the controller set
+//
+// This method implements the actions.VirtualDiskManager interface.
+func (s *StoreCLI) DestroyVirtualDisk(ctx context.Context, virtualDiskID int) error {
+	if s.Controller == "" {
+		return ErrStoreCLIControllerUnset
+	}
+
+	if virtualDiskID < 0 {
+		return InvalidVirtualDiskIDError(virtualDiskID)
+	}
+
+	// /opt/MegaRAID/storcli/storcli64 /c0/v1 del force J
+	if _, err := s.exec(ctx, fmt.Sprintf("/c%s/v%d", s.Controller, virtualDiskID), "del", "force", "J"); err != nil {
+		return errors.Wrap(ErrDestroyVirtualDisk, err.Error())
+	}
+
+	return nil
+}
+
+// UpdateStorageController installs the controller firmware package on the controller set - see ResolveController,
+// ErrRebootRequired is returned once installed since the firmware is activated on the next host reboot,
+// ErrStoreCLIControllerUnset is returned when the controller is not set.
+//
+// This method implements the actions.StorageControllerUpdater interface.
+func (s *StoreCLI) UpdateStorageController(ctx context.Context, updateFile, _ string) error {
+	// /opt/MegaRAID/storcli/storcli64 /c0 download file=mr3.rom J
+	if s.Controller == "" {
+		return ErrStoreCLIControllerUnset
+	}
+
+	args := []string{"/c" + s.Controller, "download", "file=" + updateFile, "J"}
+
+	if s.DryRun {
+		s.Executor.SetArgs(args...)
+		return updateDryRunError(s.Executor)
+	}
+
+	if _, err := s.exec(ctx, args...); err != nil {
+		return err
+	}
+
+	return rebootRequiredError(common.SlugStorageController)
+}
+
+// enclosureSlots returns the enclosure:slot of the physical drives identified by their storecli device ID (DID)
+func (s *StoreCLI) enclosureSlots(ctx context.Context, physicalDisks []uint) ([]string, error) {
+	// /opt/MegaRAID/storcli/storcli64 /c0 show J
+	s.Executor.SetArgs("/c"+s.Controller, "show", "J")
+
+	result, err := s.Executor.Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &ShowController{}
+	if err := json.Unmarshal(result.Stdout, list); err != nil {
+		return nil, err
+	}
+
+	pds := []*StoreCLIPhysicalDrive{}
+	for _, c := range list.Controllers {
+		if c.ResponseData != nil {
+			pds = append(pds, c.ResponseData.PDList...)
+		}
+	}
+
+	slots := make([]string, 0, len(physicalDisks))
+
+	for _, did := range physicalDisks {
+		idx := slices.IndexFunc(pds, func(pd *StoreCLIPhysicalDrive) bool { return pd.DID == int(did) })
+		if idx < 0 {
+			return nil, errors.Wrap(ErrStoreCLIDriveNotFound, fmt.Sprintf("controller: %s, DID: %d", s.Controller, did))
+		}
+
+		slots = append(slots, pds[idx].EnclosureSlot)
+	}
+
+	return slots, nil
+}
+
+// exec runs storecli with the given args and returns the parsed output,
+// an error is returned when the command status of any of the controllers is a failure.
+func (s *StoreCLI) exec(ctx context.Context, args ...string) (*storeCLIResponse, error) {
+	s.Executor.SetArgs(args...)
+
+	// storecli exits with a non-zero status when the command fails, the failure is described in the output
+	result, err := s.Executor.Exec(ctx)
+	if result == nil || len(result.Stdout) == 0 {
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, errors.Wrap(ErrNoCommandOutput, s.Executor.GetCmd())
+	}
+
+	response := &storeCLIResponse{}
+	if err := json.Unmarshal(result.Stdout, response); err != nil {
+		return nil, err
+	}
+
+	// listing commands report a failure for the controllers that have none of the objects listed,
+	// these are left to the caller.
+	if slices.Contains(args, "show") {
+		return response, nil
+	}
+
+	for _, c := range response.Controllers {
+		if c.CommandStatus != nil && c.CommandStatus.Status != "Success" {
+			return nil, errors.Wrap(ErrStoreCLICommandFailed, s.Executor.GetCmd()+": "+c.CommandStatus.Description)
+		}
+	}
+
+	return response, err
+}
+
+// storeCLIDriveType returns the drive type slug for the drive interface and medium
+func storeCLIDriveType(protocol, medium string) string {
+	switch {
+	case protocol == "sata" && medium == "SSD":
+		return common.SlugDriveTypeSATASSD
+	case protocol == "sata" && medium == "HDD":
+		return common.SlugDriveTypeSATAHDD
+	case protocol == "nvme":
+		return common.SlugDriveTypePCIeNVMEeSSD
+	default:
+		return "Unknown"
+	}
+}
+
+// storeCLIBytes returns the number of bytes in a storecli sector size - 512B, 4 KB
+func storeCLIBytes(s string) int64 {
+	s = strings.ToUpper(strings.ReplaceAll(s, " ", ""))
+
+	multiplier := int64(1)
+	if strings.HasSuffix(s, "KB") {
+		multiplier = 1024
+	}
+
+	n, err := strconv.ParseInt(strings.TrimRight(s, "KB"), 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return n * multiplier
+}
+
+// storeCLISpeed returns the Gbps in a storecli speed - 6.0Gb/s
+func storeCLISpeed(s string) int64 {
+	f, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(s), "Gb/s"), 64)
+	if err != nil {
+		return 0
+	}
+
+	return int64(f)
+}
@@ -7,12 +7,22 @@ import (
 	"testing"
 
 	common "github.com/metal-toolbox/bmc-common"
+	"github.com/metal-toolbox/ironlib/errs"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+const storeCLIFixturesDir = "../fixtures/utils/storecli"
+
+func newFakeStoreCLIFromDir(dir string) (*StoreCLI, *FakeStoreCLIExecute) {
+	e := NewFakeStoreCLIExecutor("storecli", dir)
+
+	return &StoreCLI{Executor: e}, e
+}
+
 func Test_StoreCLIDeviceAttributes(t *testing.T) {
 	expected := []*common.StorageController{
-		{Common: common.Common{Serial: "500304801c71e8d0", Vendor: "lsi", Model: "LSI3008-IT", Description: "LSI3008-IT", Metadata: map[string]string{"drives_attached": "12"}, Firmware: &common.Firmware{Installed: "16.00.01.00", Metadata: map[string]string{"bios_version": "08.37.00.00_18.00.00.00"}}}},
+		{Common: common.Common{Serial: "500304801c71e8d0", Vendor: "lsi", Model: "LSI3008-IT", Description: "LSI3008-IT", Metadata: map[string]string{"drives_attached": "12"}, Firmware: &common.Firmware{Installed: "16.00.01.00", Metadata: map[string]string{"bios_version": "08.37.00.00_18.00.00.00"}}}, ID: "0"},
 	}
 
 	b, err := os.ReadFile("../fixtures/utils/storecli/show.json")
@@ -51,3 +61,221 @@ func Test_StoreCLIDeviceAttributesNoControllers(t *testing.T) {
 
 	assert.Equal(t, 0, len(inventory))
 }
+
+func Test_StoreCLIStorageControllers(t *testing.T) {
+	cli, _ := newFakeStoreCLIFromDir(storeCLIFixturesDir)
+
+	controllers, err := cli.StorageControllers(context.TODO())
+	require.NoError(t, err)
+	require.Len(t, controllers, 2)
+
+	assert.Equal(t, "0", controllers[0].ID)
+	assert.Equal(t, "MegaRAID 9560-8i 4GB", controllers[0].Model)
+	assert.Equal(t, "SKC4518239", controllers[0].Serial)
+	assert.Equal(t, "5.240.02-3768", controllers[0].Firmware.Installed)
+	assert.Equal(t, "1", controllers[1].ID)
+	assert.Equal(t, "LSI3008-IT", controllers[1].Model)
+}
+
+func Test_StoreCLIDrives(t *testing.T) {
+	cli, _ := newFakeStoreCLIFromDir(storeCLIFixturesDir)
+
+	drives, err := cli.Drives(context.TODO())
+	require.NoError(t, err)
+	require.Len(t, drives, 3)
+
+	expected := &common.Drive{
+		Common: common.Common{
+			Model:       "MTFDDAK480TDS-1AW1ZA 02JG538D7A44703LEN",
+			Vendor:      "micron",
+			Description: "MTFDDAK480TDS-1AW1ZA 02JG538D7A44703LEN",
+			Serial:      "2139311B4A1F",
+			Firmware:    &common.Firmware{Installed: "MN01"},
+			Metadata:    map[string]string{"enclosure_slot": "252:0"},
+		},
+		Type:                     common.SlugDriveTypeSATASSD,
+		Protocol:                 "sata",
+		WWN:                      "500A07512B4A1F2D",
+		StorageController:        "0",
+		StorageControllerDriveID: 8,
+		BlockSizeBytes:           512,
+		CapacityBytes:            479559942144,
+		CapableSpeedGbps:         6,
+		NegotiatedSpeedGbps:      6,
+	}
+
+	assert.Equal(t, expected, drives[0])
+
+	// drives are ordered by their device ID
+	assert.Equal(t, "252:1", drives[1].Metadata["enclosure_slot"])
+	assert.Equal(t, 9, drives[1].StorageControllerDriveID)
+	assert.Equal(t, "VRGXKZ8K", drives[2].Serial)
+	assert.Equal(t, common.SlugDriveTypeSATAHDD, drives[2].Type)
+	assert.Equal(t, int64(8001524072448), drives[2].CapacityBytes)
+}
+
+func Test_StoreCLIListVirtualDisks(t *testing.T) {
+	cli, e := newFakeStoreCLIFromDir(storeCLIFixturesDir)
+	cli.SetController("0")
+
+	virtualDisks, err := cli.ListVirtualDisks(context.TODO())
+	require.NoError(t, err)
+	require.Len(t, virtualDisks, 1)
+
+	assert.Equal(t, []string{"/c0/vall show all J"}, e.Commands)
+
+	vd := virtualDisks[0]
+	assert.Equal(t, "0", vd.ID)
+	assert.Equal(t, "os", vd.Name)
+	assert.Equal(t, "RAID1", vd.RaidType)
+	assert.Equal(t, "Optl", vd.Status)
+	assert.Equal(t, int64(479559942144), vd.SizeBytes)
+	require.Len(t, vd.PhysicalDrives, 2)
+	assert.Equal(t, "252:0", vd.PhysicalDrives[0].Metadata["enclosure_slot"])
+	assert.Equal(t, 9, vd.PhysicalDrives[1].StorageControllerDriveID)
+}
+
+func Test_StoreCLICreateVirtualDisk(t *testing.T) {
+	testcases := []struct {
+		name       string
+		controller string
+		raidMode   string
+		disks      []uint
+		blockSize  uint
+		wantCmd    string
+		wantErr    error
+	}{
+		{
+			name:       "raid1 with strip size",
+			controller: "0",
+			raidMode:   "RAID1",
+			disks:      []uint{8, 9},
+			blockSize:  64,
+			wantCmd:    "/c0 add vd type=raid1 drives=252:0,252:1 name=os strip=64 J",
+		},
+		{
+			name:       "raid0 controller default strip size",
+			controller: "0",
+			raidMode:   "0",
+			disks:      []uint{10},
+			wantCmd:    "/c0 add vd type=raid0 drives=252:2 name=os J",
+		},
+		{
+			name:     "controller unset",
+			raidMode: "1",
+			disks:    []uint{8, 9},
+			wantErr:  ErrStoreCLIControllerUnset,
+		},
+		{
+			name:       "invalid raid mode",
+			controller: "0",
+			raidMode:   "7",
+			disks:      []uint{8, 9},
+			wantErr:    ErrInvalidRaidMode,
+		},
+		{
+			name:       "invalid strip size",
+			controller: "0",
+			raidMode:   "1",
+			disks:      []uint{8, 9},
+			blockSize:  48,
+			wantErr:    ErrInvalidBlockSize,
+		},
+		{
+			name:       "drive not found",
+			controller: "0",
+			raidMode:   "1",
+			disks:      []uint{8, 42},
+			wantErr:    ErrStoreCLIDriveNotFound,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cli, e := newFakeStoreCLIFromDir(storeCLIFixturesDir)
+			cli.SetController(tc.controller)
+
+			err := cli.CreateVirtualDisk(context.TODO(), tc.raidMode, tc.disks, "os", tc.blockSize)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, []string{"/c0 show J", tc.wantCmd}, e.Commands)
+		})
+	}
+}
+
+func Test_StoreCLIDestroyVirtualDisk(t *testing.T) {
+	cli, e := newFakeStoreCLIFromDir(storeCLIFixturesDir)
+
+	err := cli.DestroyVirtualDisk(context.TODO(), 0)
+	assert.ErrorIs(t, err, ErrStoreCLIControllerUnset)
+
+	cli.SetController("0")
+
+	err = cli.DestroyVirtualDisk(context.TODO(), -1)
+	assert.ErrorIs(t, err, ErrInvalidVirtualDiskID)
+
+	err = cli.DestroyVirtualDisk(context.TODO(), 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/c0/v0 del force J"}, e.Commands)
+}
+
+func Test_StoreCLIUpdateStorageController(t *testing.T) {
+	cli, e := newFakeStoreCLIFromDir(storeCLIFixturesDir)
+
+	// the controller is not defaulted
+	err := cli.UpdateStorageController(context.TODO(), "/tmp/mr3.rom", "")
+	require.ErrorIs(t, err, ErrStoreCLIControllerUnset)
+
+	cli.SetController("1")
+
+	// the firmware is activated on the next host reboot
+	err = cli.UpdateStorageController(context.TODO(), "/tmp/mr3.rom", "")
+	require.ErrorIs(t, err, ErrRebootRequired)
+
+	assert.Equal(t, []string{"/c1 download file=/tmp/mr3.rom J"}, e.Commands)
+}
+
+func Test_StoreCLIResolveController(t *testing.T) {
+	testcases := []struct {
+		name    string
+		serial  string
+		busInfo string
+		want    string
+		wantErr error
+	}{
+		{"serial", "500304801C71E8D0", "", "1", nil},
+		{"lshw bus info", "1000:10e2", "pci@0000:65:00.0", "0", nil},
+		{"not listed", "SKC0000000", "pci@0000:5e:00.0", "", ErrStoreCLIControllerNotFound},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cli, _ := newFakeStoreCLIFromDir(storeCLIFixturesDir)
+
+			err := cli.ResolveController(context.TODO(), tc.serial, tc.busInfo)
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.want, cli.Controller)
+		})
+	}
+}
+
+func Test_StoreCLIUpdateStorageControllerDryRun(t *testing.T) {
+	cli, e := newFakeStoreCLIFromDir(storeCLIFixturesDir)
+	cli.SetController("1")
+	cli.SetDryRun(true)
+
+	err := cli.UpdateStorageController(context.TODO(), "/tmp/mr3.rom", "")
+
+	dryRun := &errs.UpdateDryRunError{}
+	require.ErrorAs(t, err, &dryRun)
+	require.Len(t, dryRun.Commands, 1)
+
+	assert.Equal(t, "storecli /c1 download file=/tmp/mr3.rom J", dryRun.Commands[0].Cmd)
+
+	// no commands executed
+	assert.Empty(t, e.Commands)
+}
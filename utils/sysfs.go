@@ -0,0 +1,491 @@
+package utils
+
+import (
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	common "github.com/metal-toolbox/bmc-common"
+	"github.com/pkg/errors"
+
+	"github.com/metal-toolbox/ironlib/model"
+)
+
+const (
+	sysfsRoot = "/sys"
+
+	sysfsPCIDevices = "bus/pci/devices"
+	sysfsNet        = "class/net"
+	sysfsBlock      = "class/block"
+	sysfsCPU        = "devices/system/cpu"
+
+	// PCI class codes (class, subclass) of the devices collected
+	pciClassStorageRAID = 0x0104
+	pciClassStorageSATA = 0x0106
+	pciClassStorageSAS  = 0x0107
+	pciClassDisplay3D   = 0x0302
+)
+
+var (
+	ErrSysfsCollect = errors.New("sysfs inventory collect error")
+
+	sysfsCPUDirRegex = regexp.MustCompile(`^cpu[0-9]+$`)
+
+	// pciVendors are the names of the PCI vendor IDs commonly found in servers
+	pciVendors = map[string]string{
+		"8086": common.VendorIntel,
+		"1022": common.VendorAMD,
+		"15b3": common.VendorMellanox,
+		"14e4": common.VendorBroadcom,
+		"1000": common.VendorLSI,
+		"1b4b": common.VendorMarvell,
+		"144d": common.VendorSamsung,
+		"1344": common.VendorMicron,
+		"1179": common.VendorToshiba,
+		"1c5c": common.VendorHynix,
+		"10de": "nvidia",
+	}
+)
+
+// Sysfs is an inventory collector that reads the PCI, network, block and CPU devices from sysfs,
+// it does not depend on any utility and is an alternative to the lshw inventory collector.
+type Sysfs struct {
+	// Root is the sysfs mount point, set to a captured sysfs tree to collect offline.
+	Root string
+}
+
+// sysfsPCIDevice is a PCI device listed under /sys/bus/pci/devices
+type sysfsPCIDevice struct {
+	address   string
+	class     uint32
+	vendorID  string
+	productID string
+	driver    string
+}
+
+// NewSysfsCollector returns a sysfs inventory collector
+func NewSysfsCollector() *Sysfs {
+	return &Sysfs{Root: sysfsRoot}
+}
+
+// Attributes implements the actions.UtilAttributeGetter interface
+func (s *Sysfs) Attributes() (utilName model.CollectorUtility, absolutePath string, err error) {
+	_, err = os.Stat(filepath.Join(s.Root, sysfsPCIDevices))
+
+	return "sysfs", s.Root, err
+}
+
+// Collect collects the device PCI storage controllers and GPUs, NICs, drives and CPUs from sysfs
+//
+// Implements the InventoryCollector interface
+func (s *Sysfs) Collect(ctx context.Context, device *common.Device) error {
+	pciDevices, err := s.pciDevices()
+	if err != nil {
+		return errors.Wrap(ErrSysfsCollect, err.Error())
+	}
+
+	for _, pci := range sortedPCIDevices(pciDevices) {
+		switch pci.class >> 8 {
+		case pciClassStorageRAID, pciClassStorageSATA, pciClassStorageSAS:
+			device.StorageControllers = append(device.StorageControllers, pci.storageController())
+		case pciClassDisplay3D:
+			device.GPUs = append(device.GPUs, pci.gpu())
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	nics, err := s.nics(pciDevices)
+	if err != nil {
+		return errors.Wrap(ErrSysfsCollect, err.Error())
+	}
+
+	device.NICs = append(device.NICs, nics...)
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	drives, err := s.drives()
+	if err != nil {
+		return errors.Wrap(ErrSysfsCollect, err.Error())
+	}
+
+	device.Drives = append(device.Drives, drives...)
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	cpus, err := s.cpus()
+	if err != nil {
+		return errors.Wrap(ErrSysfsCollect, err.Error())
+	}
+
+	device.CPUs = append(device.CPUs, cpus...)
+
+	return nil
+}
+
+// read returns the trimmed contents of the sysfs attribute file, or an empty string if it can't be read
+func (s *Sysfs) read(elem ...string) string {
+	b, err := os.ReadFile(filepath.Join(append([]string{s.Root}, elem...)...))
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(b))
+}
+
+// readInt returns the sysfs attribute file value as an integer, or 0 if it can't be read
+func (s *Sysfs) readInt(elem ...string) int64 {
+	v, err := strconv.ParseInt(s.read(elem...), 0, 64)
+	if err != nil {
+		return 0
+	}
+
+	return v
+}
+
+// exists returns true if the sysfs file exists
+func (s *Sysfs) exists(elem ...string) bool {
+	_, err := os.Stat(filepath.Join(append([]string{s.Root}, elem...)...))
+	return err == nil
+}
+
+// link returns the base name of the sysfs symlink target
+func (s *Sysfs) link(elem ...string) string {
+	target, err := os.Readlink(filepath.Join(append([]string{s.Root}, elem...)...))
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Base(target)
+}
+
+func (s *Sysfs) pciDevices() (map[string]*sysfsPCIDevice, error) {
+	entries, err := os.ReadDir(filepath.Join(s.Root, sysfsPCIDevices))
+	if err != nil {
+		return nil, err
+	}
+
+	devices := map[string]*sysfsPCIDevice{}
+
+	for _, entry := range entries {
+		address := entry.Name()
+
+		class, err := strconv.ParseUint(s.read(sysfsPCIDevices, address, "class"), 0, 32)
+		if err != nil {
+			continue
+		}
+
+		devices[address] = &sysfsPCIDevice{
+			address:   address,
+			class:     uint32(class),
+			vendorID:  strings.TrimPrefix(s.read(sysfsPCIDevices, address, "vendor"), "0x"),
+			productID: strings.TrimPrefix(s.read(sysfsPCIDevices, address, "device"), "0x"),
+			driver:    s.link(sysfsPCIDevices, address, "driver"),
+		}
+	}
+
+	return devices, nil
+}
+
+// sortedPCIDevices returns the PCI devices sorted by address
+func sortedPCIDevices(devices map[string]*sysfsPCIDevice) []*sysfsPCIDevice {
+	sorted := make([]*sysfsPCIDevice, 0, len(devices))
+	for _, d := range devices {
+		sorted = append(sorted, d)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].address < sorted[j].address })
+
+	return sorted
+}
+
+func (p *sysfsPCIDevice) vendor() string {
+	if v, exists := pciVendors[p.vendorID]; exists {
+		return v
+	}
+
+	return p.vendorID
+}
+
+func (p *sysfsPCIDevice) metadata() map[string]string {
+	if p.driver == "" {
+		return nil
+	}
+
+	return map[string]string{"driver": p.driver}
+}
+
+func (p *sysfsPCIDevice) storageController() *common.StorageController {
+	protocols := map[uint32]string{pciClassStorageSATA: "SATA", pciClassStorageSAS: "SAS"}
+
+	return &common.StorageController{
+		Common: common.Common{
+			Description:  "storage controller",
+			Vendor:       p.vendor(),
+			PCIVendorID:  p.vendorID,
+			PCIProductID: p.productID,
+			// the serial is derived from the PCI vendor/product id as the lshw collector does
+			Serial:   p.vendorID + ":" + p.productID,
+			Metadata: p.metadata(),
+		},
+
+		SupportedDeviceProtocols: protocols[p.class>>8],
+		BusInfo:                  "pci@" + p.address,
+	}
+}
+
+func (p *sysfsPCIDevice) gpu() *common.GPU {
+	metadata := map[string]string{"bus_info": "pci@" + p.address}
+	for k, v := range p.metadata() {
+		metadata[k] = v
+	}
+
+	return &common.GPU{
+		Common: common.Common{
+			Description:  "3D controller",
+			Vendor:       p.vendor(),
+			PCIVendorID:  p.vendorID,
+			PCIProductID: p.productID,
+			Metadata:     metadata,
+		},
+	}
+}
+
+// nics returns a NIC for each network interface backed by a PCI device,
+// virtual interfaces and interfaces on other buses are skipped.
+func (s *Sysfs) nics(pciDevices map[string]*sysfsPCIDevice) ([]*common.NIC, error) {
+	entries, err := os.ReadDir(filepath.Join(s.Root, sysfsNet))
+	if err != nil {
+		return nil, err
+	}
+
+	nics := []*common.NIC{}
+
+	for _, entry := range entries {
+		iface := entry.Name()
+
+		pci, exists := pciDevices[s.link(sysfsNet, iface, "device")]
+		if !exists {
+			continue
+		}
+
+		mac := s.read(sysfsNet, iface, "address")
+		if mac == "" {
+			continue
+		}
+
+		port := &common.NICPort{
+			ID:         iface,
+			BusInfo:    "pci@" + pci.address,
+			MacAddress: mac,
+			LinkStatus: s.read(sysfsNet, iface, "operstate"),
+			MTUSize:    int(s.readInt(sysfsNet, iface, "mtu")),
+		}
+
+		// speed is reported in Mbit/s, and -1 when the link is down
+		if speed := s.readInt(sysfsNet, iface, "speed"); speed > 0 {
+			port.SpeedBits = speed * 1000 * 1000
+		}
+
+		nics = append(nics, &common.NIC{
+			Common: common.Common{
+				Description:  "Ethernet interface",
+				Vendor:       pci.vendor(),
+				Serial:       mac,
+				PCIVendorID:  pci.vendorID,
+				PCIProductID: pci.productID,
+				Metadata:     pci.metadata(),
+			},
+			ID:       iface,
+			NICPorts: []*common.NICPort{port},
+		})
+	}
+
+	return nics, nil
+}
+
+// drives returns the disk block devices, partitions and virtual block devices are skipped.
+func (s *Sysfs) drives() ([]*common.Drive, error) {
+	entries, err := os.ReadDir(filepath.Join(s.Root, sysfsBlock))
+	if err != nil {
+		return nil, err
+	}
+
+	drives := []*common.Drive{}
+
+	for _, entry := range entries {
+		name := entry.Name()
+
+		// virtual devices (loop, ram, dm) have no backing device
+		if s.exists(sysfsBlock, name, "partition") || !s.exists(sysfsBlock, name, "device") {
+			continue
+		}
+
+		// NVMe multipath paths are hidden
+		if s.read(sysfsBlock, name, "hidden") == "1" {
+			continue
+		}
+
+		drive := &common.Drive{
+			Common: common.Common{
+				LogicalName: "/dev/" + name,
+			},
+			// the size is in 512 byte sectors regardless of the block size
+			CapacityBytes:            s.readInt(sysfsBlock, name, "size") * 512,
+			BlockSizeBytes:           s.readInt(sysfsBlock, name, "queue", "logical_block_size"),
+			StorageControllerDriveID: -1,
+		}
+
+		if strings.HasPrefix(name, "nvme") {
+			s.nvmeDrive(name, drive)
+		} else {
+			s.scsiDrive(name, drive)
+		}
+
+		drives = append(drives, drive)
+	}
+
+	return drives, nil
+}
+
+// nvmeDrive sets the drive attributes from the NVMe controller
+func (s *Sysfs) nvmeDrive(name string, drive *common.Drive) {
+	drive.Protocol = "nvme"
+	drive.Type = common.SlugDriveTypePCIeNVMEeSSD
+	drive.Model = s.read(sysfsBlock, name, "device", "model")
+	drive.Serial = s.read(sysfsBlock, name, "device", "serial")
+	drive.Vendor = common.VendorFromString(drive.Model)
+
+	if fw := s.read(sysfsBlock, name, "device", "firmware_rev"); fw != "" {
+		drive.Firmware = &common.Firmware{Installed: fw}
+	}
+}
+
+// scsiDrive sets the drive attributes from the SCSI device
+func (s *Sysfs) scsiDrive(name string, drive *common.Drive) {
+	vendor := s.read(sysfsBlock, name, "device", "vendor")
+	drive.Model = s.read(sysfsBlock, name, "device", "model")
+	drive.Serial = s.vpdSerial(name)
+
+	if fw := s.read(sysfsBlock, name, "device", "rev"); fw != "" {
+		drive.Firmware = &common.Firmware{Installed: fw}
+	}
+
+	rotational := s.read(sysfsBlock, name, "queue", "rotational") == "1"
+
+	switch {
+	// SATA drives are attached through the kernel SCSI ATA translation which reports the ATA vendor
+	case vendor == "ATA":
+		drive.Protocol = "sata"
+		drive.Vendor = common.VendorFromString(drive.Model)
+
+		drive.Type = common.SlugDriveTypeSATASSD
+		if rotational {
+			drive.Type = common.SlugDriveTypeSATAHDD
+		}
+	default:
+		drive.Protocol = "scsi"
+		drive.Vendor = common.FormatVendorName(vendor)
+
+		target, err := filepath.EvalSymlinks(filepath.Join(s.Root, sysfsBlock, name, "device"))
+		if err == nil && strings.Contains(target, "/end_device-") {
+			drive.Protocol = "sas"
+		}
+	}
+}
+
+// vpdSerial returns the serial number from the SCSI unit serial number VPD page
+func (s *Sysfs) vpdSerial(name string) string {
+	b, err := os.ReadFile(filepath.Join(s.Root, sysfsBlock, name, "device", "vpd_pg80"))
+	if err != nil || len(b) < 4 {
+		return ""
+	}
+
+	// the page length follows the 2 byte page header
+	length := int(binary.BigEndian.Uint16(b[2:4]))
+	if 4+length > len(b) {
+		return ""
+	}
+
+	return strings.TrimSpace(string(b[4 : 4+length]))
+}
+
+// cpus returns a CPU for each physical package with the cores and threads counted from the CPU topology
+func (s *Sysfs) cpus() ([]*common.CPU, error) {
+	entries, err := os.ReadDir(filepath.Join(s.Root, sysfsCPU))
+	if err != nil {
+		return nil, err
+	}
+
+	type pkg struct {
+		cpu   *common.CPU
+		cores map[string]bool
+	}
+
+	packages := map[int64]*pkg{}
+	ids := []int64{}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if !sysfsCPUDirRegex.MatchString(name) {
+			continue
+		}
+
+		// offline CPUs have no topology
+		pkgID := s.read(sysfsCPU, name, "topology", "physical_package_id")
+		if pkgID == "" {
+			continue
+		}
+
+		id, err := strconv.ParseInt(pkgID, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		p, exists := packages[id]
+		if !exists {
+			p = &pkg{
+				cpu: &common.CPU{
+					Common: common.Common{Description: "CPU"},
+					ID:     pkgID,
+				},
+				cores: map[string]bool{},
+			}
+
+			// the max frequency is in kHz
+			p.cpu.ClockSpeedHz = s.readInt(sysfsCPU, name, "cpufreq", "cpuinfo_max_freq") * 1000
+
+			if microcode := s.read(sysfsCPU, name, "microcode", "version"); microcode != "" {
+				p.cpu.Firmware = &common.Firmware{Installed: microcode}
+			}
+
+			packages[id] = p
+			ids = append(ids, id)
+		}
+
+		p.cores[s.read(sysfsCPU, name, "topology", "core_id")] = true
+		p.cpu.Threads++
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	cpus := make([]*common.CPU, 0, len(ids))
+	for _, id := range ids {
+		p := packages[id]
+		p.cpu.Cores = len(p.cores)
+		cpus = append(cpus, p.cpu)
+	}
+
+	return cpus, nil
+}
@@ -0,0 +1,163 @@
+package utils
+
+import (
+	"context"
+	"testing"
+
+	common "github.com/metal-toolbox/bmc-common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SysfsCollect(t *testing.T) {
+	s := &Sysfs{Root: "../fixtures/utils/sysfs"}
+
+	name, path, err := s.Attributes()
+	require.NoError(t, err)
+	assert.Equal(t, "sysfs", string(name))
+	assert.Equal(t, "../fixtures/utils/sysfs", path)
+
+	device := common.NewDevice()
+	require.NoError(t, s.Collect(context.Background(), &device))
+
+	assert.Equal(t, []*common.StorageController{
+		{
+			Common: common.Common{
+				Description:  "storage controller",
+				Vendor:       common.VendorIntel,
+				Serial:       "8086:a182",
+				PCIVendorID:  "8086",
+				PCIProductID: "a182",
+				Metadata:     map[string]string{"driver": "ahci"},
+			},
+			SupportedDeviceProtocols: "SATA",
+			BusInfo:                  "pci@0000:00:17.0",
+		},
+		{
+			Common: common.Common{
+				Description:  "storage controller",
+				Vendor:       common.VendorLSI,
+				Serial:       "1000:0097",
+				PCIVendorID:  "1000",
+				PCIProductID: "0097",
+				Metadata:     map[string]string{"driver": "mpt3sas"},
+			},
+			SupportedDeviceProtocols: "SAS",
+			BusInfo:                  "pci@0000:3b:00.0",
+		},
+	}, device.StorageControllers)
+
+	assert.Equal(t, []*common.GPU{
+		{
+			Common: common.Common{
+				Description:  "3D controller",
+				Vendor:       "nvidia",
+				PCIVendorID:  "10de",
+				PCIProductID: "20b5",
+				Metadata:     map[string]string{"bus_info": "pci@0000:af:00.0", "driver": "nvidia"},
+			},
+		},
+	}, device.GPUs)
+
+	assert.Equal(t, []*common.NIC{
+		{
+			Common: common.Common{
+				Description:  "Ethernet interface",
+				Vendor:       common.VendorMellanox,
+				Serial:       "b8:59:9f:de:2a:10",
+				PCIVendorID:  "15b3",
+				PCIProductID: "1015",
+				Metadata:     map[string]string{"driver": "mlx5_core"},
+			},
+			ID: "eno1",
+			NICPorts: []*common.NICPort{
+				{ID: "eno1", BusInfo: "pci@0000:5e:00.0", MacAddress: "b8:59:9f:de:2a:10", LinkStatus: "up", MTUSize: 9000, SpeedBits: 25000 * 1000 * 1000},
+			},
+		},
+		{
+			Common: common.Common{
+				Description:  "Ethernet interface",
+				Vendor:       common.VendorMellanox,
+				Serial:       "b8:59:9f:de:2a:11",
+				PCIVendorID:  "15b3",
+				PCIProductID: "1015",
+				Metadata:     map[string]string{"driver": "mlx5_core"},
+			},
+			ID: "eno2",
+			NICPorts: []*common.NICPort{
+				{ID: "eno2", BusInfo: "pci@0000:5e:00.1", MacAddress: "b8:59:9f:de:2a:11", LinkStatus: "down", MTUSize: 1500},
+			},
+		},
+	}, device.NICs)
+
+	assert.Equal(t, []*common.Drive{
+		{
+			Common: common.Common{
+				LogicalName: "/dev/nvme0n1",
+				Vendor:      "",
+				Model:       "SAMSUNG MZQLB960HAJR-00007",
+				Serial:      "S437NA0N101010",
+				Firmware:    &common.Firmware{Installed: "EDA5402Q"},
+			},
+			Type:                     common.SlugDriveTypePCIeNVMEeSSD,
+			Protocol:                 "nvme",
+			CapacityBytes:            1875385008 * 512,
+			BlockSizeBytes:           512,
+			StorageControllerDriveID: -1,
+		},
+		{
+			Common: common.Common{
+				LogicalName: "/dev/sda",
+				Vendor:      common.VendorMicron,
+				Model:       "Micron_5200_MTFDDAK480TDN",
+				Serial:      "18371E8B5A31",
+				Firmware:    &common.Firmware{Installed: "D1MU020"},
+			},
+			Type:                     common.SlugDriveTypeSATASSD,
+			Protocol:                 "sata",
+			CapacityBytes:            937703088 * 512,
+			BlockSizeBytes:           512,
+			StorageControllerDriveID: -1,
+		},
+		{
+			Common: common.Common{
+				LogicalName: "/dev/sdb",
+				Vendor:      "SEAGATE",
+				Model:       "ST12000NM0027",
+				Serial:      "ZJV0XXXX0000C8240000",
+				Firmware:    &common.Firmware{Installed: "E004"},
+			},
+			Protocol:                 "sas",
+			CapacityBytes:            23437770752 * 512,
+			BlockSizeBytes:           512,
+			StorageControllerDriveID: -1,
+		},
+	}, device.Drives)
+
+	assert.Equal(t, []*common.CPU{
+		{
+			Common:       common.Common{Description: "CPU", Firmware: &common.Firmware{Installed: "0x2006b06"}},
+			ID:           "0",
+			ClockSpeedHz: 3700000000,
+			Cores:        2,
+			Threads:      4,
+		},
+		{
+			Common:       common.Common{Description: "CPU", Firmware: &common.Firmware{Installed: "0x2006b06"}},
+			ID:           "1",
+			ClockSpeedHz: 3700000000,
+			Cores:        1,
+			Threads:      2,
+		},
+	}, device.CPUs)
+}
+
+func Test_SysfsCollectMissingRoot(t *testing.T) {
+	s := &Sysfs{Root: t.TempDir()}
+
+	_, _, err := s.Attributes()
+	assert.Error(t, err)
+
+	device := common.NewDevice()
+	assert.ErrorIs(t, s.Collect(context.Background(), &device), ErrSysfsCollect)
+}
@@ -15,6 +15,8 @@ import (
 
 var ErrIneffectiveWipe = errors.New("found left over data after wiping disk")
 
+const watermarkSize = int64(512)
+
 type watermark struct {
 	position int64
 	data     []byte
@@ -23,6 +25,16 @@ type watermark struct {
 // ApplyWatermarks applies random watermarks randomly through out the specified device/file.
 // It returns a function that checks if the applied watermarks still exists on the device/file.
 func ApplyWatermarks(drive *common.Drive) (func() error, error) {
+	watermarks, err := applyWatermarks(drive)
+	if err != nil {
+		return nil, err
+	}
+
+	return watermarksChecker(drive, watermarks), nil
+}
+
+// applyWatermarks writes random watermarks to the device/file and returns them
+func applyWatermarks(drive *common.Drive) ([]watermark, error) {
 	// Write open
 	file, err := os.OpenFile(drive.LogicalName, os.O_WRONLY|os.O_SYNC, 0)
 	if err != nil {
@@ -31,13 +43,28 @@ func ApplyWatermarks(drive *common.Drive) (func() error, error) {
 	defer file.Close()
 
 	// Write watermarks on random locations
-	watermarkSize := int64(512)
 	watermarks, err := writeWatermarks(file, 10, watermarkSize)
 	if err != nil {
 		return nil, err
 	}
 
-	checker := func() error {
+	// We introduce a 500-millisecond delay to give the OS enough time to properly flush the disk buffers to disk.
+	// While this delay helps ensure that the data is written, it is not an ideal solution, and further investigation is needed to find more efficient synchronization mechanisms.
+	err = file.Sync()
+	if err != nil {
+		return nil, err
+	}
+	err = file.Close()
+	if err != nil {
+		return nil, err
+	}
+	time.Sleep(500 * time.Millisecond)
+	return watermarks, nil
+}
+
+// watermarksChecker returns a function that checks if the given watermarks still exists on the device/file.
+func watermarksChecker(drive *common.Drive, watermarks []watermark) func() error {
+	return func() error {
 		// The delay gives the controller time to release I/O blocking, which could otherwise cause the verification process to fail due to incomplete or pending I/O operations.
 		time.Sleep(500 * time.Millisecond)
 		checkFile, checkErr := os.OpenFile(drive.LogicalName, os.O_RDONLY, 0)
@@ -53,7 +80,7 @@ func ApplyWatermarks(drive *common.Drive) (func() error, error) {
 			}
 
 			// Read the watermark written to the position
-			currentValue := make([]byte, watermarkSize)
+			currentValue := make([]byte, len(watermark.data))
 			_, checkErr = io.ReadFull(checkFile, currentValue)
 			if checkErr != nil {
 				return fmt.Errorf("read watermark %s@%d(mark=%d): %w", drive.LogicalName, watermark.position, i, checkErr)
@@ -66,18 +93,6 @@ func ApplyWatermarks(drive *common.Drive) (func() error, error) {
 		}
 		return nil
 	}
-	// We introduce a 500-millisecond delay to give the OS enough time to properly flush the disk buffers to disk.
-	// While this delay helps ensure that the data is written, it is not an ideal solution, and further investigation is needed to find more efficient synchronization mechanisms.
-	err = file.Sync()
-	if err != nil {
-		return nil, err
-	}
-	err = file.Close()
-	if err != nil {
-		return nil, err
-	}
-	time.Sleep(500 * time.Millisecond)
-	return checker, nil
 }
 
 // writeWatermarks creates random watermarks and writes them randomlyish throughout the given file.
@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	readbackBlockSize = 1024 * 1024
+
+	// the number of mismatched offsets collected before the read back is stopped
+	readbackMaxMismatches = 16
+)
+
+// WipeReadback configures reading back the drive after a wipe,
+// to verify the drive contents match the expected post wipe pattern.
+type WipeReadback struct {
+	// SamplePercent is the percentage of the drive blocks to read back,
+	// the whole drive is read back when set to 0 or 100.
+	SamplePercent int
+}
+
+// WipeVerifyError is returned when the data read back from a wiped drive does not match the expected pattern
+type WipeVerifyError struct {
+	LogicalName string
+	// Offsets of the first mismatched byte in each of the mismatched blocks
+	Offsets []int64
+}
+
+// Error implements the error interface
+func (e *WipeVerifyError) Error() string {
+	return fmt.Sprintf("verify wipe %s, data does not match the expected pattern at offsets %v: %s", e.LogicalName, e.Offsets, ErrIneffectiveWipe)
+}
+
+// Unwrap returns ErrIneffectiveWipe
+func (e *WipeVerifyError) Unwrap() error {
+	return ErrIneffectiveWipe
+}
+
+// sampled returns true if the block is to be read back,
+// the first and last blocks of the drive are always read back.
+func (r *WipeReadback) sampled(block, blocks int64) bool {
+	if r.SamplePercent <= 0 || r.SamplePercent >= 100 || block == 0 || block == blocks-1 {
+		return true
+	}
+
+	// nolint:gosec // the blocks sampled do not need to be cryptographically random
+	return rand.IntN(100) < r.SamplePercent
+}
+
+// readbackVerify reads back the drive and compares the data with the pattern
+func readbackVerify(ctx context.Context, log *logrus.Entry, logicalName string, fill patternFiller, readback *WipeReadback) error {
+	return verifyWipe(ctx, wipeVerificationReadback, func() error {
+		return readbackBlocks(ctx, log, logicalName, fill, readback)
+	})
+}
+
+// readbackBlocks reads back the drive blocks sampled and compares the data with the pattern
+func readbackBlocks(ctx context.Context, log *logrus.Entry, logicalName string, fill patternFiller, readback *WipeReadback) error {
+	file, err := os.OpenFile(logicalName, os.O_RDONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	partitionSize, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+
+	verifyErr := &WipeVerifyError{LogicalName: logicalName}
+	blocks := (partitionSize + readbackBlockSize - 1) / readbackBlockSize
+
+	var bytesSinceLastPrint int64
+	buffer := make([]byte, readbackBlockSize)
+	expected := make([]byte, readbackBlockSize)
+	start := time.Now()
+	for block := int64(0); block < blocks; block++ {
+		// Check if the context has been canceled
+		select {
+		case <-ctx.Done():
+			log.Debug("stopping")
+			return ctx.Err()
+		default:
+		}
+
+		offset := block * readbackBlockSize
+		l := min(int64(len(buffer)), partitionSize-offset)
+
+		if readback.sampled(block, blocks) {
+			if _, err := file.ReadAt(buffer[:l], offset); err != nil {
+				return err
+			}
+
+			fill(expected[:l], offset)
+			if !bytes.Equal(buffer[:l], expected[:l]) {
+				verifyErr.Offsets = append(verifyErr.Offsets, offset+int64(mismatchIndex(buffer[:l], expected[:l])))
+				if len(verifyErr.Offsets) == readbackMaxMismatches {
+					break
+				}
+			}
+
+			bytesSinceLastPrint += l
+		}
+
+		// Print progress report every 10 seconds and when done
+		if block == blocks-1 || time.Since(start) >= 10*time.Second {
+			printProgress(log, offset+l, partitionSize, start, bytesSinceLastPrint)
+			start = time.Now()
+			bytesSinceLastPrint = 0
+		}
+	}
+
+	if len(verifyErr.Offsets) > 0 {
+		return verifyErr
+	}
+
+	return nil
+}
+
+// mismatchIndex returns the index of the first byte that differs
+func mismatchIndex(a, b []byte) int {
+	for i := range a {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+
+	return len(a)
+}
@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_readbackVerify(t *testing.T) {
+	size := int64(4*readbackBlockSize + 512)
+
+	testCases := []struct {
+		name            string
+		samplePercent   int
+		corruptOffsets  []int64
+		expectedOffsets []int64
+	}{
+		{
+			"full read back",
+			0,
+			nil,
+			nil,
+		},
+		{
+			"full read back reports mismatched offsets",
+			100,
+			[]int64{10, 20, readbackBlockSize + 7, size - 1},
+			[]int64{10, readbackBlockSize + 7, size - 1},
+		},
+		{
+			"sampled read back includes the first and last blocks",
+			1,
+			[]int64{3, size - 2},
+			[]int64{3, size - 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			drive := createTestDrive(t)
+
+			data := make([]byte, size)
+			for _, offset := range tc.corruptOffsets {
+				data[offset] = 0xaa
+			}
+
+			require.NoError(t, os.WriteFile(drive.LogicalName, data, 0o600))
+
+			logger, _ := test.NewNullLogger()
+			readback := &WipeReadback{SamplePercent: tc.samplePercent}
+
+			err := readbackVerify(context.Background(), logger.WithField("drive", drive.LogicalName), drive.LogicalName, repeatFiller([]byte{0x00}), readback)
+			if tc.expectedOffsets == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			require.ErrorIs(t, err, ErrIneffectiveWipe)
+
+			verifyErr := &WipeVerifyError{}
+			require.ErrorAs(t, err, &verifyErr)
+			require.Equal(t, tc.expectedOffsets, verifyErr.Offsets)
+		})
+	}
+}
+
+func Test_FillZeroWipeDriveReadback(t *testing.T) {
+	drive := createTestDrive(t)
+	require.NoError(t, os.Truncate(drive.LogicalName, 2*readbackBlockSize))
+
+	zw := NewFillZeroCmd(false)
+	zw.SetReadback(50)
+
+	logger, _ := test.NewNullLogger()
+	require.NoError(t, zw.WipeDrive(context.Background(), logger, drive))
+}
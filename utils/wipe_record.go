@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/metal-toolbox/ironlib/model"
+)
+
+const (
+	wipeVerificationWatermarks = "watermarks"
+	wipeVerificationReadback   = "readback"
+)
+
+type wipeRecordKey struct{}
+
+// WipeRecord collects the wipe methods attempted and the verification results while a drive is wiped,
+// the DriveWiper implementations in this package record to the WipeRecord included in the context.
+type WipeRecord struct {
+	mu            sync.Mutex
+	methods       []model.WipeMethodAttempt
+	verifications []model.WipeVerification
+}
+
+// NewWipeRecordContext returns a context including a new WipeRecord
+func NewWipeRecordContext(ctx context.Context) (context.Context, *WipeRecord) {
+	r := &WipeRecord{}
+
+	return context.WithValue(ctx, wipeRecordKey{}, r), r
+}
+
+// Methods returns the wipe methods attempted in order
+func (r *WipeRecord) Methods() []model.WipeMethodAttempt {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.methods)
+}
+
+// Verifications returns the wipe verification results in order
+func (r *WipeRecord) Verifications() []model.WipeVerification {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.verifications)
+}
+
+func wipeRecordFromContext(ctx context.Context) *WipeRecord {
+	r, _ := ctx.Value(wipeRecordKey{}).(*WipeRecord)
+	return r
+}
+
+// attemptWipeMethod runs the wipe method and records the attempt in the context WipeRecord
+func attemptWipeMethod(ctx context.Context, method string, wipe func() error) error {
+	started := time.Now().UTC()
+	err := wipe()
+
+	r := wipeRecordFromContext(ctx)
+	if r == nil {
+		return err
+	}
+
+	attempt := model.WipeMethodAttempt{Method: method, Started: started, Ended: time.Now().UTC()}
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+
+	r.mu.Lock()
+	r.methods = append(r.methods, attempt)
+	r.mu.Unlock()
+
+	return err
+}
+
+// verifyWipe runs the wipe verification and records the result in the context WipeRecord
+func verifyWipe(ctx context.Context, method string, verify func() error) error {
+	err := verify()
+
+	r := wipeRecordFromContext(ctx)
+	if r == nil {
+		return err
+	}
+
+	result := model.WipeVerification{Method: method, Passed: err == nil}
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	r.mu.Lock()
+	r.verifications = append(r.verifications, result)
+	r.mu.Unlock()
+
+	return err
+}
@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"context"
+	"crypto/rand"
+	"os"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WipeRecordFillZero(t *testing.T) {
+	drive := createTestDrive(t)
+
+	data := make([]byte, 8192)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(drive.LogicalName, data, 0o600))
+
+	ctx, record := NewWipeRecordContext(context.Background())
+
+	zw := NewFillZeroCmd(false)
+	zw.SetReadback(0)
+
+	logger, hook := test.NewNullLogger()
+	defer hook.Reset()
+
+	require.NoError(t, zw.WipeDrive(ctx, logger, drive))
+
+	methods := record.Methods()
+	require.Len(t, methods, 1)
+	require.Equal(t, "zero-fill", methods[0].Method)
+	require.Empty(t, methods[0].Error)
+	require.False(t, methods[0].Ended.Before(methods[0].Started))
+
+	verifications := record.Verifications()
+	require.Len(t, verifications, 2)
+	require.Equal(t, wipeVerificationWatermarks, verifications[0].Method)
+	require.True(t, verifications[0].Passed)
+	require.Equal(t, wipeVerificationReadback, verifications[1].Method)
+	require.True(t, verifications[1].Passed)
+}
+
+func Test_WipeRecordNvmeFormat(t *testing.T) {
+	n := NewFakeNvme()
+	dev := fakeNVMEDrive(t)
+
+	ctx, record := NewWipeRecordContext(context.Background())
+	require.NoError(t, n.Format(ctx, dev, UserDataErase))
+
+	// the attempts are recorded by WipeDrive, Format only records the verifications
+	require.Empty(t, record.Methods())
+
+	verifications := record.Verifications()
+	require.Len(t, verifications, 1)
+	require.Equal(t, wipeVerificationWatermarks, verifications[0].Method)
+	require.True(t, verifications[0].Passed)
+}
+
+func Test_WipeRecordNotInContext(t *testing.T) {
+	err := attemptWipeMethod(context.Background(), "zero-fill", func() error { return ErrIneffectiveWipe })
+	require.ErrorIs(t, err, ErrIneffectiveWipe)
+
+	err = verifyWipe(context.Background(), wipeVerificationReadback, func() error { return nil })
+	require.NoError(t, err)
+}